@@ -0,0 +1,100 @@
+package alerts
+
+import (
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/origin/pkg/monitortestlibrary/alertpolicy"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	helper "github.com/openshift/origin/test/extended/util/prometheus"
+)
+
+// WithPolicy returns the alerts allowed by allowedAlerts plus the allowed alerts in the policy that apply to the
+// phase and job.  Policy entries with a bug are added to the lists with bugs.
+func WithPolicy(
+	allowedAlerts func(featureSet configv1.FeatureSet) (allowedFiringWithBugs, allowedFiring, allowedPendingWithBugs, allowedPending helper.MetricConditions),
+	policy *alertpolicy.AlertPolicy,
+	phase alertpolicy.Phase,
+	jobType *platformidentification.JobType,
+) func(featureSet configv1.FeatureSet) (allowedFiringWithBugs, allowedFiring, allowedPendingWithBugs, allowedPending helper.MetricConditions) {
+
+	return func(featureSet configv1.FeatureSet) (helper.MetricConditions, helper.MetricConditions, helper.MetricConditions, helper.MetricConditions) {
+		firingAlertsWithBugs, allowedFiringAlerts, pendingAlertsWithBugs, allowedPendingAlerts := allowedAlerts(featureSet)
+
+		now := time.Now()
+		for _, allowed := range policy.AllowedAlerts {
+			if !allowed.Applies(now, jobType) || !allowed.AppliesTo(phase, string(featureSet)) {
+				continue
+			}
+			condition := metricConditionFor(allowed)
+			hasBug := len(allowed.Bug) > 0
+			if allowed.Allows(alertpolicy.FiringState) {
+				if hasBug {
+					firingAlertsWithBugs = append(firingAlertsWithBugs, condition)
+				} else {
+					allowedFiringAlerts = append(allowedFiringAlerts, condition)
+				}
+			}
+			if allowed.Allows(alertpolicy.PendingState) {
+				if hasBug {
+					pendingAlertsWithBugs = append(pendingAlertsWithBugs, condition)
+				} else {
+					allowedPendingAlerts = append(allowedPendingAlerts, condition)
+				}
+			}
+		}
+
+		return firingAlertsWithBugs, allowedFiringAlerts, pendingAlertsWithBugs, allowedPendingAlerts
+	}
+}
+
+func metricConditionFor(allowed alertpolicy.AllowedAlert) helper.MetricCondition {
+	selector := map[string]string{}
+	for name, value := range allowed.Selector {
+		selector[name] = value
+	}
+	selector["alertname"] = allowed.AlertName
+	if len(allowed.Namespace) > 0 {
+		selector["namespace"] = allowed.Namespace
+	}
+	condition := helper.MetricCondition{
+		Selector:       selector,
+		AlertName:      allowed.AlertName,
+		AlertNamespace: allowed.Namespace,
+		Text:           allowed.Description(),
+	}
+	// entries with a bug are tracked like the built-in exceptions, see exceptions.Registry, which reports the ones
+	// missing an owner or expiry as invalid.
+	if len(allowed.Bug) > 0 {
+		exception := allowed.Exception
+		condition.Exception = &exception
+	}
+	return condition
+}
+
+// ExpiredPolicyConditions returns the allowed alerts in the policy that would apply to the phase, job, and feature set
+// if they had not expired, split into firing and pending.  Expired entries no longer allow the alert, but their
+// exceptions are registered so the expiry is reported.
+func ExpiredPolicyConditions(
+	policy *alertpolicy.AlertPolicy,
+	phase alertpolicy.Phase,
+	jobType *platformidentification.JobType,
+	featureSet configv1.FeatureSet,
+	now time.Time,
+) (expiredFiring, expiredPending helper.MetricConditions) {
+	for _, allowed := range policy.AllowedAlerts {
+		if !allowed.Expired(now) || !allowed.JobType.Matches(jobType) || !allowed.AppliesTo(phase, string(featureSet)) {
+			continue
+		}
+		condition := metricConditionFor(allowed)
+		exception := allowed.Exception
+		condition.Exception = &exception
+		if allowed.Allows(alertpolicy.FiringState) {
+			expiredFiring = append(expiredFiring, condition)
+		}
+		if allowed.Allows(alertpolicy.PendingState) {
+			expiredPending = append(expiredPending, condition)
+		}
+	}
+	return expiredFiring, expiredPending
+}
@@ -0,0 +1,84 @@
+package dev
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+type buildHistoricalDataOpts struct {
+	artifactDir      string
+	disruptionOutput string
+	alertsOutput     string
+}
+
+func newBuildHistoricalDataCommand() *cobra.Command {
+	o := buildHistoricalDataOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "build-historical-data",
+		Short: "Compute disruption and alert percentiles from job run artifacts on disk",
+		Long: templates.LongDesc(`
+Compute disruption and alert percentiles from a directory of job run artifacts.
+
+Every directory containing a cluster-data*.json file is treated as a single job run. The
+backend-disruption*.json and alerts*.json files next to it are grouped by the job type from
+the cluster data and P50, P75, P95, P99, and JobRuns are computed for every backend and alert.
+
+The output uses the same format as the query_results.json files we generate from BigQuery, so
+it can be used in their place to derive thresholds from your own CI runs.
+`),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(o.artifactDir) == 0 {
+				return fmt.Errorf("--artifact-dir is required")
+			}
+			if len(o.disruptionOutput) == 0 && len(o.alertsOutput) == 0 {
+				return fmt.Errorf("at least one of --disruption-output or --alerts-output is required")
+			}
+
+			logrus.WithField("artifactDir", o.artifactDir).Info("reading job run artifacts")
+			data, err := historicaldata.BuildFromJobArtifacts(o.artifactDir)
+			if err != nil {
+				return err
+			}
+			logrus.Infof("found %d job runs, %d disruption and %d alert entries", data.JobRuns, len(data.Disruptions), len(data.Alerts))
+
+			if len(o.disruptionOutput) > 0 {
+				disruptionJSON, err := historicaldata.DisruptionToJSON(data.Disruptions)
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(o.disruptionOutput, disruptionJSON, 0644); err != nil {
+					return err
+				}
+				logrus.WithField("file", o.disruptionOutput).Info("wrote disruption historical data")
+			}
+			if len(o.alertsOutput) > 0 {
+				alertJSON, err := historicaldata.AlertsToJSON(data.Alerts)
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(o.alertsOutput, alertJSON, 0644); err != nil {
+					return err
+				}
+				logrus.WithField("file", o.alertsOutput).Info("wrote alert historical data")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&o.artifactDir,
+		"artifact-dir", "",
+		"Directory containing the artifacts of one or more job runs. It is searched recursively.")
+	cmd.Flags().StringVar(&o.disruptionOutput,
+		"disruption-output", "",
+		"File to write the disruption percentiles to, in the same format as pkg/monitortestlibrary/allowedbackenddisruption/query_results.json.")
+	cmd.Flags().StringVar(&o.alertsOutput,
+		"alerts-output", "",
+		"File to write the alert percentiles to, in the same format as pkg/monitortestlibrary/allowedalerts/query_results.json.")
+	return cmd
+}
@@ -1,16 +1,21 @@
 package dev
 
 import (
+	"context"
+	"fmt"
 	"io/ioutil"
 	"os"
 
 	configv1 "github.com/openshift/api/config/v1"
 	"github.com/openshift/origin/pkg/alerts"
+	"github.com/openshift/origin/pkg/monitor/intervalquery"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortestlibrary/alertpolicy"
 	"github.com/openshift/origin/pkg/monitortestlibrary/allowedalerts"
 	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 	"github.com/openshift/origin/pkg/monitortests/network/legacynetworkmonitortests"
+	"github.com/openshift/origin/pkg/monitortests/testframework/alertanalyzer"
 	"github.com/openshift/origin/pkg/monitortests/testframework/legacytestframeworkmonitortests"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -27,18 +32,24 @@ func NewDevCommand() *cobra.Command {
 	cmd.AddCommand(
 		newRunAlertInvariantsCommand(),
 		newRunDisruptionInvariantsCommand(),
+		newBuildHistoricalDataCommand(),
 	)
 	return cmd
 }
 
 type alertInvariantOpts struct {
 	intervalsFile string
+	query         string
 	release       string
 	fromRelease   string
 	platform      string
 	architecture  string
 	network       string
 	topology      string
+	alertPolicy   string
+
+	alertSource      string
+	prometheusBinary string
 }
 
 func newRunAlertInvariantsCommand() *cobra.Command {
@@ -55,14 +66,26 @@ a running cluster.
 
 		RunE: func(cmd *cobra.Command, args []string) error {
 			logrus.Info("running alert invariant tests")
+			if len(o.alertSource) > 0 {
+				if err := alertanalyzer.CheckAlertSource(o.alertSource, o.prometheusBinary); err != nil {
+					return err
+				}
+			}
 
 			logrus.WithField("intervalsFile", o.intervalsFile).Info("loading e2e intervals")
-			intervals, err := readIntervalsFromFile(o.intervalsFile)
+			intervals, err := readIntervalsFromFile(o.intervalsFile, o.query)
 			if err != nil {
 				logrus.WithError(err).Fatal("error loading intervals file")
 			}
 			logrus.Infof("loaded %d intervals", len(intervals))
 
+			if len(o.alertSource) > 0 {
+				intervals, err = alertanalyzer.ReplaceAlertIntervalsFromSource(context.Background(), intervals, o.alertSource, o.prometheusBinary)
+				if err != nil {
+					return err
+				}
+			}
+
 			jobType := &platformidentification.JobType{
 				Release:      o.release,
 				FromRelease:  o.fromRelease,
@@ -72,11 +95,22 @@ a running cluster.
 				Topology:     o.topology,
 			}
 
+			if len(o.alertPolicy) > 0 {
+				policy, err := alertpolicy.ReadPolicyFile(o.alertPolicy)
+				if err != nil {
+					return err
+				}
+				alertpolicy.SetPolicy(policy)
+				logrus.Infof("loaded %d allowed alerts and %d alert tests from %s", len(policy.AllowedAlerts), len(policy.AlertTests), o.alertPolicy)
+			}
+
 			logrus.Info("running tests")
 			testCases := legacytestframeworkmonitortests.RunAlertTests(
 				jobType,
 				nil,
-				alerts.AllowedAlertsDuringUpgrade, // NOTE: may someway want a cli flag for conformance variant
+				// NOTE: may someway want a cli flag for conformance variant
+				alerts.WithPolicy(alerts.AllowedAlertsDuringUpgrade, alertpolicy.CurrentPolicy(), alertpolicy.UpgradePhase, jobType),
+				alertpolicy.UpgradePhase,
 				configv1.Default,
 				allowedalerts.DefaultAllowances,
 				intervals,
@@ -94,6 +128,18 @@ a running cluster.
 	cmd.Flags().StringVar(&o.intervalsFile,
 		"intervals-file", "e2e-events.json",
 		"Path to an intervals file (i.e. e2e-events_20230214-203340.json). Can be obtained from a CI run in openshift-tests junit artifacts.")
+	cmd.Flags().StringVar(&o.query,
+		"query", "",
+		"Only use intervals matching this expression, for instance 'source=Alert and locator.namespace=~\"^openshift-\"'.")
+	cmd.Flags().StringVar(&o.alertPolicy,
+		"alert-policy", "",
+		"Path to a yaml or json AlertPolicy file with allowed alerts and alert tests to apply on top of the built-in ones.")
+	cmd.Flags().StringVar(&o.alertSource,
+		"alert-source", "",
+		"Rebuild the alert intervals instead of using the ones in the intervals file. Either a prometheus remote-read URL (i.e. http://localhost:9090/api/v1/read) or a prometheus TSDB directory or tarball collected from the cluster, which needs --prometheus-binary.")
+	cmd.Flags().StringVar(&o.prometheusBinary,
+		"prometheus-binary", "prometheus",
+		"The prometheus executable used to serve a TSDB --alert-source locally, looked up on the PATH. It is not part of the tests image, install it where the artifacts are processed.")
 	cmd.Flags().StringVar(
 		&o.platform,
 		"platform", "gcp",
@@ -121,7 +167,12 @@ a running cluster.
 	return cmd
 }
 
-func readIntervalsFromFile(intervalsFile string) (monitorapi.Intervals, error) {
+func readIntervalsFromFile(intervalsFile, query string) (monitorapi.Intervals, error) {
+	queryFilter, err := intervalquery.Compile(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --query: %w", err)
+	}
+
 	jsonFile, err := os.Open(intervalsFile)
 	if err != nil {
 		return nil, err
@@ -133,7 +184,11 @@ func readIntervalsFromFile(intervalsFile string) (monitorapi.Intervals, error) {
 		return nil, err
 	}
 
-	return monitorserialization.IntervalsFromJSON(jsonBytes)
+	intervals, err := monitorserialization.IntervalsFromJSON(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	return intervals.Filter(queryFilter), nil
 }
 
 func newRunDisruptionInvariantsCommand() *cobra.Command {
@@ -153,7 +208,7 @@ a running cluster.
 			logrus.Info("running some disruption invariant tests (where possible)")
 
 			logrus.WithField("intervalsFile", opts.intervalsFile).Info("loading e2e intervals")
-			intervals, err := readIntervalsFromFile(opts.intervalsFile)
+			intervals, err := readIntervalsFromFile(opts.intervalsFile, opts.query)
 			if err != nil {
 				logrus.WithError(err).Fatal("error loading intervals file")
 			}
@@ -178,6 +233,9 @@ a running cluster.
 	cmd.Flags().StringVar(&opts.intervalsFile,
 		"intervals-file", "e2e-events.json",
 		"Path to an intervals file (i.e. e2e-events_20230214-203340.json). Can be obtained from a CI run in openshift-tests junit artifacts.")
+	cmd.Flags().StringVar(&opts.query,
+		"query", "",
+		"Only use intervals matching this expression, for instance 'source=Alert and locator.namespace=~\"^openshift-\"'.")
 	cmd.Flags().StringVar(
 		&opts.platform,
 		"platform", "gcp",
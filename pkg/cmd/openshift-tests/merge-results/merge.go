@@ -0,0 +1,170 @@
+package merge_results
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	testginkgo "github.com/openshift/origin/pkg/test/ginkgo"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// shardProperties are junit suite properties that describe a single shard and make no sense once merged.
+var shardProperties = map[string]bool{
+	"ShardIndex": true,
+	"ShardCount": true,
+}
+
+// shardJUnitSuites are the junit suites written by one shard.
+type shardJUnitSuites struct {
+	// shard is the ShardIndex of the suites, or the position of the shard directory when it is missing.
+	shard  string
+	suites []*junitapi.JUnitTestSuite
+}
+
+// mergeJUnitSuites combines suites with the same name into one.  Every shard runs against its own cluster, so only
+// the test cases of e2e tests that ran in a single shard are merged.  Test cases found in more than one shard, like
+// the monitor and synthetic tests and the [Early] and [Late] tests that run on every shard, describe each cluster
+// separately and are kept in a suite per shard, so a failure on one cluster is not hidden as a flake by a pass on
+// another.  Shards run side by side, so the merged duration is the longest shard rather than the sum.
+func mergeJUnitSuites(shards []shardJUnitSuites) []*junitapi.JUnitTestSuite {
+	shardsByTestName := map[string]map[string]bool{}
+	for _, shard := range shards {
+		for _, suite := range shard.suites {
+			for _, testCase := range suite.TestCases {
+				key := suite.Name + "\x00" + testCase.Name
+				if shardsByTestName[key] == nil {
+					shardsByTestName[key] = map[string]bool{}
+				}
+				shardsByTestName[key][shard.shard] = true
+			}
+		}
+	}
+
+	ret := []*junitapi.JUnitTestSuite{}
+	byName := map[string]*junitapi.JUnitTestSuite{}
+	getSuite := func(name string, from *junitapi.JUnitTestSuite) *junitapi.JUnitTestSuite {
+		suite, ok := byName[name]
+		if !ok {
+			suite = &junitapi.JUnitTestSuite{Name: name}
+			for _, property := range from.Properties {
+				if !shardProperties[property.Name] {
+					suite.Properties = append(suite.Properties, property)
+				}
+			}
+			byName[name] = suite
+			ret = append(ret, suite)
+		}
+		if from.Duration > suite.Duration {
+			suite.Duration = from.Duration
+		}
+		return suite
+	}
+
+	for _, shard := range shards {
+		for _, suite := range shard.suites {
+			merged := getSuite(suite.Name, suite)
+			for _, testCase := range suite.TestCases {
+				target := merged
+				if len(shardsByTestName[suite.Name+"\x00"+testCase.Name]) > 1 {
+					target = getSuite(fmt.Sprintf("%s-shard-%s", suite.Name, shard.shard), suite)
+				}
+				addTestCase(target, testCase)
+			}
+		}
+	}
+
+	// suites whose tests all ran in every shard have nothing left to merge.
+	nonEmpty := []*junitapi.JUnitTestSuite{}
+	for _, suite := range ret {
+		if len(suite.TestCases) > 0 {
+			nonEmpty = append(nonEmpty, suite)
+		}
+	}
+	return nonEmpty
+}
+
+func addTestCase(suite *junitapi.JUnitTestSuite, testCase *junitapi.JUnitTestCase) {
+	suite.NumTests++
+	switch {
+	case testCase.FailureOutput != nil:
+		suite.NumFailed++
+	case testCase.SkipMessage != nil:
+		suite.NumSkipped++
+	}
+	suite.TestCases = append(suite.TestCases, testCase)
+}
+
+// readJUnitSuites reads every junit*.xml file in each shard directory.  Nested suites are flattened.
+func readJUnitSuites(dirs []string) ([]shardJUnitSuites, error) {
+	ret := []shardJUnitSuites{}
+	for i, dir := range dirs {
+		shard := shardJUnitSuites{shard: strconv.Itoa(i)}
+		filenames, err := filepath.Glob(filepath.Join(dir, "junit*.xml"))
+		if err != nil {
+			return nil, err
+		}
+		for _, filename := range filenames {
+			suites, err := testginkgo.ReadJUnitSuites(filename)
+			if err != nil {
+				return nil, err
+			}
+			for _, suite := range suites {
+				// the nested suites are already in the list on their own.
+				suite.Children = nil
+				for _, property := range suite.Properties {
+					if property.Name == "ShardIndex" {
+						shard.shard = property.Value
+					}
+				}
+				shard.suites = append(shard.suites, suite)
+			}
+		}
+		ret = append(ret, shard)
+	}
+	return ret, nil
+}
+
+// mergeIntervals reads every e2e-events*.json file in the shard directories into one sorted list.  Every shard
+// runs against its own cluster, so each interval is located in its shard with LocatorShardKey, which keeps the
+// clusters on their own rows of the timeline.  shards names the shard of each directory.  Identical intervals
+// within a shard are only kept once.
+func mergeIntervals(dirs, shards []string) (monitorapi.Intervals, error) {
+	ret := monitorapi.Intervals{}
+	seen := map[string]bool{}
+	for i, dir := range dirs {
+		filenames, err := filepath.Glob(filepath.Join(dir, "e2e-events*.json"))
+		if err != nil {
+			return nil, err
+		}
+		for _, filename := range filenames {
+			intervals, err := monitorserialization.EventsFromFile(filename)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %q: %w", filename, err)
+			}
+			for _, interval := range intervals {
+				keys := map[monitorapi.LocatorKey]string{}
+				for k, v := range interval.StructuredLocator.Keys {
+					keys[k] = v
+				}
+				keys[monitorapi.LocatorShardKey] = shards[i]
+				interval.StructuredLocator.Keys = keys
+
+				key, err := monitorserialization.IntervalToOneLineJSON(interval)
+				if err != nil {
+					return nil, err
+				}
+				if seen[string(key)] {
+					continue
+				}
+				seen[string(key)] = true
+				ret = append(ret, interval)
+			}
+		}
+	}
+	sort.Sort(ret)
+	return ret, nil
+}
@@ -0,0 +1,154 @@
+package merge_results
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/riskanalysis"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+type MergeResultsFlags struct {
+	OutputDir string
+
+	genericclioptions.IOStreams
+}
+
+func NewMergeResultsFlags(streams genericclioptions.IOStreams) *MergeResultsFlags {
+	return &MergeResultsFlags{
+		IOStreams: streams,
+	}
+}
+
+func NewMergeResultsCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	f := NewMergeResultsFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:   "merge-results SHARD_JUNIT_DIR...",
+		Short: "Merge the results of a suite run in shards into one report",
+		Long: templates.LongDesc(`
+		Merge the --junit-dir of every shard of a suite run with --shard-index and --shard-count into
+		one report.
+
+		Suites with the same name in the junit*.xml files are combined into junit_merged.xml.  Every
+		shard runs against its own cluster, so tests found in more than one shard, like the monitor
+		and synthetic tests, are kept in a <suite>-shard-<index> suite per shard instead of being
+		merged.  The e2e-events*.json interval files are combined into e2e-events_merged.json with
+		every interval located in its shard.  The test-failures-summary*.json files are combined into
+		test-failures-summary_merged.json so risk-analysis can run against the output directory.
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.Validate(args); err != nil {
+				return err
+			}
+			o, err := f.ToOptions(args)
+			if err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *MergeResultsFlags) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&f.OutputDir, "output-dir", f.OutputDir, "The directory where the merged results are written. It must not be one of the shard directories.")
+}
+
+func (f *MergeResultsFlags) Validate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("at least one shard junit directory is required")
+	}
+	if len(f.OutputDir) == 0 {
+		return fmt.Errorf("missing --output-dir")
+	}
+	outputDir, err := filepath.Abs(f.OutputDir)
+	if err != nil {
+		return err
+	}
+	for _, arg := range args {
+		shardDir, err := filepath.Abs(arg)
+		if err != nil {
+			return err
+		}
+		if shardDir == outputDir {
+			return fmt.Errorf("--output-dir %q must not be one of the shard directories", f.OutputDir)
+		}
+	}
+	return nil
+}
+
+func (f *MergeResultsFlags) ToOptions(args []string) (*MergeResultsOptions, error) {
+	return &MergeResultsOptions{
+		ShardDirs: args,
+		OutputDir: f.OutputDir,
+		IOStreams: f.IOStreams,
+	}, nil
+}
+
+type MergeResultsOptions struct {
+	ShardDirs []string
+	OutputDir string
+
+	genericclioptions.IOStreams
+}
+
+func (o *MergeResultsOptions) Run() error {
+	if err := os.MkdirAll(o.OutputDir, 0755); err != nil {
+		return err
+	}
+
+	shards, err := readJUnitSuites(o.ShardDirs)
+	if err != nil {
+		return err
+	}
+	shardNames := []string{}
+	numSuites := 0
+	for _, shard := range shards {
+		shardNames = append(shardNames, shard.shard)
+		numSuites += len(shard.suites)
+	}
+	mergedSuites := mergeJUnitSuites(shards)
+	if len(mergedSuites) > 0 {
+		out, err := xml.MarshalIndent(&junitapi.JUnitTestSuites{Suites: mergedSuites}, "", "    ")
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(o.OutputDir, "junit_merged.xml")
+		if err := os.WriteFile(path, out, 0640); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "Merged %d junit suites into %d in %s\n", numSuites, len(mergedSuites), path)
+	}
+
+	intervals, err := mergeIntervals(o.ShardDirs, shardNames)
+	if err != nil {
+		return err
+	}
+	if len(intervals) > 0 {
+		path := filepath.Join(o.OutputDir, "e2e-events_merged.json")
+		if err := monitorserialization.EventsToFile(path, intervals); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "Merged %d intervals into %s\n", len(intervals), path)
+	}
+
+	if err := riskanalysis.MergeTestFailureSummaries(o.ShardDirs, o.OutputDir); err != nil {
+		return fmt.Errorf("failed to merge test failure summaries: %w", err)
+	}
+
+	return nil
+}
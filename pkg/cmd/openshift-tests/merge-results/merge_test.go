@@ -0,0 +1,91 @@
+package merge_results
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+func TestMergeJUnitSuites(t *testing.T) {
+	shard0 := &junitapi.JUnitTestSuite{
+		Name:       "openshift-tests",
+		NumTests:   3,
+		NumFailed:  2,
+		Duration:   100,
+		Properties: []*junitapi.TestSuiteProperty{{Name: "TestVersion", Value: "v1"}, {Name: "ShardIndex", Value: "0"}},
+		TestCases: []*junitapi.JUnitTestCase{
+			{Name: "a"},
+			{Name: "b", FailureOutput: &junitapi.FailureOutput{}},
+			{Name: "invariant", FailureOutput: &junitapi.FailureOutput{}},
+		},
+	}
+	shard1 := &junitapi.JUnitTestSuite{
+		Name:       "openshift-tests",
+		NumTests:   3,
+		NumSkipped: 1,
+		Duration:   150,
+		Properties: []*junitapi.TestSuiteProperty{{Name: "TestVersion", Value: "v1"}, {Name: "ShardIndex", Value: "1"}},
+		TestCases: []*junitapi.JUnitTestCase{
+			{Name: "c"},
+			{Name: "d", SkipMessage: &junitapi.SkipMessage{}},
+			{Name: "invariant"},
+		},
+	}
+	monitor := &junitapi.JUnitTestSuite{
+		Name:      "invariants",
+		NumTests:  1,
+		Duration:  10,
+		TestCases: []*junitapi.JUnitTestCase{{Name: "e"}},
+	}
+	monitor1 := &junitapi.JUnitTestSuite{
+		Name:      "invariants",
+		NumTests:  1,
+		Duration:  10,
+		TestCases: []*junitapi.JUnitTestCase{{Name: "e", FailureOutput: &junitapi.FailureOutput{}}},
+	}
+
+	actual := mergeJUnitSuites([]shardJUnitSuites{
+		{shard: "0", suites: []*junitapi.JUnitTestSuite{shard0, monitor}},
+		{shard: "1", suites: []*junitapi.JUnitTestSuite{shard1, monitor1}},
+	})
+	suites := map[string]*junitapi.JUnitTestSuite{}
+	names := []string{}
+	for _, suite := range actual {
+		suites[suite.Name] = suite
+		names = append(names, suite.Name)
+	}
+	expectedNames := []string{"openshift-tests", "openshift-tests-shard-0", "invariants-shard-0", "openshift-tests-shard-1", "invariants-shard-1"}
+	if !reflect.DeepEqual(expectedNames, names) {
+		t.Fatalf("expected suites %v, got %v", expectedNames, names)
+	}
+
+	merged := suites["openshift-tests"]
+	if merged.NumTests != 4 || merged.NumFailed != 1 || merged.NumSkipped != 1 {
+		t.Errorf("unexpected counts in merged suite: %#v", merged)
+	}
+	if merged.Duration != 150 {
+		t.Errorf("expected the longest shard duration, got %v", merged.Duration)
+	}
+	if !reflect.DeepEqual(merged.Properties, []*junitapi.TestSuiteProperty{{Name: "TestVersion", Value: "v1"}}) {
+		t.Errorf("expected shard properties to be dropped, got %v", merged.Properties)
+	}
+	testNames := []string{}
+	for _, testCase := range merged.TestCases {
+		testNames = append(testNames, testCase.Name)
+	}
+	if !reflect.DeepEqual(testNames, []string{"a", "b", "c", "d"}) {
+		t.Errorf("unexpected merged test cases: %v", testNames)
+	}
+
+	// the invariant failed on the cluster of shard 0, a pass on shard 1 must not turn it into a flake.
+	if suite := suites["openshift-tests-shard-0"]; len(suite.TestCases) != 1 || suite.NumFailed != 1 {
+		t.Errorf("expected the failing invariant of shard 0 in its own suite, got %#v", suite)
+	}
+	if suite := suites["openshift-tests-shard-1"]; len(suite.TestCases) != 1 || suite.NumFailed != 0 {
+		t.Errorf("expected the passing invariant of shard 1 in its own suite, got %#v", suite)
+	}
+	if suite := suites["invariants-shard-1"]; suite.NumFailed != 1 {
+		t.Errorf("expected the monitor failure of shard 1 to be kept, got %#v", suite)
+	}
+}
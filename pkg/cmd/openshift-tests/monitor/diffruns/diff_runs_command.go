@@ -0,0 +1,144 @@
+package diffruns
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/intervaldiff"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+type DiffRunsFlags struct {
+	OutputType string
+	MinLevel   string
+	MinGrowth  time.Duration
+
+	genericclioptions.IOStreams
+}
+
+func NewDiffRunsFlags(streams genericclioptions.IOStreams) *DiffRunsFlags {
+	return &DiffRunsFlags{
+		OutputType: "text",
+		MinLevel:   monitorapi.Warning.String(),
+		MinGrowth:  5 * time.Second,
+		IOStreams:  streams,
+	}
+}
+
+func NewDiffRunsCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	f := NewDiffRunsFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:   "diff-runs A B",
+		Short: "Report the intervals, disruption, alerts, and pathological events that appear or grow in run B",
+		Long: templates.LongDesc(`
+		Compare two intervals files (i.e. e2e-events_20230214-203340.json) from different runs, usually of two
+		payloads, and report what appears or grows in B compared with A: total disruption per backend, the time
+		each alert was pending or firing, the repeat counts of pathological events, and all other intervals at or
+		above --min-level grouped by source, locator, and reason.
+
+		Locators are normalized before they are compared, so UIDs, generated pod name suffixes, and IPs do not
+		make the same thing look different.  Times are reported relative to the start of each run.
+
+		openshift-tests monitor diff-runs -o html e2e-events_good.json e2e-events_bad.json > diff.html
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.Validate(args); err != nil {
+				return err
+			}
+			o, err := f.ToOptions(args)
+			if err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *DiffRunsFlags) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&f.OutputType, "output", "o", f.OutputType, "type of output: [text, json, html]")
+	flags.StringVar(&f.MinLevel, "min-level", f.MinLevel, "Intervals below this level are left out of the intervals section: [Info, Warning, Error]")
+	flags.DurationVar(&f.MinGrowth, "min-growth", f.MinGrowth, "Durations that grow by less than this are not reported.  Anything new in B is always reported.")
+}
+
+func (f *DiffRunsFlags) Validate(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("exactly two intervals files are required, got %d", len(args))
+	}
+	switch f.OutputType {
+	case "text", "json", "html":
+	default:
+		return fmt.Errorf("unknown --output %q", f.OutputType)
+	}
+	if _, err := monitorapi.ConditionLevelFromString(f.MinLevel); err != nil {
+		return fmt.Errorf("invalid --min-level: %w", err)
+	}
+	return nil
+}
+
+func (f *DiffRunsFlags) ToOptions(args []string) (*DiffRunsOptions, error) {
+	runs := []intervaldiff.Run{}
+	for _, filename := range args {
+		intervals, err := monitorserialization.EventsFromFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %q: %w", filename, err)
+		}
+		runs = append(runs, intervaldiff.Run{Name: filename, Intervals: intervals})
+	}
+	minLevel, err := monitorapi.ConditionLevelFromString(f.MinLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DiffRunsOptions{
+		A:          runs[0],
+		B:          runs[1],
+		OutputType: f.OutputType,
+		CompareOptions: intervaldiff.Options{
+			MinLevel:  minLevel,
+			MinGrowth: f.MinGrowth,
+		},
+		IOStreams: f.IOStreams,
+	}, nil
+}
+
+type DiffRunsOptions struct {
+	A              intervaldiff.Run
+	B              intervaldiff.Run
+	OutputType     string
+	CompareOptions intervaldiff.Options
+
+	genericclioptions.IOStreams
+}
+
+func (o *DiffRunsOptions) Run() error {
+	report := intervaldiff.Compare(o.A, o.B, o.CompareOptions)
+
+	var output []byte
+	var err error
+	switch o.OutputType {
+	case "json":
+		output, err = report.ToJSON()
+	case "html":
+		output, err = report.ToHTML()
+	default:
+		return report.WriteText(o.Out)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = o.Out.Write(output)
+	return err
+}
@@ -0,0 +1,196 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/defaultmonitortests"
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortests/testframework/alertanalyzer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+type ReplayFlags struct {
+	IntervalsFile              string
+	ResourceFiles              []string
+	ArtifactDir                string
+	JunitSuiteName             string
+	ClusterStabilityDuringTest string
+	ExactMonitorTests          []string
+	DisableMonitorTests        []string
+	AlertSource                string
+	PrometheusBinary           string
+
+	genericclioptions.IOStreams
+}
+
+func NewReplayFlags(streams genericclioptions.IOStreams) *ReplayFlags {
+	return &ReplayFlags{
+		JunitSuiteName:             "invariants",
+		ClusterStabilityDuringTest: string(monitortestframework.Stable),
+		PrometheusBinary:           "prometheus",
+		IOStreams:                  streams,
+	}
+}
+
+func NewReplayCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	f := NewReplayFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Replay monitor tests against intervals and resources from a previous run",
+		Long: templates.LongDesc(`
+		Replay the monitor tests against an e2e intervals json file and the tracked resource zip files from
+		a previous run.  No cluster is required.  Computed intervals are rebuilt, every monitor test is
+		evaluated, content is written to the artifact directory, and a junit is produced.
+
+		Monitor tests that rely on data gathered from a live cluster during collection will report that
+		through their junits.
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.Validate(); err != nil {
+				return err
+			}
+			o, err := f.ToOptions()
+			if err != nil {
+				return err
+			}
+			return o.Run(context.Background())
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *ReplayFlags) BindFlags(flags *pflag.FlagSet) {
+	monitorNames := defaultmonitortests.ListAllMonitorTests()
+
+	flags.StringVar(&f.IntervalsFile, "intervals-file", f.IntervalsFile, "Path to an intervals file (i.e. e2e-events_20230214-203340.json). Can be obtained from a CI run in openshift-tests junit artifacts.")
+	flags.StringSliceVar(&f.ResourceFiles, "resource-file", f.ResourceFiles, "Path to a tracked resources file (i.e. resource-pods_20230214-203340.zip). May be specified multiple times.")
+	flags.StringVar(&f.ArtifactDir, "artifact-dir", f.ArtifactDir, "The directory where replayed monitor content and junit will be stored.")
+	flags.StringVar(&f.JunitSuiteName, "junit-suite-name", f.JunitSuiteName, "The name of the junit suite to write.")
+	flags.StringVar(&f.ClusterStabilityDuringTest, "cluster-stability", f.ClusterStabilityDuringTest,
+		fmt.Sprintf("The cluster stability of the run that produced the intervals: [%s, %s]", monitortestframework.Stable, monitortestframework.Disruptive))
+	flags.StringSliceVar(&f.ExactMonitorTests, "monitor", f.ExactMonitorTests,
+		fmt.Sprintf("list of exactly which monitors to enable. All others will be disabled.  Current monitors are: [%s]", strings.Join(monitorNames, ", ")))
+	flags.StringSliceVar(&f.DisableMonitorTests, "disable-monitor", f.DisableMonitorTests, "list of monitors to disable.  Defaults for others will be honored.")
+	flags.StringVar(&f.AlertSource, "alert-source", f.AlertSource,
+		"Rebuild the alert intervals instead of using the ones in the intervals file. Either a prometheus remote-read URL (i.e. http://localhost:9090/api/v1/read) or a prometheus TSDB directory or tarball collected from the cluster, which needs --prometheus-binary.")
+	flags.StringVar(&f.PrometheusBinary, "prometheus-binary", f.PrometheusBinary, "The prometheus executable used to serve a TSDB --alert-source locally, looked up on the PATH. It is not part of the tests image, install it where the artifacts are processed.")
+}
+
+func (f *ReplayFlags) Validate() error {
+	if len(f.IntervalsFile) == 0 {
+		return fmt.Errorf("missing --intervals-file")
+	}
+	if len(f.ArtifactDir) == 0 {
+		return fmt.Errorf("missing --artifact-dir")
+	}
+	switch monitortestframework.ClusterStabilityDuringTest(f.ClusterStabilityDuringTest) {
+	case monitortestframework.Stable, monitortestframework.Disruptive:
+	default:
+		return fmt.Errorf("unknown --cluster-stability %q", f.ClusterStabilityDuringTest)
+	}
+	if len(f.AlertSource) > 0 {
+		if err := alertanalyzer.CheckAlertSource(f.AlertSource, f.PrometheusBinary); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *ReplayFlags) ToOptions() (*ReplayOptions, error) {
+	monitorTestInfo := monitortestframework.MonitorTestInitializationInfo{
+		ClusterStabilityDuringTest: monitortestframework.ClusterStabilityDuringTest(f.ClusterStabilityDuringTest),
+		ExactMonitorTests:          f.ExactMonitorTests,
+		DisableMonitorTests:        f.DisableMonitorTests,
+	}
+	monitorTestRegistry, err := defaultmonitortests.NewMonitorTestsFor(monitorTestInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	intervals, err := monitorserialization.EventsFromFile(f.IntervalsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", f.IntervalsFile, err)
+	}
+
+	recordedResources := monitorapi.ResourcesMap{}
+	for _, resourceFile := range f.ResourceFiles {
+		resourceType, instances, err := monitorserialization.InstanceMapFromFile(resourceFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %q: %w", resourceFile, err)
+		}
+		if _, ok := recordedResources[resourceType]; ok {
+			return nil, fmt.Errorf("%q contains %v, which was already loaded from another file", resourceFile, resourceType)
+		}
+		recordedResources[resourceType] = instances
+	}
+
+	if len(f.AlertSource) > 0 {
+		intervals, err = alertanalyzer.ReplaceAlertIntervalsFromSource(context.Background(), intervals, f.AlertSource, f.PrometheusBinary)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ReplayOptions{
+		Intervals:         intervals,
+		RecordedResources: recordedResources,
+		ArtifactDir:       f.ArtifactDir,
+		JunitSuiteName:    f.JunitSuiteName,
+		MonitorTests:      monitorTestRegistry,
+		IOStreams:         f.IOStreams,
+	}, nil
+}
+
+type ReplayOptions struct {
+	Intervals         monitorapi.Intervals
+	RecordedResources monitorapi.ResourcesMap
+	ArtifactDir       string
+	JunitSuiteName    string
+	MonitorTests      monitortestframework.MonitorTestRegistry
+
+	genericclioptions.IOStreams
+}
+
+func (o *ReplayOptions) Run(ctx context.Context) error {
+	if err := os.MkdirAll(o.ArtifactDir, 0755); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "Replaying %d intervals and %d resource types.\n", len(o.Intervals), len(o.RecordedResources))
+
+	m := monitor.NewReplayMonitor(o.Intervals, o.RecordedResources, o.ArtifactDir, o.MonitorTests)
+	if err := m.Start(ctx); err != nil {
+		return err
+	}
+	resultState, err := m.Stop(ctx)
+	if err != nil {
+		return err
+	}
+
+	timeSuffix := fmt.Sprintf("_%s", time.Now().UTC().Format("20060102-150405"))
+	if err := m.SerializeResults(ctx, o.JunitSuiteName, timeSuffix); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Out, "Replay finished: %s\n", resultState)
+	if resultState != monitor.Succeeded {
+		return fmt.Errorf("replayed monitor tests failed")
+	}
+	return nil
+}
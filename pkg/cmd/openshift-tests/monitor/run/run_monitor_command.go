@@ -14,6 +14,7 @@ import (
 	"github.com/openshift/origin/pkg/clioptions/imagesetup"
 	"github.com/openshift/origin/pkg/monitortestframework"
 
+	"github.com/openshift/origin/pkg/monitor/intervalquery"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"github.com/openshift/origin/test/extended/util/image"
 
@@ -30,9 +31,11 @@ import (
 type RunMonitorFlags struct {
 	ArtifactDir         string
 	DisplayFromNow      bool
+	DisplayFilter       string
 	ExactMonitorTests   []string
 	DisableMonitorTests []string
 	FromRepository      string
+	IntervalStorageDir  string
 
 	genericclioptions.IOStreams
 }
@@ -86,10 +89,12 @@ func (f *RunMonitorFlags) BindFlags(flags *pflag.FlagSet) {
 
 	flags.StringVar(&f.ArtifactDir, "artifact-dir", f.ArtifactDir, "The directory where monitor events will be stored.")
 	flags.BoolVar(&f.DisplayFromNow, "display-from-now", f.DisplayFromNow, "Only display intervals from at or after this comand was started.")
+	flags.StringVar(&f.DisplayFilter, "display-filter", f.DisplayFilter, "Only display intervals matching this expression, for instance 'source=Disruption or level>=Warning'.")
 	flags.StringSliceVar(&f.ExactMonitorTests, "monitor", f.ExactMonitorTests,
 		fmt.Sprintf("list of exactly which monitors to enable. All others will be disabled.  Current monitors are: [%s]", strings.Join(monitorNames, ", ")))
 	flags.StringSliceVar(&f.DisableMonitorTests, "disable-monitor", f.DisableMonitorTests, "list of monitors to disable.  Defaults for others will be honored.")
 	flags.StringVar(&f.FromRepository, "from-repository", f.FromRepository, "A container image repository to retrieve test images from.")
+	flags.StringVar(&f.IntervalStorageDir, "interval-storage-dir", f.IntervalStorageDir, "A directory to spill intervals to so memory stays bounded on long runs. Empty keeps all intervals in memory.")
 }
 
 func (f *RunMonitorFlags) ToOptions() (*RunMonitorOptions, error) {
@@ -104,17 +109,30 @@ func (f *RunMonitorFlags) ToOptions() (*RunMonitorOptions, error) {
 		}
 	}
 
+	if len(f.DisplayFilter) > 0 {
+		queryFilterFn, err := intervalquery.Compile(f.DisplayFilter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --display-filter: %w", err)
+		}
+		if displayFilterFn != nil {
+			displayFilterFn = monitorapi.And(displayFilterFn, queryFilterFn)
+		} else {
+			displayFilterFn = queryFilterFn
+		}
+	}
+
 	monitorTestRegistry, err := f.getMonitorTestRegistry()
 	if err != nil {
 		return nil, err
 	}
 
 	return &RunMonitorOptions{
-		ArtifactDir:     f.ArtifactDir,
-		DisplayFilterFn: displayFilterFn,
-		MonitorTests:    monitorTestRegistry,
-		IOStreams:       f.IOStreams,
-		FromRepository:  f.FromRepository,
+		ArtifactDir:        f.ArtifactDir,
+		DisplayFilterFn:    displayFilterFn,
+		MonitorTests:       monitorTestRegistry,
+		IOStreams:          f.IOStreams,
+		FromRepository:     f.FromRepository,
+		IntervalStorageDir: f.IntervalStorageDir,
 	}, nil
 }
 
@@ -128,10 +146,11 @@ func (f *RunMonitorFlags) getMonitorTestRegistry() (monitortestframework.Monitor
 }
 
 type RunMonitorOptions struct {
-	ArtifactDir     string
-	DisplayFilterFn monitorapi.EventIntervalMatchesFunc
-	MonitorTests    monitortestframework.MonitorTestRegistry
-	FromRepository  string
+	ArtifactDir        string
+	DisplayFilterFn    monitorapi.EventIntervalMatchesFunc
+	MonitorTests       monitortestframework.MonitorTestRegistry
+	FromRepository     string
+	IntervalStorageDir string
 
 	genericclioptions.IOStreams
 }
@@ -170,7 +189,14 @@ func (o *RunMonitorOptions) Run() error {
 	}()
 	signal.Notify(abortCh, syscall.SIGINT, syscall.SIGTERM)
 
-	recorder := monitor.WrapWithJSONLRecorder(monitor.NewRecorder(), o.Out, o.DisplayFilterFn)
+	delegateRecorder := monitor.NewRecorder()
+	if len(o.IntervalStorageDir) > 0 {
+		delegateRecorder, err = monitor.NewSegmentedRecorder(o.IntervalStorageDir, monitor.DefaultSegmentSize)
+		if err != nil {
+			return err
+		}
+	}
+	recorder := monitor.WrapWithJSONLRecorder(delegateRecorder, o.Out, o.DisplayFilterFn)
 	m := monitor.NewMonitor(
 		recorder,
 		restConfig,
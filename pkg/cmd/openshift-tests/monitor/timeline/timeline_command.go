@@ -9,8 +9,11 @@ import (
 
 	"github.com/openshift/origin/pkg/monitortests/testframework/timelineserializer"
 
+	"github.com/openshift/origin/pkg/monitor/intervalquery"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/test/ginkgo"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 	"github.com/openshift/origin/test/extended/testdata"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -21,10 +24,12 @@ import (
 type TimelineOptions struct {
 	MonitorEventFilename string
 	PodResourceFilename  string
+	JUnitFilenames       []string
 	TimelineType         string
 
 	LocatorMatchers []string
 	Namespaces      []string
+	Query           string
 	OutputType      string
 	EndDate         string
 
@@ -36,15 +41,16 @@ type TimelineOptions struct {
 type RenderFunc func(intervals monitorapi.Intervals) ([]byte, error)
 
 func NewTimelineOptions(ioStreams genericclioptions.IOStreams) *TimelineOptions {
-	return &TimelineOptions{
+	o := &TimelineOptions{
 		TimelineType: "spyglass",
 
 		OutputType: "html",
 
 		IOStreams: ioStreams,
 		KnownRenderers: map[string]RenderFunc{
-			"json": monitorserialization.IntervalsToJSON,
-			"html": renderHTML,
+			"json":         monitorserialization.IntervalsToJSON,
+			"html":         renderHTML,
+			"chrome-trace": monitorserialization.IntervalsToChromeTrace,
 		},
 		KnownTimelines: map[string]monitorapi.EventIntervalMatchesFunc{
 			"everything":    timelineserializer.BelongsInEverything,
@@ -54,6 +60,8 @@ func NewTimelineOptions(ioStreams genericclioptions.IOStreams) *TimelineOptions
 			"pod-lifecycle": timelineserializer.IsOriginalPodEvent, // TODO: may not be used?
 		},
 	}
+	o.KnownRenderers["viewer"] = o.renderViewer
+	return o
 }
 
 func NewTimelineCommand(ioStreams genericclioptions.IOStreams) *cobra.Command {
@@ -66,6 +74,17 @@ func NewTimelineCommand(ioStreams genericclioptions.IOStreams) *cobra.Command {
 		Create a timeline html page based on the provided monitor events.
 
 		openshift-tests timeline --type=pod -f raw-monitor-events.json --namespace=openshift-kube-apiserver --namespace=openshift-kube-apiserver-operator -ojson 
+
+		-oviewer produces a single interactive page with filtering, zooming, and search that links intervals to
+		the junit failures from --junit and the pods from --known-pods.
+
+		openshift-tests timeline --type=everything -f e2e-events.json --junit=junit_e2e.xml --known-pods=resource-pods.zip -oviewer > timeline.html
+
+		-ochrome-trace produces Chrome Trace Event json that https://ui.perfetto.dev and chrome://tracing load.  Each
+		locator type is a process and each locator is a thread, with the annotations as args.  Timestamps are since
+		the unix epoch, so the intervals line up with other traces of the same run.
+
+		openshift-tests timeline --type=everything -f e2e-events.json -ochrome-trace > intervals.trace.json
 		`,
 
 		SilenceUsage:  true,
@@ -95,7 +114,9 @@ func (o *TimelineOptions) Bind(flagset *pflag.FlagSet) error {
 	flagset.StringVarP(&o.OutputType, "output", "o", o.OutputType, fmt.Sprintf("type of output: [%s]", strings.Join(sets.StringKeySet(o.KnownRenderers).List(), ",")))
 	flagset.StringVar(&o.TimelineType, "type", o.TimelineType, "type of timeline to produce: "+strings.Join(sets.StringKeySet(o.KnownTimelines).List(), ","))
 	flagset.StringVar(&o.PodResourceFilename, "known-pods", o.PodResourceFilename, "resource-pods_<timestamp>.zip filename from openshift-tests.")
+	flagset.StringSliceVar(&o.JUnitFilenames, "junit", o.JUnitFilenames, "junit xml files whose failures are linked from -oviewer.  May be specified multiple times.")
 	flagset.StringSliceVarP(&o.LocatorMatchers, "locator", "l", o.LocatorMatchers, "key=value selector for monitor event locators (where value is a regex).  for instance -lpod=openshift-etcd-installer.  The same key listed multiple times means an OR.  Each separate key is logically ANDed.  Precede value with a dash for anti-match")
+	flagset.StringVarP(&o.Query, "query", "q", o.Query, "expression over interval fields to filter on.  for instance -q 'source=Alert and (level>=Warning or locator.namespace=~\"^openshift-etcd\")'.  Fields: source, level, reason, cause, message, display, duration, from, to, locator, locator.type, locator.<key>, annotation.<key>")
 	flagset.StringVarP(&o.EndDate, "end-date", "e", o.EndDate, fmt.Sprintf("Stop date (default is one hour after latest event) in RFC3399 format in UTC timezone: %s", time.RFC3339))
 
 	return nil
@@ -129,6 +150,10 @@ func (o *TimelineOptions) Validate() error {
 		}
 	}
 
+	if _, err := intervalquery.Compile(o.Query); err != nil {
+		return fmt.Errorf("invalid --query: %w", err)
+	}
+
 	if len(o.EndDate) > 0 {
 		_, err := time.ParseInLocation(time.RFC3339, o.EndDate, time.UTC)
 		if err != nil {
@@ -170,6 +195,7 @@ func (o *TimelineOptions) ToTimeline() *Timeline {
 		LocatorMatcher:        locatorMatcher,
 		RemovedLocatorMatcher: inverseLocatorMatcher,
 		Namespaces:            o.Namespaces,
+		QueryFilter:           intervalquery.MustCompile(o.Query),
 		EndDate:               endDateTime,
 
 		Renderer:       o.KnownRenderers[o.OutputType],
@@ -185,6 +211,7 @@ type Timeline struct {
 	LocatorMatcher        map[string][]*regexp.Regexp
 	RemovedLocatorMatcher map[string][]*regexp.Regexp
 	Namespaces            []string
+	QueryFilter           monitorapi.EventIntervalMatchesFunc
 	EndDate               *time.Time
 
 	Renderer       RenderFunc
@@ -210,6 +237,9 @@ func (o *Timeline) Run() error {
 	if len(o.RemovedLocatorMatcher) > 0 {
 		filteredEvents = filteredEvents.Filter(monitorapi.NotContainsAllParts(o.RemovedLocatorMatcher))
 	}
+	if o.QueryFilter != nil {
+		filteredEvents = filteredEvents.Filter(o.QueryFilter)
+	}
 	// compute intervals from raw
 	var to time.Time
 
@@ -241,6 +271,30 @@ func (o *Timeline) Run() error {
 	return nil
 }
 
+func (o *TimelineOptions) renderViewer(events monitorapi.Intervals) ([]byte, error) {
+	junits := []*junitapi.JUnitTestCase{}
+	for _, filename := range o.JUnitFilenames {
+		suites, err := ginkgo.ReadJUnitSuites(filename)
+		if err != nil {
+			return nil, err
+		}
+		for _, suite := range suites {
+			junits = append(junits, suite.TestCases...)
+		}
+	}
+
+	resources := monitorapi.ResourcesMap{}
+	if len(o.PodResourceFilename) > 0 {
+		resourceType, instances, err := monitorserialization.InstanceMapFromFile(o.PodResourceFilename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %q: %w", o.PodResourceFilename, err)
+		}
+		resources[resourceType] = instances
+	}
+
+	return timelineserializer.RenderTimelineViewer("Timeline", events, junits, resources)
+}
+
 func renderHTML(events monitorapi.Intervals) ([]byte, error) {
 	eventIntervalsJSON, err := monitorserialization.EventsIntervalsToJSON(events)
 	if err != nil {
@@ -21,6 +21,14 @@ files after an invocation of openshift-tests. If multiple files are present
 Results are then submitted to sippy which will return an analysis of per-test
 and overall risk level given historical pass rates on the failed tests.
 The resulting analysis is then also written to the junit artifacts directory.
+
+When --pass-rate-file is set the analysis is computed locally from a json list of
+historical per-test, per-job-type pass, failure, and flake counts instead of
+contacting sippy:
+
+  [{"TestName": "...", "Release": "4.16", "FromRelease": "", "Platform": "aws",
+    "Architecture": "amd64", "Network": "ovn", "Topology": "ha",
+    "Passes": 120, "Failures": 1, "Flakes": 2}]
 `),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -34,5 +42,8 @@ The resulting analysis is then also written to the junit artifacts directory.
 	cmd.Flags().StringVar(&riskAnalysisOpts.SippyURL,
 		"sippy-url", sippyDefaultURL,
 		"Sippy URL API endpoint")
+	cmd.Flags().StringVar(&riskAnalysisOpts.PassRateFile,
+		"pass-rate-file", riskAnalysisOpts.PassRateFile,
+		"A json file of historical test pass rates per job type. When set, the risk analysis is computed locally and sippy is not contacted.")
 	return cmd
 }
@@ -21,6 +21,8 @@ import (
 	"k8s.io/kubectl/pkg/util/templates"
 
 	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/backend/sampler"
+	disruptionci "github.com/openshift/origin/pkg/disruption/ci"
 	"github.com/openshift/origin/pkg/monitor"
 	"github.com/openshift/origin/pkg/monitor/apiserveravailability"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
@@ -35,12 +37,22 @@ type RunAPIDisruptionMonitorOptions struct {
 	ArtifactDir      string
 	LoadBalancerType string
 	ExtraMessage     string
+
+	// ConfigFile is the in-cluster monitor configuration, and Poller the
+	// poller of that configuration whose backends are sampled.
+	ConfigFile string
+	Poller     string
+
+	// DegradedLatencyThreshold is the round trip latency above which a
+	// successful sample is deemed degraded.
+	DegradedLatencyThreshold time.Duration
 }
 
 func NewRunInClusterDisruptionMonitorOptions(ioStreams genericclioptions.IOStreams) *RunAPIDisruptionMonitorOptions {
 	return &RunAPIDisruptionMonitorOptions{
-		Out:    ioStreams.Out,
-		ErrOut: ioStreams.ErrOut,
+		Out:                      ioStreams.Out,
+		ErrOut:                   ioStreams.ErrOut,
+		DegradedLatencyThreshold: disruptionci.DefaultDegradedLatencyThreshold,
 	}
 }
 
@@ -68,6 +80,15 @@ func NewRunInClusterDisruptionMonitorCommand(ioStreams genericclioptions.IOStrea
 	cmd.Flags().StringVar(&disruptionOpt.ExtraMessage,
 		"extra-message", disruptionOpt.ExtraMessage,
 		"Add custom label to disruption event message")
+	cmd.Flags().StringVar(&disruptionOpt.ConfigFile,
+		"config", disruptionOpt.ConfigFile,
+		"The in-cluster monitor configuration, if set the backends of --poller are sampled and --lb-type is ignored.")
+	cmd.Flags().StringVar(&disruptionOpt.Poller,
+		"poller", disruptionOpt.Poller,
+		"The name of the poller in --config to run.")
+	cmd.Flags().DurationVar(&disruptionOpt.DegradedLatencyThreshold,
+		"degraded-latency-threshold", disruptionOpt.DegradedLatencyThreshold,
+		"The round trip latency above which a successful sample is deemed degraded, 0 disables the degraded intervals. A backend of --config may set its own.")
 	return cmd
 }
 
@@ -77,7 +98,20 @@ func (opt *RunAPIDisruptionMonitorOptions) Run() error {
 		return err
 	}
 
+	if opt.DegradedLatencyThreshold < 0 {
+		return fmt.Errorf("--degraded-latency-threshold must not be negative")
+	}
 	lb := backend.ParseStringToLoadBalancerType(opt.LoadBalancerType)
+	var poller *sampler.InClusterPoller
+	if len(opt.ConfigFile) > 0 {
+		monitors, err := sampler.LoadInClusterMonitorConfiguration(opt.ConfigFile)
+		if err != nil {
+			return err
+		}
+		if poller, err = monitors.Poller(opt.Poller); err != nil {
+			return err
+		}
+	}
 
 	ctx, cancelFn := context.WithCancel(context.Background())
 	defer cancelFn()
@@ -99,7 +133,12 @@ func (opt *RunAPIDisruptionMonitorOptions) Run() error {
 	}()
 	signal.Notify(abortCh, syscall.SIGINT, syscall.SIGTERM)
 
-	recorder, err := StartAPIAvailability(ctx, restConfig, lb)
+	var recorder monitorapi.Recorder
+	if poller != nil {
+		recorder, err = StartPollerAvailability(ctx, restConfig, poller, opt.DegradedLatencyThreshold)
+	} else {
+		recorder, err = StartAPIAvailability(ctx, restConfig, lb, opt.DegradedLatencyThreshold)
+	}
 	if err != nil {
 		return err
 	}
@@ -156,14 +195,14 @@ func (opt *RunAPIDisruptionMonitorOptions) Run() error {
 }
 
 // StartAPIAvailability monitors just the cluster availability
-func StartAPIAvailability(ctx context.Context, restConfig *rest.Config, lb backend.LoadBalancerType) (monitorapi.Recorder, error) {
+func StartAPIAvailability(ctx context.Context, restConfig *rest.Config, lb backend.LoadBalancerType, degradedLatencyThreshold time.Duration) (monitorapi.Recorder, error) {
 	recorder := monitor.NewRecorder()
 
 	client, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
 		return nil, err
 	}
-	if err := controlplane.StartAPIMonitoringUsingNewBackend(ctx, recorder, restConfig, lb); err != nil {
+	if err := controlplane.StartAPIMonitoringUsingNewBackend(ctx, recorder, restConfig, lb, degradedLatencyThreshold); err != nil {
 		return nil, err
 	}
 
@@ -175,3 +214,24 @@ func StartAPIAvailability(ctx context.Context, restConfig *rest.Config, lb backe
 	recorder.AddIntervals(intervals...)
 	return recorder, nil
 }
+
+// StartPollerAvailability monitors the backends of the given in-cluster poller,
+// a poller without backends monitors the cluster availability.
+func StartPollerAvailability(ctx context.Context, restConfig *rest.Config, poller *sampler.InClusterPoller, degradedLatencyThreshold time.Duration) (monitorapi.Recorder, error) {
+	if len(poller.Backends) == 0 {
+		return StartAPIAvailability(ctx, restConfig, poller.LoadBalancerType, degradedLatencyThreshold)
+	}
+
+	recorder := monitor.NewRecorder()
+	factory := disruptionci.NewDisruptionTestFactory(restConfig)
+	for _, tc := range disruptionci.NewPollerTestConfigurations(*poller, degradedLatencyThreshold) {
+		backendSampler, err := factory.New(tc)
+		if err != nil {
+			return nil, fmt.Errorf("poller %s: unable to create the %s sampler: %w", poller.Name, tc.Name(), err)
+		}
+		if err := backendSampler.StartEndpointMonitoring(ctx, recorder, nil); err != nil {
+			return nil, err
+		}
+	}
+	return recorder, nil
+}
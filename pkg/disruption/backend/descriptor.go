@@ -9,8 +9,34 @@ type ProtocolType string
 const (
 	ProtocolHTTP1 ProtocolType = "http1"
 	ProtocolHTTP2 ProtocolType = "http2"
+
+	// ProtocolTCP samples a raw TCP connect to the target.
+	ProtocolTCP ProtocolType = "tcp"
+	// ProtocolTLS samples a TCP connect followed by a TLS handshake.
+	ProtocolTLS ProtocolType = "tls"
+	// ProtocolDNS samples a DNS resolution against a given DNS server.
+	ProtocolDNS ProtocolType = "dns"
+	// ProtocolGRPC samples the standard gRPC health check.
+	ProtocolGRPC ProtocolType = "grpc"
+	// ProtocolWebSocket samples a WebSocket handshake, or the liveness
+	// of a long running WebSocket stream when the connection is reused.
+	ProtocolWebSocket ProtocolType = "websocket"
+	// ProtocolWatch samples the liveness of a long running watch.
+	ProtocolWatch ProtocolType = "watch"
 )
 
+// IsHTTP returns true if the samples are HTTP requests sent to
+// the target, otherwise the samples are produced by a Prober.
+func (p ProtocolType) IsHTTP() bool {
+	return p == ProtocolHTTP1 || p == ProtocolHTTP2
+}
+
+// DialsEverySample returns true if every sample opens a new connection
+// to the target, such a protocol can not use reused connections.
+func (p ProtocolType) DialsEverySample() bool {
+	return p == ProtocolTCP || p == ProtocolTLS
+}
+
 type LoadBalancerType string
 
 const (
@@ -0,0 +1,86 @@
+package latency
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/events"
+)
+
+func newCIHandler(descriptor backend.TestDescriptor, threshold time.Duration, monitor monitorapi.RecorderWriter, eventRecorder events.EventRecorder) *ciHandler {
+	return &ciHandler{
+		descriptor:      descriptor,
+		threshold:       threshold,
+		monitorRecorder: monitor,
+		eventRecorder:   eventRecorder,
+	}
+}
+
+var _ latencyHandler = &ciHandler{}
+var _ backend.WantEventRecorderAndMonitorRecorder = &ciHandler{}
+
+// ciHandler records the latency summary and the degraded intervals in CI
+type ciHandler struct {
+	descriptor      backend.TestDescriptor
+	threshold       time.Duration
+	monitorRecorder monitorapi.RecorderWriter
+	eventRecorder   events.EventRecorder
+}
+
+// SetEventRecorder sets the event recorder
+func (h *ciHandler) SetEventRecorder(recorder events.EventRecorder) {
+	h.eventRecorder = recorder
+}
+
+// SetMonitorRecorder sets the interval recorder provided by the monitor API
+func (h *ciHandler) SetMonitorRecorder(monitorRecorder monitorapi.RecorderWriter) {
+	h.monitorRecorder = monitorRecorder
+}
+
+// Summary records a non display interval that spans the given minute,
+// it carries the latency percentiles of the minute as annotations.
+func (h *ciHandler) Summary(minute time.Time, latencies []time.Duration) {
+	message := monitorapi.NewMessage().Reason(monitorapi.DisruptionLatencySummaryReason).
+		WithAnnotation(monitorapi.AnnotationLatencyP50, milliseconds(Percentile(latencies, 50))).
+		WithAnnotation(monitorapi.AnnotationLatencyP90, milliseconds(Percentile(latencies, 90))).
+		WithAnnotation(monitorapi.AnnotationLatencyP99, milliseconds(Percentile(latencies, 99))).
+		WithAnnotation(monitorapi.AnnotationCount, strconv.Itoa(len(latencies))).
+		HumanMessage(fmt.Sprintf("latency of %d successful samples over %s connections", len(latencies), h.descriptor.GetConnectionType()))
+
+	interval := monitorapi.NewInterval(monitorapi.SourceDisruptionLatency, monitorapi.Info).Locator(h.descriptor.DisruptionLocator()).
+		Message(message).Build(minute, time.Time{})
+	openIntervalID := h.monitorRecorder.StartInterval(interval)
+	h.monitorRecorder.EndInterval(openIntervalID, minute.Add(time.Minute))
+}
+
+// Degraded records a window of samples that succeeded, but were
+// slower than the threshold.
+func (h *ciHandler) Degraded(from *backend.SampleResult, samples int, to time.Time) {
+	message := monitorapi.NewMessage().Reason(monitorapi.DisruptionLatencyDegradedReason).
+		WithAnnotation(monitorapi.AnnotationLatencyThreshold, milliseconds(h.threshold)).
+		WithAnnotation(monitorapi.AnnotationCount, strconv.Itoa(samples)).
+		HumanMessage(fmt.Sprintf("%d successful samples over %s connections were slower than %s, starting with sample-id=%d %s",
+			samples, h.descriptor.GetConnectionType(), h.threshold, from.Sample.ID, from.String()))
+
+	klog.V(4).Info(message.BuildString())
+	h.eventRecorder.Eventf(
+		&v1.ObjectReference{Kind: "OpenShiftTest", Namespace: "kube-system", Name: h.descriptor.Name()},
+		nil, v1.EventTypeWarning, string(monitorapi.DisruptionLatencyDegradedReason), "detected", message.BuildString())
+
+	interval := monitorapi.NewInterval(monitorapi.SourceDisruptionLatency, monitorapi.Warning).Locator(h.descriptor.DisruptionLocator()).
+		Display().
+		Message(message).Build(from.Sample.StartedAt, time.Time{})
+	openIntervalID := h.monitorRecorder.StartInterval(interval)
+	h.monitorRecorder.EndInterval(openIntervalID, to)
+}
+
+func milliseconds(d time.Duration) string {
+	return strconv.FormatInt(d.Milliseconds(), 10)
+}
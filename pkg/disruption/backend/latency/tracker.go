@@ -0,0 +1,145 @@
+// Package latency tracks the latency of the successful samples of a
+// disruption test, a backend that is available but slow is not caught
+// by the disruption intervals alone.
+package latency
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	"k8s.io/client-go/tools/events"
+)
+
+// NewTracker returns a SampleCollector that does the following:
+//
+//   - groups the round trip duration of the successful samples by the
+//     minute the sample started in, and records a summary interval with
+//     the p50, p90, and p99 latency of each minute
+//
+//   - records a degraded interval for each window of consecutive
+//     successful samples that are slower than the given threshold, the
+//     window ends when a sample is faster than the threshold, or fails.
+//     A zero threshold disables the degraded intervals.
+//
+//     delegate: the next SampleCollector in the chain to be invoked
+//     descriptor: the disruption test the samples belong to
+//     threshold: the latency above which a sample is deemed degraded
+//     monitor: Monitor API to record the intervals in CI
+//     eventRecorder: to create events associated with the degraded intervals
+//
+// For example given a threshold of 1s and the following sequence of samples
+//
+//	s1:200ms s2:1.5s s3:2s s4:err s5:1.2s s6:300ms
+//
+// it will generate the following degraded intervals
+//
+//	degraded[s2,s4) degraded[s5,s6)
+func NewTracker(delegate backendsampler.SampleCollector, descriptor backend.TestDescriptor, threshold time.Duration,
+	monitorRecorder monitorapi.RecorderWriter, eventRecorder events.EventRecorder) (backendsampler.SampleCollector, backend.WantEventRecorderAndMonitorRecorder) {
+	handler := newCIHandler(descriptor, threshold, monitorRecorder, eventRecorder)
+	return &tracker{
+		delegate:  delegate,
+		handler:   handler,
+		threshold: threshold,
+	}, handler
+}
+
+// latencyHandler is an internal interface that receives the calculated
+// latency summaries and degraded windows, and handles them.
+// NOTE: This is intentionally not exported, it helps in writing unit tests
+type latencyHandler interface {
+	// Summary is called once for each minute that has at least
+	// one successful sample, latencies are sorted in ascending order.
+	Summary(minute time.Time, latencies []time.Duration)
+
+	// Degraded is called for a window of consecutive degraded samples
+	// that starts with the given sample, and ends at the given time.
+	Degraded(from *backend.SampleResult, samples int, to time.Time)
+}
+
+type tracker struct {
+	delegate  backendsampler.SampleCollector
+	handler   latencyHandler
+	threshold time.Duration
+
+	minute    time.Time
+	latencies []time.Duration
+
+	degradedFrom *backend.SampleResult
+	degraded     int
+	previous     *backend.SampleResult
+}
+
+func (t *tracker) Collect(bs backend.SampleResult) {
+	// we receive sample in ordered sequence, 1, 2, ... n
+	if t.delegate != nil {
+		t.delegate.Collect(bs)
+	}
+	t.collect(bs)
+}
+
+func (t *tracker) collect(result backend.SampleResult) {
+	if result.Sample == nil {
+		// no more sample arriving, flush what we have
+		t.summarize()
+		if t.degradedFrom != nil {
+			t.handler.Degraded(t.degradedFrom, t.degraded, t.previous.Sample.FinishedAt)
+			t.degradedFrom = nil
+		}
+		return
+	}
+
+	current := &result
+	t.previous = current
+
+	degraded := t.threshold > 0 && current.Succeeded() && current.RoundTripDuration > t.threshold
+	switch {
+	case degraded && t.degradedFrom == nil:
+		t.degradedFrom, t.degraded = current, 1
+	case degraded:
+		t.degraded++
+	case t.degradedFrom != nil:
+		t.handler.Degraded(t.degradedFrom, t.degraded, current.Sample.StartedAt)
+		t.degradedFrom = nil
+	}
+
+	if !current.Succeeded() {
+		return
+	}
+	minute := current.Sample.StartedAt.Truncate(time.Minute)
+	if !minute.Equal(t.minute) {
+		t.summarize()
+		t.minute = minute
+	}
+	t.latencies = append(t.latencies, current.RoundTripDuration)
+}
+
+func (t *tracker) summarize() {
+	if len(t.latencies) == 0 {
+		return
+	}
+	sort.Slice(t.latencies, func(i, j int) bool { return t.latencies[i] < t.latencies[j] })
+	t.handler.Summary(t.minute, t.latencies)
+	t.latencies = nil
+}
+
+// Percentile returns the nearest-rank percentile p (0 < p <= 100)
+// of the given latencies, which must be sorted in ascending order.
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(float64(len(sorted))*p/100)) - 1
+	switch {
+	case rank < 0:
+		rank = 0
+	case rank >= len(sorted):
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
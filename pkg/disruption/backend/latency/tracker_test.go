@@ -0,0 +1,167 @@
+package latency
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/sampler"
+)
+
+func TestLatencyTracker(t *testing.T) {
+	start := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	sample := func(id uint64, at time.Duration, latency time.Duration, err error) backend.SampleResult {
+		return backend.SampleResult{
+			Sample: &sampler.Sample{ID: id, StartedAt: start.Add(at), FinishedAt: start.Add(at + latency), Err: err},
+			RequestResponse: backend.RequestResponse{
+				RequestContextAssociatedData: backend.RequestContextAssociatedData{RoundTripDuration: latency},
+			},
+		}
+	}
+	ms := time.Millisecond
+
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		samples   []backend.SampleResult
+		summaries []summary
+		degraded  []degraded
+	}{
+		{
+			name:    "no samples",
+			samples: []backend.SampleResult{{Sample: nil}},
+		},
+		{
+			name: "failed samples only, no summary expected",
+			samples: []backend.SampleResult{
+				sample(1, 0, 0, fmt.Errorf("error")),
+				sample(2, time.Second, 0, fmt.Errorf("error")),
+				{Sample: nil},
+			},
+		},
+		{
+			name: "one summary per minute, failed samples are ignored",
+			samples: []backend.SampleResult{
+				sample(1, 0, 100*ms, nil),
+				sample(2, 20*time.Second, 300*ms, nil),
+				sample(3, 40*time.Second, 0, fmt.Errorf("error")),
+				sample(4, 50*time.Second, 200*ms, nil),
+				sample(5, 61*time.Second, 400*ms, nil),
+				{Sample: nil},
+			},
+			summaries: []summary{
+				{minute: start, latencies: []time.Duration{100 * ms, 200 * ms, 300 * ms}},
+				{minute: start.Add(time.Minute), latencies: []time.Duration{400 * ms}},
+			},
+		},
+		{
+			name:      "degraded windows end with a fast or failed sample",
+			threshold: time.Second,
+			samples: []backend.SampleResult{
+				sample(1, 0, 200*ms, nil),
+				sample(2, 1*time.Second, 1500*ms, nil),
+				sample(3, 3*time.Second, 2000*ms, nil),
+				sample(4, 6*time.Second, 0, fmt.Errorf("error")),
+				sample(5, 7*time.Second, 1200*ms, nil),
+				sample(6, 9*time.Second, 300*ms, nil),
+				{Sample: nil},
+			},
+			summaries: []summary{
+				{minute: start, latencies: []time.Duration{200 * ms, 300 * ms, 1200 * ms, 1500 * ms, 2000 * ms}},
+			},
+			degraded: []degraded{
+				{from: 2, samples: 2, to: start.Add(6 * time.Second)},
+				{from: 5, samples: 1, to: start.Add(9 * time.Second)},
+			},
+		},
+		{
+			name:      "degraded window open at the end",
+			threshold: time.Second,
+			samples: []backend.SampleResult{
+				sample(1, 0, 1500*ms, nil),
+				sample(2, 2*time.Second, 1500*ms, nil),
+				{Sample: nil},
+			},
+			summaries: []summary{
+				{minute: start, latencies: []time.Duration{1500 * ms, 1500 * ms}},
+			},
+			degraded: []degraded{
+				{from: 1, samples: 2, to: start.Add(3500 * ms)},
+			},
+		},
+		{
+			name: "zero threshold, no degraded window expected",
+			samples: []backend.SampleResult{
+				sample(1, 0, time.Hour, nil),
+				{Sample: nil},
+			},
+			summaries: []summary{
+				{minute: start, latencies: []time.Duration{time.Hour}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler := &fakeHandler{}
+			tracker := &tracker{handler: handler, threshold: test.threshold}
+
+			for i := range test.samples {
+				tracker.Collect(test.samples[i])
+			}
+
+			if !reflect.DeepEqual(test.summaries, handler.summaries) {
+				t.Errorf("expected the summaries to match: diff: %s", cmp.Diff(test.summaries, handler.summaries, cmp.AllowUnexported(summary{})))
+			}
+			if !reflect.DeepEqual(test.degraded, handler.degraded) {
+				t.Errorf("expected the degraded windows to match: diff: %s", cmp.Diff(test.degraded, handler.degraded, cmp.AllowUnexported(degraded{})))
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := []time.Duration{}
+	for i := 1; i <= 200; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	for p, want := range map[float64]time.Duration{50: 100 * time.Millisecond, 90: 180 * time.Millisecond, 99: 198 * time.Millisecond, 100: 200 * time.Millisecond} {
+		if got := Percentile(latencies, p); got != want {
+			t.Errorf("p%v: expected %s, but got: %s", p, want, got)
+		}
+	}
+	if got := Percentile([]time.Duration{time.Second}, 99); got != time.Second {
+		t.Errorf("expected the only sample, but got: %s", got)
+	}
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("expected zero, but got: %s", got)
+	}
+}
+
+type summary struct {
+	minute    time.Time
+	latencies []time.Duration
+}
+
+type degraded struct {
+	from    uint64
+	samples int
+	to      time.Time
+}
+
+type fakeHandler struct {
+	summaries []summary
+	degraded  []degraded
+}
+
+func (h *fakeHandler) Summary(minute time.Time, latencies []time.Duration) {
+	h.summaries = append(h.summaries, summary{minute: minute, latencies: append([]time.Duration{}, latencies...)})
+}
+
+func (h *fakeHandler) Degraded(from *backend.SampleResult, samples int, to time.Time) {
+	h.degraded = append(h.degraded, degraded{from: from.Sample.ID, samples: samples, to: to})
+}
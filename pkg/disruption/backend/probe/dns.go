@@ -0,0 +1,85 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
+)
+
+type DNSRecordType string
+
+const (
+	DNSRecordA    DNSRecordType = "A"
+	DNSRecordAAAA DNSRecordType = "AAAA"
+	DNSRecordSRV  DNSRecordType = "SRV"
+)
+
+// NewDNSProber returns a Prober that resolves the given name against
+// the given DNS server (host:port) for each sample, the sample fails
+// if the lookup fails or it returns no records.
+// Use a fully qualified name with a trailing dot, for example
+// kubernetes.default.svc.cluster.local., so the search domains of
+// the host are not applied.
+func NewDNSProber(server, name string, recordType DNSRecordType) (backendsampler.Prober, error) {
+	switch recordType {
+	case DNSRecordA, DNSRecordAAAA, DNSRecordSRV:
+	default:
+		return nil, fmt.Errorf("unsupported DNS record type %q", recordType)
+	}
+	if len(server) == 0 || len(name) == 0 {
+		return nil, fmt.Errorf("both the DNS server and the name to resolve are required")
+	}
+
+	p := &dnsProber{server: server, name: name, recordType: recordType}
+	p.resolver = &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			// always ask the given server, never the one in resolv.conf
+			return (&net.Dialer{}).DialContext(ctx, network, p.server)
+		},
+	}
+	return p, nil
+}
+
+type dnsProber struct {
+	server     string
+	name       string
+	recordType DNSRecordType
+	resolver   *net.Resolver
+}
+
+func (p *dnsProber) GetBaseURL() string {
+	return fmt.Sprintf("dns://%s/%s?type=%s", p.server, p.name, p.recordType)
+}
+
+func (p *dnsProber) Probe(ctx context.Context, _ uint64, data *backend.RequestContextAssociatedData) error {
+	data.GotConnInfo = &backend.GotConnInfo{RemoteAddr: p.server}
+
+	var records int
+	var err error
+	switch p.recordType {
+	case DNSRecordA:
+		var ips []net.IP
+		ips, err = p.resolver.LookupIP(ctx, "ip4", p.name)
+		records = len(ips)
+	case DNSRecordAAAA:
+		var ips []net.IP
+		ips, err = p.resolver.LookupIP(ctx, "ip6", p.name)
+		records = len(ips)
+	case DNSRecordSRV:
+		var srvs []*net.SRV
+		_, srvs, err = p.resolver.LookupSRV(ctx, "", "", p.name)
+		records = len(srvs)
+	}
+	if err == nil && records == 0 {
+		err = fmt.Errorf("no %s records found for %s", p.recordType, p.name)
+	}
+	if err != nil {
+		data.DNSErr = err
+		return backendsampler.NewKnownError("DNSError", err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+)
+
+// NewGRPCHealthProber returns a Prober that sends the standard gRPC health
+// check for the given service to the given address (host:port), the sample
+// fails unless the server reports SERVING.  An empty service checks the
+// server as a whole.
+//
+//	tlsConfig: if nil the connection is not encrypted
+//	reuseConnection: if true a single client connection is shared by
+//	 all samples, otherwise each sample dials a new connection.
+func NewGRPCHealthProber(address, service string, tlsConfig *tls.Config, reuseConnection bool) backendsampler.Prober {
+	creds := insecure.NewCredentials()
+	if tlsConfig != nil {
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	return &grpcHealth{
+		address: address,
+		service: service,
+		creds:   creds,
+		reuse:   reuseConnection,
+	}
+}
+
+type grpcHealth struct {
+	address string
+	service string
+	creds   credentials.TransportCredentials
+	reuse   bool
+
+	lock sync.Mutex
+	conn *grpc.ClientConn
+}
+
+func (p *grpcHealth) GetBaseURL() string {
+	return fmt.Sprintf("grpc://%s/%s", p.address, p.service)
+}
+
+func (p *grpcHealth) Probe(ctx context.Context, _ uint64, data *backend.RequestContextAssociatedData) error {
+	conn, err := p.getConn(ctx)
+	if err != nil {
+		return checkDialError(err, data)
+	}
+	if !p.reuse {
+		defer conn.Close()
+	}
+
+	remote := &peer.Peer{}
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: p.service}, grpc.Peer(remote))
+	if remote.Addr != nil {
+		data.GotConnInfo = &backend.GotConnInfo{RemoteAddr: remote.Addr.String(), Reused: p.reuse}
+	}
+	if err != nil {
+		return err
+	}
+	if status := resp.GetStatus(); status != healthpb.HealthCheckResponse_SERVING {
+		return backendsampler.NewKnownError("ServerAvailability", fmt.Errorf("health check for service %q returned %s", p.service, status))
+	}
+	return nil
+}
+
+func (p *grpcHealth) getConn(ctx context.Context) (*grpc.ClientConn, error) {
+	if !p.reuse {
+		// block so a failure to connect is reported as such, rather
+		// than as an unavailable RPC.
+		return grpc.DialContext(ctx, p.address, grpc.WithTransportCredentials(p.creds), grpc.WithBlock())
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.conn == nil {
+		// the shared connection reconnects on its own, the RPCs
+		// fail fast while it is not ready.
+		conn, err := grpc.Dial(p.address, grpc.WithTransportCredentials(p.creds))
+		if err != nil {
+			return nil, err
+		}
+		p.conn = conn
+	}
+	return p.conn, nil
+}
+
+func (p *grpcHealth) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.conn == nil {
+		return nil
+	}
+	err := p.conn.Close()
+	p.conn = nil
+	return err
+}
@@ -0,0 +1,327 @@
+package probe
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func probeOnce(t *testing.T, p backendsampler.Prober, sampleID uint64) (*backend.RequestContextAssociatedData, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	data := &backend.RequestContextAssociatedData{}
+	return data, p.Probe(ctx, sampleID, data)
+}
+
+func TestTCPAndTLSProbers(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	address := ts.Listener.Addr().String()
+
+	tlsConfig := ts.Client().Transport.(*http.Transport).TLSClientConfig
+	for _, p := range []backendsampler.Prober{NewTCPConnectProber(address), NewTLSHandshakeProber(address, tlsConfig)} {
+		data, err := probeOnce(t, p, 1)
+		if err != nil {
+			t.Errorf("%s: expected no error, but got: %v", p.GetBaseURL(), err)
+		}
+		if data.GotConnInfo == nil || data.GotConnInfo.RemoteAddr != address {
+			t.Errorf("%s: expected the remote address to be %s, but got: %v", p.GetBaseURL(), address, data.GotConnInfo)
+		}
+	}
+
+	// the handshake fails when the server is not trusted
+	if _, err := probeOnce(t, NewTLSHandshakeProber(address, nil), 2); err == nil {
+		t.Errorf("expected the TLS handshake to fail")
+	}
+
+	ts.Close()
+	if _, err := probeOnce(t, NewTCPConnectProber(address), 3); err == nil {
+		t.Errorf("expected the TCP connect to fail after the server is closed")
+	}
+}
+
+func TestDNSProber(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	go serveDNS(conn, map[string]net.IP{"api.example.com.": net.ParseIP("10.0.0.1")})
+
+	p, err := NewDNSProber(conn.LocalAddr().String(), "api.example.com.", DNSRecordA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := probeOnce(t, p, 1); err != nil {
+		t.Errorf("expected no error, but got: %v", err)
+	}
+
+	p, err = NewDNSProber(conn.LocalAddr().String(), "missing.example.com.", DNSRecordA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := probeOnce(t, p, 2)
+	var knownErr *backendsampler.KnownError
+	if !errors.As(err, &knownErr) || knownErr.Category() != "DNSError" || data.DNSErr == nil {
+		t.Errorf("expected a DNSError, but got: %v", err)
+	}
+
+	if _, err := NewDNSProber(conn.LocalAddr().String(), "api.example.com.", "MX"); err == nil {
+		t.Errorf("expected an error for an unsupported record type")
+	}
+}
+
+// serveDNS answers A queries for the given names, and answers
+// every other query with no records.
+func serveDNS(conn net.PacketConn, records map[string]net.IP) {
+	buf := make([]byte, 512)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		query := buf[:n]
+		// the question starts after the 12 byte header
+		name, end := []string{}, 12
+		for end < n && query[end] != 0 {
+			length := int(query[end])
+			name = append(name, string(query[end+1:end+1+length]))
+			end += 1 + length
+		}
+		end += 5 // the root label, type, and class
+		qtype := binary.BigEndian.Uint16(query[end-4 : end-2])
+
+		ip := records[strings.Join(name, ".")+"."].To4()
+		resp := append([]byte{}, query[:end]...)
+		resp[2], resp[3] = 0x81, 0x80 // a response, recursion available
+		binary.BigEndian.PutUint16(resp[6:8], 0)
+		binary.BigEndian.PutUint16(resp[8:10], 0)
+		binary.BigEndian.PutUint16(resp[10:12], 0)
+		if ip != nil && qtype == 1 {
+			binary.BigEndian.PutUint16(resp[6:8], 1)
+			resp = append(resp, 0xc0, 12, 0, 1, 0, 1, 0, 0, 0, 30, 0, 4)
+			resp = append(resp, ip...)
+		}
+		conn.WriteTo(resp, addr)
+	}
+}
+
+func TestGRPCHealthProber(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	healthServer := health.NewServer()
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	address := listener.Addr().String()
+	for _, reuse := range []bool{false, true} {
+		p := NewGRPCHealthProber(address, "etcd", nil, reuse)
+
+		healthServer.SetServingStatus("etcd", healthpb.HealthCheckResponse_SERVING)
+		data, err := probeOnce(t, p, 1)
+		if err != nil {
+			t.Errorf("reuse=%t: expected no error, but got: %v", reuse, err)
+		}
+		if data.GotConnInfo == nil || data.GotConnInfo.RemoteAddr != address {
+			t.Errorf("reuse=%t: expected the remote address to be %s, but got: %v", reuse, address, data.GotConnInfo)
+		}
+
+		healthServer.SetServingStatus("etcd", healthpb.HealthCheckResponse_NOT_SERVING)
+		_, err = probeOnce(t, p, 2)
+		var knownErr *backendsampler.KnownError
+		if !errors.As(err, &knownErr) || knownErr.Category() != "ServerAvailability" {
+			t.Errorf("reuse=%t: expected the sample to fail, but got: %v", reuse, err)
+		}
+		p.(*grpcHealth).Close()
+	}
+}
+
+func TestWebSocketProber(t *testing.T) {
+	lock := sync.Mutex{}
+	conns := []*websocket.Conn{}
+	upgrader := websocket.Upgrader{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		lock.Lock()
+		conns = append(conns, conn)
+		lock.Unlock()
+		// the default ping handler answers while reading
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer ts.Close()
+
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/v1/namespaces?watch=true"
+	header := http.Header{"Authorization": []string{"Bearer token"}}
+
+	if _, err := probeOnce(t, NewWebSocketProber(url, header, nil, false), 1); err != nil {
+		t.Errorf("expected no error, but got: %v", err)
+	}
+	if _, err := probeOnce(t, NewWebSocketProber(url, nil, nil, false), 1); err == nil {
+		t.Errorf("expected the handshake to fail without the token")
+	}
+
+	p := NewWebSocketProber(url, header, nil, true)
+	defer p.(*webSocketProber).Close()
+	for i, wantReused := range []bool{false, true} {
+		data, err := probeOnce(t, p, uint64(i+1))
+		if err != nil {
+			t.Fatalf("expected no error, but got: %v", err)
+		}
+		if data.GotConnInfo.Reused != wantReused {
+			t.Errorf("sample %d: expected reused=%t", i+1, wantReused)
+		}
+	}
+
+	// the stream ends, the sample fails and the next one opens a new stream
+	lock.Lock()
+	for _, conn := range conns {
+		conn.Close()
+	}
+	lock.Unlock()
+	if _, err := probeOnce(t, p, 3); err == nil {
+		t.Errorf("expected the sample to fail after the stream ended")
+	}
+	data, err := probeOnce(t, p, 4)
+	if err != nil || data.GotConnInfo.Reused {
+		t.Errorf("expected a new stream, but got: %v %v", err, data.GotConnInfo)
+	}
+}
+
+func TestWatchProber(t *testing.T) {
+	bookmark := func(rv string) string {
+		return `{"type":"BOOKMARK","object":{"kind":"Namespace","metadata":{"resourceVersion":"` + rv + `"}}}` + "\n"
+	}
+	tests := []struct {
+		name string
+		// watches are the events sent by each watch in turn, a watch
+		// that is not the last one ends after sending them, the last
+		// one repeats its last event until it is closed.
+		watches [][]string
+		wantErr string
+	}{
+		{
+			name:    "live",
+			watches: [][]string{{bookmark("11")}},
+		},
+		{
+			name:    "ended unexpectedly",
+			watches: [][]string{{bookmark("11")}, {bookmark("12")}},
+			wantErr: "watch ended unexpectedly",
+		},
+		{
+			name:    "stalled",
+			watches: [][]string{{}},
+			wantErr: "watch stalled",
+		},
+		{
+			name:    "resource version went backwards",
+			watches: [][]string{{bookmark("11"), bookmark("5")}},
+			wantErr: "resource version went backwards from 11 to 5",
+		},
+		{
+			name: "expired resource version",
+			watches: [][]string{
+				{`{"type":"ERROR","object":{"kind":"Status","code":410,"reason":"Expired"}}` + "\n"},
+				{bookmark("12")},
+			},
+		},
+		{
+			name:    "error",
+			watches: [][]string{{`{"type":"ERROR","object":{"kind":"Status","code":500,"reason":"InternalError"}}` + "\n"}, {}},
+			wantErr: "watch ended with an error: InternalError",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lock := sync.Mutex{}
+			watches := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("watch") != "true" {
+					w.Write([]byte(`{"kind":"NamespaceList","metadata":{"resourceVersion":"10"},"items":[]}`))
+					return
+				}
+				lock.Lock()
+				n := watches
+				watches++
+				lock.Unlock()
+				if n >= len(test.watches) {
+					n = len(test.watches) - 1
+				}
+				for _, event := range test.watches[n] {
+					w.Write([]byte(event))
+				}
+				w.(http.Flusher).Flush()
+				if n < len(test.watches)-1 {
+					return
+				}
+				for {
+					select {
+					case <-r.Context().Done():
+						return
+					case <-time.After(100 * time.Millisecond):
+					}
+					if events := test.watches[n]; len(events) > 0 {
+						w.Write([]byte(events[len(events)-1]))
+						w.(http.Flusher).Flush()
+					}
+				}
+			}))
+			defer ts.Close()
+
+			p := NewWatchProber(ts.Client(), ts.URL, "/api/v1/namespaces", WatchOptions{
+				BookmarkTimeout: 500 * time.Millisecond,
+				RetryInterval:   10 * time.Millisecond,
+			})
+			defer p.(*watchProber).Close()
+
+			// the first sample waits for the watch to be established, a
+			// failure can already be latched by then, so it is kept too.
+			var errs []string
+			for i := uint64(1); i < 4; i++ {
+				if i == 2 {
+					<-time.After(time.Second)
+				}
+				if _, err := probeOnce(t, p, i); err != nil {
+					errs = append(errs, err.Error())
+				}
+			}
+			got := strings.Join(errs, "\n")
+			switch {
+			case len(test.wantErr) == 0 && len(got) > 0:
+				t.Errorf("expected no error, but got: %v", got)
+			case len(test.wantErr) > 0 && !strings.Contains(got, test.wantErr):
+				t.Errorf("expected %q, but got: %v", test.wantErr, got)
+			}
+		})
+	}
+}
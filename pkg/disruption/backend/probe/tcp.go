@@ -0,0 +1,70 @@
+// Package probe has the Prober implementations that sample backends
+// without sending an HTTP request: a raw TCP connect, a TLS handshake,
+// a DNS resolution, a gRPC health check, and WebSocket liveness.
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
+)
+
+// NewTCPConnectProber returns a Prober that opens a new TCP connection
+// to the given address (host:port) for each sample, and closes it.
+func NewTCPConnectProber(address string) backendsampler.Prober {
+	return &tcpConnect{address: address}
+}
+
+// NewTLSHandshakeProber returns a Prober that opens a new TCP connection
+// to the given address (host:port) for each sample, completes the TLS
+// handshake using the given tls.Config, and closes the connection.
+// A nil tls.Config is treated as the zero configuration.
+func NewTLSHandshakeProber(address string, config *tls.Config) backendsampler.Prober {
+	return &tcpConnect{address: address, useTLS: true, tlsConfig: config}
+}
+
+type tcpConnect struct {
+	address   string
+	useTLS    bool
+	tlsConfig *tls.Config
+}
+
+func (p *tcpConnect) GetBaseURL() string {
+	if p.useTLS {
+		return fmt.Sprintf("tls://%s", p.address)
+	}
+	return fmt.Sprintf("tcp://%s", p.address)
+}
+
+func (p *tcpConnect) Probe(ctx context.Context, _ uint64, data *backend.RequestContextAssociatedData) error {
+	dialer := &net.Dialer{KeepAlive: -1}
+
+	var conn net.Conn
+	var err error
+	if p.useTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: p.tlsConfig}).DialContext(ctx, "tcp", p.address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", p.address)
+	}
+	if err != nil {
+		return checkDialError(err, data)
+	}
+	data.GotConnInfo = &backend.GotConnInfo{RemoteAddr: conn.RemoteAddr().String()}
+	return conn.Close()
+}
+
+// checkDialError categorizes the errors that are common to every prober
+// that dials the target.
+func checkDialError(err error, data *backend.RequestContextAssociatedData) error {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		data.DNSErr = err
+		return backendsampler.NewKnownError("DNSError", err)
+	}
+	return err
+}
@@ -0,0 +1,369 @@
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
+)
+
+// WatchOptions tune how a watch stream is deemed disrupted.
+type WatchOptions struct {
+	// WatchTimeout is the timeoutSeconds requested for each watch, the
+	// server ending the watch any earlier is a disruption.
+	WatchTimeout time.Duration
+
+	// BookmarkTimeout is how long the watch can go without a bookmark or
+	// an event before it is deemed stalled.  The apiserver sends a
+	// bookmark about every minute to a watch that allows them.
+	BookmarkTimeout time.Duration
+
+	// RetryInterval is how long to wait before a failed list or
+	// watch is retried.
+	RetryInterval time.Duration
+}
+
+// NewWatchProber returns a Prober that keeps a long running watch open on
+// the given collection path of a kube-like apiserver, for example
+// /api/v1/namespaces?fieldSelector=metadata.name%3Ddefault, and re-opens it
+// from the last resource version whenever it ends.
+// A sample fails if, since the previous sample or right now:
+//   - the list or the watch request failed
+//   - the watch ended before the requested timeout, or with an error
+//   - no bookmark or event arrived within the BookmarkTimeout
+//   - a resource version older than one already observed was received
+//
+// An expired resource version (410 Gone) is not a disruption, the
+// collection is listed again to get a current resource version.
+//
+//	client: sends the requests, it must not have a client timeout
+//	host: scheme and host of the apiserver, for example https://api:6443
+func NewWatchProber(client backend.Client, host, path string, options WatchOptions) backendsampler.Prober {
+	if options.WatchTimeout == 0 {
+		options.WatchTimeout = 10 * time.Minute
+	}
+	if options.BookmarkTimeout == 0 {
+		options.BookmarkTimeout = 2 * time.Minute
+	}
+	if options.RetryInterval == 0 {
+		options.RetryInterval = time.Second
+	}
+	return &watchProber{
+		client:      client,
+		host:        host,
+		path:        path,
+		options:     options,
+		established: make(chan struct{}),
+	}
+}
+
+type watchProber struct {
+	client  backend.Client
+	host    string
+	path    string
+	options WatchOptions
+
+	start sync.Once
+	stop  context.CancelFunc
+	// established is closed after the first attempt to list and watch
+	// completes, a sample never fails because the watch is not open yet.
+	established     chan struct{}
+	establishedOnce sync.Once
+
+	lock sync.Mutex
+	// failing is set while the watch can not be opened
+	failing error
+	// latched is the first failure since the previous sample, so a watch
+	// that breaks and recovers between two samples is not missed.
+	latched error
+	// remoteAddr is the address the current watch is connected to
+	remoteAddr string
+}
+
+func (p *watchProber) GetBaseURL() string {
+	return fmt.Sprintf("%s%s", p.host, p.path)
+}
+
+func (p *watchProber) Probe(ctx context.Context, _ uint64, data *backend.RequestContextAssociatedData) error {
+	p.start.Do(func() {
+		var watchCtx context.Context
+		watchCtx, p.stop = context.WithCancel(context.Background())
+		go p.run(watchCtx)
+	})
+
+	select {
+	case <-p.established:
+	case <-ctx.Done():
+		return fmt.Errorf("the watch was not established: %w", ctx.Err())
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(p.remoteAddr) > 0 {
+		data.GotConnInfo = &backend.GotConnInfo{RemoteAddr: p.remoteAddr}
+	}
+	err := p.latched
+	p.latched = nil
+	if err == nil {
+		err = p.failing
+	}
+	return err
+}
+
+func (p *watchProber) Close() error {
+	p.start.Do(func() {})
+	if p.stop != nil {
+		p.stop()
+	}
+	return nil
+}
+
+func (p *watchProber) fail(err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.failing = err
+	if p.latched == nil {
+		p.latched = err
+	}
+}
+
+func (p *watchProber) latch(err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.latched == nil {
+		p.latched = err
+	}
+}
+
+func (p *watchProber) recovered(remoteAddr string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.failing = nil
+	p.remoteAddr = remoteAddr
+}
+
+func (p *watchProber) markEstablished() {
+	p.establishedOnce.Do(func() { close(p.established) })
+}
+
+func (p *watchProber) run(ctx context.Context) {
+	defer p.markEstablished()
+
+	// lastRV is the newest resource version observed, rv is the one the
+	// next watch starts from, it is empty when a list is needed.
+	var lastRV uint64
+	var rv string
+	for ctx.Err() == nil {
+		if len(rv) == 0 {
+			listed, err := p.list(ctx)
+			if err == nil {
+				err = checkResourceVersion(listed, &lastRV)
+			}
+			if err != nil {
+				p.fail(err)
+				p.markEstablished()
+				p.wait(ctx)
+				continue
+			}
+			rv = listed
+		}
+
+		next, err := p.watch(ctx, rv, &lastRV)
+		if err != nil && ctx.Err() == nil {
+			p.fail(err)
+			p.markEstablished()
+			p.wait(ctx)
+		}
+		rv = next
+	}
+}
+
+func (p *watchProber) wait(ctx context.Context) {
+	select {
+	case <-time.After(p.options.RetryInterval):
+	case <-ctx.Done():
+	}
+}
+
+type listMeta struct {
+	Metadata struct {
+		ResourceVersion string `json:"resourceVersion"`
+	} `json:"metadata"`
+}
+
+type watchEvent struct {
+	Type   string          `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+type status struct {
+	Code    int    `json:"code"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+func (p *watchProber) newRequest(ctx context.Context, query url.Values) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.host+p.path, nil)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	for k, v := range query {
+		q[k] = v
+	}
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("Accept", "application/json")
+	return req, nil
+}
+
+// list returns the current resource version of the collection.
+func (p *watchProber) list(ctx context.Context) (string, error) {
+	listCtx, cancel := context.WithTimeout(ctx, p.options.BookmarkTimeout)
+	defer cancel()
+	req, err := p.newRequest(listCtx, url.Values{"limit": []string{"1"}})
+	if err != nil {
+		return "", err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("list failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("list failed with unexpected HTTP status code: %v", resp.Status)
+	}
+	list := listMeta{}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return "", fmt.Errorf("list failed to decode: %w", err)
+	}
+	return list.Metadata.ResourceVersion, nil
+}
+
+// watch runs a single watch from the given resource version until it ends,
+// it returns the resource version to resume from, which is empty if the
+// collection needs to be listed again, and an error if the watch ended
+// in a way that is deemed a disruption.
+func (p *watchProber) watch(ctx context.Context, rv string, lastRV *uint64) (string, error) {
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	timeoutSeconds := int64(p.options.WatchTimeout.Seconds())
+	req, err := p.newRequest(watchCtx, url.Values{
+		"watch":               []string{"true"},
+		"allowWatchBookmarks": []string{"true"},
+		"resourceVersion":     []string{rv},
+		"timeoutSeconds":      []string{strconv.FormatInt(timeoutSeconds, 10)},
+	})
+	if err != nil {
+		return rv, err
+	}
+	remoteAddr := ""
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			remoteAddr = info.Conn.RemoteAddr().String()
+		},
+	}))
+	started := time.Now()
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return rv, fmt.Errorf("watch failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusGone {
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return rv, fmt.Errorf("watch failed with unexpected HTTP status code: %v", resp.Status)
+	}
+	p.recovered(remoteAddr)
+	p.markEstablished()
+
+	events := make(chan watchEvent)
+	decodeErr := make(chan error, 1)
+	go func() {
+		defer close(events)
+		decoder := json.NewDecoder(resp.Body)
+		for {
+			event := watchEvent{}
+			if err := decoder.Decode(&event); err != nil {
+				decodeErr <- err
+				return
+			}
+			select {
+			case events <- event:
+			case <-watchCtx.Done():
+				return
+			}
+		}
+	}()
+
+	stalled := time.NewTimer(p.options.BookmarkTimeout)
+	defer stalled.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return rv, nil
+
+		case <-stalled.C:
+			return rv, fmt.Errorf("watch stalled: no bookmark or event for %s", p.options.BookmarkTimeout)
+
+		case event, ok := <-events:
+			if !ok {
+				if ctx.Err() != nil {
+					return rv, nil
+				}
+				// a watch that ends at the requested timeout is expected
+				if lasted := time.Since(started); lasted < time.Duration(timeoutSeconds)*time.Second-5*time.Second {
+					return rv, fmt.Errorf("watch ended unexpectedly after %s: %v", lasted.Round(time.Second), <-decodeErr)
+				}
+				return rv, nil
+			}
+			if !stalled.Stop() {
+				<-stalled.C
+			}
+			stalled.Reset(p.options.BookmarkTimeout)
+
+			if event.Type == "ERROR" {
+				s := status{}
+				json.Unmarshal(event.Object, &s)
+				if s.Code == http.StatusGone {
+					return "", nil
+				}
+				return rv, fmt.Errorf("watch ended with an error: %s %s", s.Reason, s.Message)
+			}
+			object := listMeta{}
+			if err := json.Unmarshal(event.Object, &object); err != nil {
+				return rv, fmt.Errorf("watch event failed to decode: %w", err)
+			}
+			if err := checkResourceVersion(object.Metadata.ResourceVersion, lastRV); err != nil {
+				// keep watching, the watch itself is still live.
+				p.latch(err)
+				continue
+			}
+			rv = object.Metadata.ResourceVersion
+		}
+	}
+}
+
+// checkResourceVersion returns an error if the given resource version is
+// older than the newest one observed, otherwise it records it.
+// Resource versions are opaque, but the apiservers use the etcd revision.
+func checkResourceVersion(rv string, lastRV *uint64) error {
+	current, err := strconv.ParseUint(rv, 10, 64)
+	if err != nil {
+		return fmt.Errorf("unexpected resource version %q: %w", rv, err)
+	}
+	if current < *lastRV {
+		return fmt.Errorf("resource version went backwards from %d to %d", *lastRV, current)
+	}
+	*lastRV = current
+	return nil
+}
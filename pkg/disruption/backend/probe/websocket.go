@@ -0,0 +1,203 @@
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
+
+	"github.com/gorilla/websocket"
+)
+
+// NewWebSocketProber returns a Prober for the given ws:// or wss:// url.
+//
+//	header: the request headers sent with the handshake, for example
+//	 the Authorization header
+//	tlsConfig: the tls.Config used for wss:// urls
+//	reuseConnection: if false each sample completes a new handshake and
+//	 closes the connection.  If true a single long running stream, for
+//	 example a watch, is kept open and each sample sends a ping on it, the
+//	 sample fails if the pong does not arrive before the probe timeout or
+//	 the stream ends.  A failed stream is closed and a new one is opened
+//	 by the next sample.
+func NewWebSocketProber(url string, header http.Header, tlsConfig *tls.Config, reuseConnection bool) backendsampler.Prober {
+	return &webSocketProber{
+		url:    url,
+		header: header,
+		dialer: &websocket.Dialer{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+		reuse: reuseConnection,
+	}
+}
+
+type webSocketProber struct {
+	url    string
+	header http.Header
+	dialer *websocket.Dialer
+	reuse  bool
+
+	lock   sync.Mutex
+	stream *liveStream
+}
+
+func (p *webSocketProber) GetBaseURL() string {
+	return p.url
+}
+
+func (p *webSocketProber) Probe(ctx context.Context, sampleID uint64, data *backend.RequestContextAssociatedData) error {
+	if !p.reuse {
+		conn, err := p.dial(ctx)
+		if err != nil {
+			return checkDialError(err, data)
+		}
+		data.GotConnInfo = &backend.GotConnInfo{RemoteAddr: conn.RemoteAddr().String()}
+		return conn.Close()
+	}
+
+	stream, reused, err := p.getStream(ctx)
+	if err != nil {
+		return checkDialError(err, data)
+	}
+	data.GotConnInfo = &backend.GotConnInfo{RemoteAddr: stream.conn.RemoteAddr().String(), Reused: reused}
+
+	if err := stream.ping(ctx, strconv.FormatUint(sampleID, 10)); err != nil {
+		// the stream is not live anymore, the next sample opens a new one.
+		p.dropStream(stream)
+		return err
+	}
+	return nil
+}
+
+func (p *webSocketProber) dial(ctx context.Context) (*websocket.Conn, error) {
+	conn, resp, err := p.dialer.DialContext(ctx, p.url, p.header)
+	if err != nil {
+		if resp != nil {
+			return nil, backendsampler.NewKnownError("ServerAvailability", fmt.Errorf("websocket handshake failed with %s: %w", resp.Status, err))
+		}
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (p *webSocketProber) getStream(ctx context.Context) (*liveStream, bool, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.stream != nil {
+		return p.stream, true, nil
+	}
+	conn, err := p.dial(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	p.stream = newLiveStream(conn)
+	return p.stream, false, nil
+}
+
+func (p *webSocketProber) dropStream(stream *liveStream) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	stream.conn.Close()
+	if p.stream == stream {
+		p.stream = nil
+	}
+}
+
+func (p *webSocketProber) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.stream == nil {
+		return nil
+	}
+	err := p.stream.conn.Close()
+	p.stream = nil
+	return err
+}
+
+// liveStream reads from a long running WebSocket connection, and
+// matches each pong received with the ping that was sent.
+type liveStream struct {
+	conn *websocket.Conn
+
+	lock    sync.Mutex
+	waiting map[string]chan struct{}
+	done    chan struct{}
+	err     error
+}
+
+func newLiveStream(conn *websocket.Conn) *liveStream {
+	s := &liveStream{
+		conn:    conn,
+		waiting: map[string]chan struct{}{},
+		done:    make(chan struct{}),
+	}
+	conn.SetPongHandler(func(appData string) error {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		if ch, ok := s.waiting[appData]; ok {
+			close(ch)
+			delete(s.waiting, appData)
+		}
+		return nil
+	})
+	go s.read()
+	return s
+}
+
+// read discards the messages, like watch events, sent by the server, the
+// control frames are handled while reading.
+func (s *liveStream) read() {
+	for {
+		_, r, err := s.conn.NextReader()
+		if err == nil {
+			_, err = io.Copy(io.Discard, r)
+		}
+		if err != nil {
+			s.lock.Lock()
+			s.err = err
+			s.lock.Unlock()
+			close(s.done)
+			return
+		}
+	}
+}
+
+func (s *liveStream) ping(ctx context.Context, payload string) error {
+	pong := make(chan struct{})
+	s.lock.Lock()
+	s.waiting[payload] = pong
+	s.lock.Unlock()
+	defer func() {
+		s.lock.Lock()
+		delete(s.waiting, payload)
+		s.lock.Unlock()
+	}()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(time.Minute)
+	}
+	// WriteControl can be called concurrently with the reader.
+	if err := s.conn.WriteControl(websocket.PingMessage, []byte(payload), deadline); err != nil {
+		return fmt.Errorf("failed to send ping: %w", err)
+	}
+
+	select {
+	case <-pong:
+		return nil
+	case <-s.done:
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		return fmt.Errorf("websocket stream ended: %w", s.err)
+	case <-ctx.Done():
+		return fmt.Errorf("no pong received: %w", ctx.Err())
+	}
+}
@@ -4,6 +4,11 @@ import (
 	"fmt"
 )
 
+// NewKnownError returns a KnownError that attaches the given category to err.
+func NewKnownError(category string, err error) *KnownError {
+	return &KnownError{category: category, err: err}
+}
+
 type KnownError struct {
 	category string
 	err      error
@@ -0,0 +1,86 @@
+package sampler
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/sampler"
+)
+
+// Prober exercises a backend that is not sampled with an HTTP request,
+// for example a raw TCP connect, a DNS resolution, or a gRPC health check.
+// A Prober that holds on to a connection across samples should also
+// implement io.Closer, it is closed when there are no more samples.
+type Prober interface {
+	// GetBaseURL returns a description of the target,
+	// for example tcp://10.0.0.1:6443.
+	GetBaseURL() string
+
+	// Probe exercises the backend once, it returns an error if the sample
+	// is deemed to have failed.  Probe can record diagnostic data, like the
+	// remote address, in the given RequestContextAssociatedData.
+	// Probe can be invoked concurrently.
+	Probe(ctx context.Context, sampleID uint64, data *backend.RequestContextAssociatedData) error
+}
+
+// NewProbeProducerConsumer returns a ProducerConsumer, the Producer uses the
+// given Prober to exercise the backend, and the consumer feeds the result
+// to the specified SampleCollector, exactly as NewSampleProducerConsumer
+// does, so the disruption interval(s) are tracked the same way.
+//
+//	prober: the Prober that exercises the backend
+//	timeout: the maximum amount of time a single probe can take
+//	collector: user specified SampleCollector that will collect each
+//	 sample result for further analysis.
+func NewProbeProducerConsumer(prober Prober, timeout time.Duration, collector SampleCollector) sampler.ProducerConsumer {
+	return &probeProducerConsumer{
+		prober:    prober,
+		timeout:   timeout,
+		collector: collector,
+	}
+}
+
+type probeProducerConsumer struct {
+	prober    Prober
+	timeout   time.Duration
+	collector SampleCollector
+}
+
+func (pc *probeProducerConsumer) Produce(stop context.Context, sampleID uint64) (interface{}, error) {
+	rr := backend.RequestResponse{
+		RequestContextAssociatedData: backend.RequestContextAssociatedData{},
+	}
+
+	// similar to the HTTP producer, we don't use the stop context as the base
+	// context since we want a probe in progress to be able to complete.
+	ctx := context.Background()
+	if pc.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, pc.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := pc.prober.Probe(ctx, sampleID, &rr.RequestContextAssociatedData)
+	rr.RoundTripDuration = time.Since(start)
+	return rr, err
+}
+
+func (pc *probeProducerConsumer) Consume(s *sampler.Sample, custom interface{}) {
+	// should never happen, we panic if for some programmer error
+	rr := custom.(backend.RequestResponse)
+	pc.collector.Collect(backend.SampleResult{
+		Sample:          s,
+		RequestResponse: rr,
+	})
+}
+
+func (pc *probeProducerConsumer) Close() {
+	if closer, ok := pc.prober.(io.Closer); ok {
+		closer.Close()
+	}
+	// no more sample available, send an empty value
+	pc.collector.Collect(backend.SampleResult{})
+}
@@ -12,6 +12,7 @@ import (
 
 	configclient "github.com/openshift/client-go/config/clientset/versioned"
 	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	"github.com/openshift/origin/pkg/disruption/backend"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
 	"github.com/openshift/origin/pkg/monitortestlibrary/nodeaccess"
@@ -25,12 +26,15 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	watchtools "k8s.io/client-go/tools/watch"
+	"sigs.k8s.io/yaml"
 )
 
 const (
 	disruptionDataFolder = "disruption-data"
 	disruptionTypeEnvVar = "DISRUPTION_TYPE_LABEL"
 	inClusterEventsFile  = "junit/AdditionalEvents__in_cluster_disruption.json"
+	configMapName        = "disruption-monitor-config"
+	configMapKey         = "config.yaml"
 )
 
 var (
@@ -56,6 +60,9 @@ var (
 	rbacMonitorCRBName    string
 )
 
+// TearDownInClusterMonitors removes every poller, whatever configuration
+// started it, and writes the intervals they recorded on the nodes to the
+// artifacts.
 func TearDownInClusterMonitors(config *rest.Config) error {
 	ctx := context.Background()
 
@@ -87,7 +94,12 @@ func TearDownInClusterMonitors(config *rest.Config) error {
 	return monitorserialization.EventsToFile(artifactPath, events)
 }
 
-func StartInClusterMonitors(ctx context.Context, config *rest.Config) error {
+// StartInClusterMonitors deploys a DaemonSet for each poller of the given
+// configuration, every poller runs run-disruption with its own backends.
+func StartInClusterMonitors(ctx context.Context, config *rest.Config, monitors *InClusterMonitorConfiguration) error {
+	if err := monitors.Validate(); err != nil {
+		return err
+	}
 	kubeClient, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return err
@@ -127,15 +139,16 @@ func StartInClusterMonitors(ctx context.Context, config *rest.Config) error {
 	if err != nil {
 		return err
 	}
-	err = createServiceNetworkDS(ctx, kubeClient)
+	err = createConfigMap(ctx, kubeClient, monitors)
 	if err != nil {
 		return err
 	}
-	err = createLocalhostDS(ctx, kubeClient)
-	if err != nil {
-		return err
+	for _, poller := range monitors.Pollers {
+		if err := createPollerDS(ctx, kubeClient, poller, apiIntHost); err != nil {
+			return err
+		}
 	}
-	return createInternalLBDS(ctx, kubeClient, apiIntHost)
+	return nil
 }
 
 func deleteTestBed(ctx context.Context, kubeClient *kubernetes.Clientset) error {
@@ -187,10 +200,10 @@ func deleteTestBed(ctx context.Context, kubeClient *kubernetes.Clientset) error
 	return nil
 }
 
-func createInternalLBDS(ctx context.Context, clientset *kubernetes.Clientset, apiIntHost string) error {
-	dsObj := resourceread.ReadDaemonSetV1OrDie(dsInternalLBYaml)
-	dsObj.Namespace = namespace
-	dsObj.Spec.Template.Spec.Containers[0].Env[0].Value = apiIntHost
+// createPollerDS creates the DaemonSet of the given poller, and waits
+// for it to roll out.
+func createPollerDS(ctx context.Context, clientset *kubernetes.Clientset, poller InClusterPoller, apiIntHost string) error {
+	dsObj := newPollerDS(poller, apiIntHost)
 
 	client := clientset.AppsV1().DaemonSets(namespace)
 	var err error
@@ -217,60 +230,59 @@ func createInternalLBDS(ctx context.Context, clientset *kubernetes.Clientset, ap
 	return nil
 }
 
-func createServiceNetworkDS(ctx context.Context, clientset *kubernetes.Clientset) error {
-	dsObj := resourceread.ReadDaemonSetV1OrDie(dsServiceNetworkYaml)
+// newPollerDS returns the DaemonSet of the given poller, from the
+// manifest of its load balancer type.
+func newPollerDS(poller InClusterPoller, apiIntHost string) *appsv1.DaemonSet {
+	var dsYaml []byte
+	switch poller.LoadBalancerType {
+	case backend.InternalLoadBalancerType:
+		dsYaml = dsInternalLBYaml
+	case backend.LocalhostType:
+		dsYaml = dsLocalhostYaml
+	default:
+		dsYaml = dsServiceNetworkYaml
+	}
+	dsObj := resourceread.ReadDaemonSetV1OrDie(dsYaml)
 	dsObj.Namespace = namespace
-
-	client := clientset.AppsV1().DaemonSets(namespace)
-	var err error
-	_, err = client.Create(ctx, dsObj, metav1.CreateOptions{})
-	if err != nil {
-		return fmt.Errorf("error creating daemonset: %v", err)
-	}
-
-	timeLimitedCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
-	defer cancel()
-
-	if _, watchErr := watchtools.UntilWithSync(timeLimitedCtx,
-		cache.NewListWatchFromClient(
-			clientset.AppsV1().RESTClient(), "daemonsets", namespace, fields.OneTermEqualSelector("metadata.name", dsObj.Name)),
-		&appsv1.DaemonSet{},
-		nil,
-		func(event watch.Event) (bool, error) {
-			ds := event.Object.(*appsv1.DaemonSet)
-			return ds.Status.NumberReady > 0, nil
-		},
-	); watchErr != nil {
-		return fmt.Errorf("daemonset %s didn't roll out: %v", dsObj.Name, watchErr)
+	dsObj.Name = poller.Name
+	dsObj.Spec.Selector.MatchLabels["app"] = poller.Name
+	dsObj.Spec.Template.Labels["app"] = poller.Name
+	if len(poller.NodeSelector) > 0 {
+		dsObj.Spec.Template.Spec.NodeSelector = poller.NodeSelector
+	}
+	container := &dsObj.Spec.Template.Spec.Containers[0]
+	for i := range container.Env {
+		switch container.Env[i].Name {
+		case "POLLER_NAME":
+			container.Env[i].Value = poller.Name
+		case "LB_TYPE":
+			container.Env[i].Value = string(poller.LoadBalancerType)
+		case "KUBERNETES_SERVICE_HOST":
+			container.Env[i].Value = apiIntHost
+		}
 	}
-	return nil
+	return dsObj
 }
 
-func createLocalhostDS(ctx context.Context, clientset *kubernetes.Clientset) error {
-	dsObj := resourceread.ReadDaemonSetV1OrDie(dsLocalhostYaml)
-	dsObj.Namespace = namespace
-
-	client := clientset.AppsV1().DaemonSets(namespace)
-	var err error
-	_, err = client.Create(ctx, dsObj, metav1.CreateOptions{})
+// createConfigMap stores the configuration, the pollers mount it
+// to find out which backends they sample.  A configuration left behind
+// by an earlier run is replaced, the backends may have changed since.
+func createConfigMap(ctx context.Context, clientset *kubernetes.Clientset, monitors *InClusterMonitorConfiguration) error {
+	data, err := yaml.Marshal(monitors)
 	if err != nil {
-		return fmt.Errorf("error creating daemonset: %v", err)
+		return err
 	}
-
-	timeLimitedCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
-	defer cancel()
-
-	if _, watchErr := watchtools.UntilWithSync(timeLimitedCtx,
-		cache.NewListWatchFromClient(
-			clientset.AppsV1().RESTClient(), "daemonsets", namespace, fields.OneTermEqualSelector("metadata.name", dsObj.Name)),
-		&appsv1.DaemonSet{},
-		nil,
-		func(event watch.Event) (bool, error) {
-			ds := event.Object.(*appsv1.DaemonSet)
-			return ds.Status.NumberReady > 0, nil
-		},
-	); watchErr != nil {
-		return fmt.Errorf("daemonset %s didn't roll out: %v", dsObj.Name, watchErr)
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: configMapName, Namespace: namespace},
+		Data:       map[string]string{configMapKey: string(data)},
+	}
+	client := clientset.CoreV1().ConfigMaps(namespace)
+	_, err = client.Create(ctx, configMap, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		_, err = client.Update(ctx, configMap, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("error creating the monitor configuration: %v", err)
 	}
 	return nil
 }
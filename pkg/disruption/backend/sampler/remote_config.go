@@ -0,0 +1,215 @@
+package sampler
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+// InClusterMonitorConfigEnvVar names the file with the in-cluster monitor
+// configuration, if it is not set the default pollers are deployed.
+const InClusterMonitorConfigEnvVar = "IN_CLUSTER_DISRUPTION_MONITORS_CONFIG"
+
+// InClusterMonitorConfiguration lists the pollers deployed in the cluster,
+// each poller is a DaemonSet that runs run-disruption on its nodes.
+type InClusterMonitorConfiguration struct {
+	Pollers []InClusterPoller `json:"pollers"`
+}
+
+// InClusterPoller is a DaemonSet that samples its backends from every
+// node it runs on.
+type InClusterPoller struct {
+	// Name is the name of the DaemonSet, it must be unique.
+	Name string `json:"name"`
+
+	// LoadBalancerType is how the poller reaches its backends, one of
+	// internal-lb, service-network, or localhost.  An internal-lb poller
+	// runs on the host network and talks to api-int, a localhost poller
+	// runs on the host network of the control plane nodes.
+	LoadBalancerType backend.LoadBalancerType `json:"loadBalancerType"`
+
+	// NodeSelector, if set, replaces the nodes the poller runs on.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Backends are the endpoints sampled by the poller, if empty
+	// the poller samples the kube and openshift apiservers.
+	Backends []InClusterBackend `json:"backends,omitempty"`
+}
+
+// InClusterBackend is an endpoint sampled by a poller.
+type InClusterBackend struct {
+	// Name identifies the backend in the disruption backend names,
+	// for example "my-ingress".
+	Name string `json:"name"`
+
+	// Target is the host:port of the endpoint, or the address of the
+	// server for the dns protocol.  If empty, the apiserver the poller
+	// is connected to is sampled.
+	Target string `json:"target,omitempty"`
+
+	// Path is the request path for the http, websocket, and watch protocols.
+	Path string `json:"path,omitempty"`
+
+	// Protocol is one of http1, http2, tcp, tls, dns, grpc, websocket, or watch.
+	Protocol backend.ProtocolType `json:"protocol"`
+
+	// ConnectionTypes defaults to new connections only, the tcp and tls
+	// protocols do not support reused connections.
+	ConnectionTypes []monitorapi.BackendConnectionType `json:"connectionTypes,omitempty"`
+
+	// Interval between two samples, it defaults to 1s.
+	Interval metav1.Duration `json:"interval,omitempty"`
+
+	// Timeout of a sample, it defaults to 15s.
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+
+	// DegradedLatencyThreshold is the round trip latency above which a
+	// successful sample is deemed degraded, it defaults to the threshold
+	// of the run-disruption command.
+	DegradedLatencyThreshold metav1.Duration `json:"degradedLatencyThreshold,omitempty"`
+
+	// Plaintext disables TLS, InsecureSkipTLSVerify keeps TLS but does not
+	// verify the certificate of a Target, which is not the apiserver.
+	Plaintext             bool `json:"plaintext,omitempty"`
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+
+	// DNSName is the name resolved by the dns protocol, DNSRecordType is
+	// the type of record resolved: A (the default), AAAA, or SRV.
+	DNSName       string `json:"dnsName,omitempty"`
+	DNSRecordType string `json:"dnsRecordType,omitempty"`
+
+	// GRPCService is the service checked by the grpc protocol.
+	GRPCService string `json:"grpcService,omitempty"`
+}
+
+// DefaultInClusterMonitorConfiguration returns the pollers that sample the
+// apiservers through the internal load balancer, the service network, and
+// localhost on the control plane nodes.
+func DefaultInClusterMonitorConfiguration() *InClusterMonitorConfiguration {
+	return &InClusterMonitorConfiguration{
+		Pollers: []InClusterPoller{
+			{Name: "internal-lb-monitor", LoadBalancerType: backend.InternalLoadBalancerType},
+			{Name: "service-network-monitor", LoadBalancerType: backend.ServiceNetworkType},
+			{Name: "localhost-monitor", LoadBalancerType: backend.LocalhostType},
+		},
+	}
+}
+
+// LoadInClusterMonitorConfiguration reads the configuration, in yaml or json,
+// from the given file and defaults it.
+func LoadInClusterMonitorConfiguration(path string) (*InClusterMonitorConfiguration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the in-cluster monitor configuration: %w", err)
+	}
+	config := &InClusterMonitorConfiguration{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse the in-cluster monitor configuration %s: %w", path, err)
+	}
+	config.Default()
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid in-cluster monitor configuration %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// InClusterMonitorConfigurationFromEnv loads the configuration named by
+// InClusterMonitorConfigEnvVar, or returns the default configuration.
+func InClusterMonitorConfigurationFromEnv() (*InClusterMonitorConfiguration, error) {
+	path := os.Getenv(InClusterMonitorConfigEnvVar)
+	if len(path) == 0 {
+		return DefaultInClusterMonitorConfiguration(), nil
+	}
+	return LoadInClusterMonitorConfiguration(path)
+}
+
+// Default fills in the optional fields of the backends.
+func (c *InClusterMonitorConfiguration) Default() {
+	for i := range c.Pollers {
+		for j := range c.Pollers[i].Backends {
+			b := &c.Pollers[i].Backends[j]
+			if len(b.ConnectionTypes) == 0 {
+				b.ConnectionTypes = []monitorapi.BackendConnectionType{monitorapi.NewConnectionType}
+			}
+			if b.Interval.Duration == 0 {
+				b.Interval.Duration = time.Second
+			}
+			if b.Timeout.Duration == 0 {
+				b.Timeout.Duration = 15 * time.Second
+			}
+		}
+	}
+}
+
+func (c *InClusterMonitorConfiguration) Validate() error {
+	if len(c.Pollers) == 0 {
+		return fmt.Errorf("at least one poller is required")
+	}
+	pollers := map[string]bool{}
+	for _, p := range c.Pollers {
+		if errs := validation.IsDNS1123Label(p.Name); len(errs) > 0 {
+			return fmt.Errorf("poller %q: invalid name: %s", p.Name, strings.Join(errs, ", "))
+		}
+		if pollers[p.Name] {
+			return fmt.Errorf("poller %q is listed more than once", p.Name)
+		}
+		pollers[p.Name] = true
+
+		switch p.LoadBalancerType {
+		case backend.InternalLoadBalancerType, backend.ServiceNetworkType, backend.LocalhostType:
+		default:
+			return fmt.Errorf("poller %q: unsupported loadBalancerType %q", p.Name, p.LoadBalancerType)
+		}
+
+		backends := map[string]bool{}
+		for _, b := range p.Backends {
+			if len(b.Name) == 0 {
+				return fmt.Errorf("poller %q: a backend must have a name", p.Name)
+			}
+			if backends[b.Name] {
+				return fmt.Errorf("poller %q: backend %q is listed more than once", p.Name, b.Name)
+			}
+			backends[b.Name] = true
+
+			switch b.Protocol {
+			case backend.ProtocolHTTP1, backend.ProtocolHTTP2, backend.ProtocolTCP, backend.ProtocolTLS,
+				backend.ProtocolGRPC, backend.ProtocolWebSocket, backend.ProtocolWatch:
+			case backend.ProtocolDNS:
+				if len(b.Target) == 0 || len(b.DNSName) == 0 {
+					return fmt.Errorf("poller %q: backend %q: the dns protocol needs a target and a dnsName", p.Name, b.Name)
+				}
+			default:
+				return fmt.Errorf("poller %q: backend %q: unsupported protocol %q", p.Name, b.Name, b.Protocol)
+			}
+			if b.DegradedLatencyThreshold.Duration < 0 {
+				return fmt.Errorf("poller %q: backend %q: degradedLatencyThreshold must not be negative", p.Name, b.Name)
+			}
+			for _, connectionType := range b.ConnectionTypes {
+				if connectionType != monitorapi.NewConnectionType && connectionType != monitorapi.ReusedConnectionType {
+					return fmt.Errorf("poller %q: backend %q: unsupported connection type %q", p.Name, b.Name, connectionType)
+				}
+				if connectionType == monitorapi.ReusedConnectionType && b.Protocol.DialsEverySample() {
+					return fmt.Errorf("poller %q: backend %q: the %s protocol opens a new connection for every sample, it can not use %s connections", p.Name, b.Name, b.Protocol, connectionType)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Poller returns the poller with the given name.
+func (c *InClusterMonitorConfiguration) Poller(name string) (*InClusterPoller, error) {
+	for i := range c.Pollers {
+		if c.Pollers[i].Name == name {
+			return &c.Pollers[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no poller named %q in the in-cluster monitor configuration", name)
+}
@@ -0,0 +1,212 @@
+package sampler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestLoadInClusterMonitorConfiguration(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "custom poller",
+			config: `
+pollers:
+- name: internal-lb-monitor
+  loadBalancerType: internal-lb
+- name: ingress-monitor
+  loadBalancerType: service-network
+  nodeSelector:
+    node-role.kubernetes.io/worker: ""
+  backends:
+  - name: my-ingress
+    target: router-internal-default.openshift-ingress.svc:443
+    path: /healthz
+    protocol: http1
+    insecureSkipTLSVerify: true
+    connectionTypes: [new, reused]
+    interval: 2s
+    degradedLatencyThreshold: 500ms
+`,
+		},
+		{
+			name:    "no pollers",
+			config:  `pollers: []`,
+			wantErr: "at least one poller is required",
+		},
+		{
+			name: "duplicate poller",
+			config: `
+pollers:
+- name: a
+  loadBalancerType: localhost
+- name: a
+  loadBalancerType: localhost
+`,
+			wantErr: `poller "a" is listed more than once`,
+		},
+		{
+			name: "invalid name",
+			config: `
+pollers:
+- name: My_Poller
+  loadBalancerType: localhost
+`,
+			wantErr: `poller "My_Poller": invalid name`,
+		},
+		{
+			name: "unsupported load balancer type",
+			config: `
+pollers:
+- name: a
+  loadBalancerType: external-lb
+`,
+			wantErr: `unsupported loadBalancerType "external-lb"`,
+		},
+		{
+			name: "unsupported protocol",
+			config: `
+pollers:
+- name: a
+  loadBalancerType: service-network
+  backends:
+  - name: b
+    protocol: ftp
+`,
+			wantErr: `unsupported protocol "ftp"`,
+		},
+		{
+			name: "dns without a target",
+			config: `
+pollers:
+- name: a
+  loadBalancerType: service-network
+  backends:
+  - name: b
+    protocol: dns
+    dnsName: kubernetes.default.svc.cluster.local.
+`,
+			wantErr: "the dns protocol needs a target and a dnsName",
+		},
+		{
+			name: "negative degraded latency threshold",
+			config: `
+pollers:
+- name: a
+  loadBalancerType: service-network
+  backends:
+  - name: b
+    protocol: http1
+    degradedLatencyThreshold: -1s
+`,
+			wantErr: "degradedLatencyThreshold must not be negative",
+		},
+		{
+			name: "tcp with reused connections",
+			config: `
+pollers:
+- name: a
+  loadBalancerType: service-network
+  backends:
+  - name: b
+    target: router-internal-default.openshift-ingress.svc:443
+    protocol: tcp
+    connectionTypes: [new, reused]
+`,
+			wantErr: `backend "b": the tcp protocol opens a new connection for every sample, it can not use reused connections`,
+		},
+		{
+			name: "unknown field",
+			config: `
+pollers:
+- name: a
+  loadBalancerType: localhost
+  replicas: 3
+`,
+			wantErr: "unknown field",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(test.config), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadInClusterMonitorConfiguration(path)
+			switch {
+			case len(test.wantErr) == 0 && err != nil:
+				t.Errorf("expected no error, but got: %v", err)
+			case len(test.wantErr) > 0 && (err == nil || !strings.Contains(err.Error(), test.wantErr)):
+				t.Errorf("expected an error with %q, but got: %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestInClusterMonitorConfigurationDefault(t *testing.T) {
+	config := &InClusterMonitorConfiguration{
+		Pollers: []InClusterPoller{
+			{
+				Name:             "ingress-monitor",
+				LoadBalancerType: backend.ServiceNetworkType,
+				Backends:         []InClusterBackend{{Name: "my-ingress", Protocol: backend.ProtocolTCP}},
+			},
+		},
+	}
+	config.Default()
+	if err := config.Validate(); err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+
+	poller, err := config.Poller("ingress-monitor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := poller.Backends[0]
+	if len(b.ConnectionTypes) != 1 || b.ConnectionTypes[0] != monitorapi.NewConnectionType {
+		t.Errorf("expected new connections, but got: %v", b.ConnectionTypes)
+	}
+	if b.Interval.Duration != time.Second || b.Timeout.Duration != 15*time.Second {
+		t.Errorf("expected the default interval and timeout, but got: %s %s", b.Interval.Duration, b.Timeout.Duration)
+	}
+	if _, err := config.Poller("missing"); err == nil {
+		t.Errorf("expected an error for a missing poller")
+	}
+}
+
+func TestNewPollerDS(t *testing.T) {
+	for _, poller := range DefaultInClusterMonitorConfiguration().Pollers {
+		ds := newPollerDS(poller, "api-int.example.com")
+		if ds.Name != poller.Name || ds.Spec.Selector.MatchLabels["app"] != poller.Name || ds.Spec.Template.Labels["app"] != poller.Name {
+			t.Errorf("%s: expected the daemonset to be named after the poller, but got: %s", poller.Name, ds.Name)
+		}
+		env := map[string]string{}
+		for _, e := range ds.Spec.Template.Spec.Containers[0].Env {
+			env[e.Name] = e.Value
+		}
+		if env["POLLER_NAME"] != poller.Name || env["LB_TYPE"] != string(poller.LoadBalancerType) {
+			t.Errorf("%s: unexpected env: %v", poller.Name, env)
+		}
+		if poller.LoadBalancerType == backend.InternalLoadBalancerType && env["KUBERNETES_SERVICE_HOST"] != "api-int.example.com" {
+			t.Errorf("%s: expected the api-int host, but got: %v", poller.Name, env)
+		}
+	}
+
+	ds := newPollerDS(InClusterPoller{
+		Name:             "ingress-monitor",
+		LoadBalancerType: backend.LocalhostType,
+		NodeSelector:     map[string]string{"node-role.kubernetes.io/worker": ""},
+	}, "")
+	if _, ok := ds.Spec.Template.Spec.NodeSelector["node-role.kubernetes.io/worker"]; !ok || len(ds.Spec.Template.Spec.NodeSelector) != 1 {
+		t.Errorf("expected the node selector to be replaced, but got: %v", ds.Spec.Template.Spec.NodeSelector)
+	}
+}
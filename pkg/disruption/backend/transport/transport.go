@@ -1,6 +1,7 @@
 package transport
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
@@ -30,6 +31,27 @@ func FromRestConfig(config *rest.Config, reuseConnection bool, timeout time.Dura
 		return nil, err
 	}
 
+	rt := FromTLSConfig(tlsConfig, reuseConnection, timeout, http1)
+	if len(config.BearerToken) == 0 && len(config.BearerTokenFile) == 0 {
+		return rt, nil
+	}
+	if tlsConfig == nil {
+		return nil, fmt.Errorf("tls.Config is required if you have providing a token")
+	}
+
+	return transport.NewBearerAuthWithRefreshRoundTripper(config.BearerToken, config.BearerTokenFile, rt)
+}
+
+// FromTLSConfig constructs a http.RoundTripper transport that uses the
+// given tls.Config, it does not authenticate the requests.
+//
+//	tlsConfig: the given tls.Config object, nil uses the system roots
+//	reuseConnection: true if the underlying TCP connection should be
+//	  reused for multiple requests
+//	timeout: transport timeout
+//	http1: if true the transport will be configured to use HTTP/1.x
+//	  protocol, otherwise http/2.0 will be used.
+func FromTLSConfig(tlsConfig *tls.Config, reuseConnection bool, timeout time.Duration, http1 bool) *http.Transport {
 	rt := &http.Transport{
 		Dial: (&net.Dialer{
 			Timeout:   timeout,
@@ -46,12 +68,5 @@ func FromRestConfig(config *rest.Config, reuseConnection bool, timeout time.Dura
 	if !http1 {
 		utilnet.SetTransportDefaults(rt)
 	}
-	if len(config.BearerToken) == 0 && len(config.BearerTokenFile) == 0 {
-		return rt, nil
-	}
-	if tlsConfig == nil {
-		return nil, fmt.Errorf("tls.Config is required if you have providing a token")
-	}
-
-	return transport.NewBearerAuthWithRefreshRoundTripper(config.BearerToken, config.BearerTokenFile, rt)
+	return rt
 }
@@ -1,6 +1,7 @@
 package ci
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"sync"
@@ -8,7 +9,9 @@ import (
 
 	"github.com/openshift/origin/pkg/disruption/backend"
 	"github.com/openshift/origin/pkg/disruption/backend/disruption"
+	"github.com/openshift/origin/pkg/disruption/backend/latency"
 	"github.com/openshift/origin/pkg/disruption/backend/logger"
+	"github.com/openshift/origin/pkg/disruption/backend/probe"
 	"github.com/openshift/origin/pkg/disruption/backend/roundtripper"
 	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
 	"github.com/openshift/origin/pkg/disruption/backend/shutdown"
@@ -19,6 +22,11 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+// DefaultDegradedLatencyThreshold is the round trip latency above which a
+// successful request to the apiservers is deemed degraded, unless the
+// run says otherwise.
+const DefaultDegradedLatencyThreshold = 2 * time.Second
+
 type ServerNameType string
 
 const (
@@ -62,10 +70,44 @@ type TestConfiguration struct {
 	//  it to 1s.
 	SampleInterval time.Duration
 
+	// DegradedLatencyThreshold is the round trip latency above which a
+	// successful sample is deemed degraded, a window of degraded samples
+	// is recorded as a degraded latency interval.  If zero, the latency
+	// is still summarized per minute, but no degraded interval is recorded.
+	// See DefaultDegradedLatencyThreshold.
+	DegradedLatencyThreshold time.Duration
+
 	// EnableShutdownResponseHeader indicates whether to include the shutdown
 	// response header extractor, this should be true only when the
 	// request(s) are being sent to the kube-apiserver.
 	EnableShutdownResponseHeader bool
+
+	// Target is the address (host:port) exercised by the test, if empty the
+	// host of the rest Config is used.  The credentials of the rest Config
+	// are never sent to a Target.
+	// For the dns protocol it is the DNS server, and it is required.
+	Target string
+
+	// TLSConfig is used by the tls, grpc, and websocket protocols, and by
+	// the http protocols with a Target.  If nil, the TLS configuration of
+	// the rest Config is used, or the system roots for a Target.
+	TLSConfig *tls.Config
+
+	// Plaintext disables TLS for the grpc, websocket, and http protocols.
+	Plaintext bool
+
+	// DNSName and DNSRecordType are the name and the type of record (A,
+	// AAAA, or SRV) that the dns protocol resolves.
+	DNSName       string
+	DNSRecordType probe.DNSRecordType
+
+	// GRPCService is the service name sent in the gRPC health check, if
+	// empty the health of the server as a whole is checked.
+	GRPCService string
+
+	// Watch tunes how the watch protocol deems the watch disrupted, the
+	// zero value uses the defaults of the watch prober.
+	Watch probe.WatchOptions
 }
 
 // TestDescriptor defines the disruption test type, the user must
@@ -82,8 +124,8 @@ type TestDescriptor struct {
 	// by the requests should be new or reused.
 	ConnectionType monitorapi.BackendConnectionType
 
-	// Protocol specifies the protocol used by the test,
-	// whether it is http/1x or http/2.0
+	// Protocol specifies the protocol used by the test, whether it is
+	// http/1x or http/2.0, or one of the protocols sampled by a Prober.
 	Protocol backend.ProtocolType
 }
 
@@ -119,6 +161,9 @@ func (t TestDescriptor) Validate() error {
 	if len(t.TargetServer) == 0 {
 		return fmt.Errorf("TargetServer must have a valid value")
 	}
+	if t.ConnectionType == monitorapi.ReusedConnectionType && t.Protocol.DialsEverySample() {
+		return fmt.Errorf("the %s protocol opens a new connection for every sample, it can not use %s connections", t.Protocol, t.ConnectionType)
+	}
 	return nil
 }
 func (t TestDescriptor) GetLoadBalancerType() backend.LoadBalancerType       { return t.LoadBalancerType }
@@ -133,6 +178,10 @@ type dependency interface {
 	// to send requests to the target server.
 	NewTransport(TestConfiguration) (http.RoundTripper, error)
 
+	// NewProber returns a new Prober that exercises the target server
+	// for the protocols that are not HTTP.
+	NewProber(TestConfiguration) (backendsampler.Prober, error)
+
 	// HostName returns the host name in order to connect to the target server.
 	HostName() string
 
@@ -166,6 +215,9 @@ func (b *testFactory) New(c TestConfiguration) (Sampler, error) {
 		b.sharedShutdownInterval, b.wantMonitorAndRecorder = shutdown.NewSharedShutdownIntervalTracker(nil, c, nil, nil)
 		b.hostNameDecoder, b.err = b.dependency.GetHostNameDecoder()
 	})
+	if !c.Protocol.IsHTTP() {
+		return b.newProbeSampler(c)
+	}
 
 	rt, err := b.dependency.NewTransport(c)
 	if err != nil {
@@ -181,10 +233,15 @@ func (b *testFactory) New(c TestConfiguration) (Sampler, error) {
 	if err != nil {
 		return nil, err
 	}
-	requestor := backendsampler.NewHostPathRequestor(b.dependency.HostName(), c.Path)
+	host := b.dependency.HostName()
+	if len(c.Target) > 0 {
+		host = c.targetHost()
+	}
+	requestor := backendsampler.NewHostPathRequestor(host, c.Path)
 
 	// we don't have access to the monitor and event recorder yet
 	collector, want := disruption.NewIntervalTracker(b.sharedShutdownInterval, c, nil, nil)
+	collector, latencyWant := latency.NewTracker(collector, c, c.DegradedLatencyThreshold, nil, nil)
 	collector = logger.NewLogger(collector, c)
 
 	pc := backendsampler.NewSampleProducerConsumer(client, requestor, backendsampler.NewResponseChecker(), collector)
@@ -192,13 +249,43 @@ func (b *testFactory) New(c TestConfiguration) (Sampler, error) {
 	backendSampler := &BackendSampler{
 		TestConfiguration:           c,
 		SampleRunner:                runner,
-		wantEventRecorderAndMonitor: []backend.WantEventRecorderAndMonitorRecorder{b.wantMonitorAndRecorder, want},
+		wantEventRecorderAndMonitor: []backend.WantEventRecorderAndMonitorRecorder{b.wantMonitorAndRecorder, want, latencyWant},
 		baseURL:                     requestor.GetBaseURL(),
 		hostNameDecoder:             b.hostNameDecoder,
 	}
 	return backendSampler, nil
 }
 
+// newProbeSampler returns a disruption test instance that exercises the
+// target server with a Prober, the samples are tracked exactly the same
+// way as the HTTP requests.
+func (b *testFactory) newProbeSampler(c TestConfiguration) (Sampler, error) {
+	prober, err := b.dependency.NewProber(c)
+	if err != nil {
+		return nil, err
+	}
+
+	collector, want := disruption.NewIntervalTracker(b.sharedShutdownInterval, c, nil, nil)
+	wants := []backend.WantEventRecorderAndMonitorRecorder{b.wantMonitorAndRecorder, want}
+	// a watch sample lasts as long as the watch stays open, its
+	// duration is not a round trip latency.
+	if c.Protocol != backend.ProtocolWatch {
+		var latencyWant backend.WantEventRecorderAndMonitorRecorder
+		collector, latencyWant = latency.NewTracker(collector, c, c.DegradedLatencyThreshold, nil, nil)
+		wants = append(wants, latencyWant)
+	}
+	collector = logger.NewLogger(collector, c)
+
+	pc := backendsampler.NewProbeProducerConsumer(prober, c.Timeout, collector)
+	runner := sampler.NewWithProducerConsumer(c.SampleInterval, pc)
+	return &BackendSampler{
+		TestConfiguration:           c,
+		SampleRunner:                runner,
+		wantEventRecorderAndMonitor: wants,
+		baseURL:                     prober.GetBaseURL(),
+	}, nil
+}
+
 // restConfigDependency is used by the factory when we want to create
 // a disruption test instance from a rest Config.
 type restConfigDependency struct {
@@ -215,6 +302,10 @@ func (r *restConfigDependency) NewTransport(tc TestConfiguration) (http.RoundTri
 		useHTTP1 = true
 	}
 
+	if len(tc.Target) > 0 {
+		// the Target is not the apiserver, we don't hand it the credentials.
+		return transport.FromTLSConfig(tc.TLSConfig, reuseConnection, tc.Timeout, useHTTP1), nil
+	}
 	rt, err := transport.FromRestConfig(r.config, reuseConnection, tc.Timeout, useHTTP1)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create transport - %v", err)
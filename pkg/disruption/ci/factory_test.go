@@ -12,6 +12,8 @@ import (
 	"github.com/openshift/origin/pkg/monitor"
 
 	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/backend/probe"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -121,6 +123,55 @@ func TestBackendSampler(t *testing.T) {
 	<-monitorErrCh2
 }
 
+func TestProbeSampler(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	factory := &testFactory{
+		dependency: &testServerDependency{
+			server: ts,
+		},
+	}
+	bs, err := factory.New(TestConfiguration{
+		TestDescriptor: TestDescriptor{
+			TargetServer:     "test-server",
+			LoadBalancerType: backend.ExternalLoadBalancerType,
+			ConnectionType:   monitorapi.NewConnectionType,
+			Protocol:         backend.ProtocolTCP,
+		},
+		Timeout:        time.Second,
+		SampleInterval: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("failed to build probe sampler: %v", err)
+	}
+	if url, _ := bs.GetURL(); url != "tcp://"+ts.Listener.Addr().String() {
+		t.Errorf("unexpected url: %s", url)
+	}
+
+	recorder := monitor.NewRecorder()
+	monitorErrCh := make(chan error, 1)
+	go func() {
+		monitorErrCh <- bs.RunEndpointMonitoring(context.Background(), recorder, &fakeRecorder{})
+	}()
+	<-time.After(time.Second)
+	ts.Close()
+	<-time.After(time.Second)
+	bs.Stop()
+	<-monitorErrCh
+
+	var disrupted bool
+	for _, interval := range recorder.Intervals(time.Time{}, time.Time{}) {
+		if interval.Source == monitorapi.SourceDisruption && interval.Level == monitorapi.Error &&
+			interval.StructuredLocator.OldLocator() == bs.GetLocator().OldLocator() {
+			disrupted = true
+		}
+	}
+	if !disrupted {
+		t.Errorf("expected a disruption interval after the server was closed")
+	}
+}
+
 type testServerDependency struct {
 	server *httptest.Server
 }
@@ -140,6 +191,9 @@ func (d *testServerDependency) NewTransport(tc TestConfiguration) (http.RoundTri
 
 	return transport, nil
 }
+func (d *testServerDependency) NewProber(tc TestConfiguration) (backendsampler.Prober, error) {
+	return probe.NewTCPConnectProber(d.server.Listener.Addr().String()), nil
+}
 func (d *testServerDependency) HostName() string { return d.server.URL }
 func (d *testServerDependency) GetHostNameDecoder() (backend.HostNameDecoderWithRunner, error) {
 	return nil, nil
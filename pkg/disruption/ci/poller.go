@@ -0,0 +1,54 @@
+package ci
+
+import (
+	"crypto/tls"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend/probe"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
+)
+
+// NewPollerTestConfigurations returns a disruption test configuration for
+// each backend and connection type of the given in-cluster poller.
+// The backend name is the TargetServer, so a backend named my-ingress
+// polled over new connections through the service network is recorded as
+// my-ingress-http1-service-network-new-connections.  A backend without a
+// threshold of its own uses the given degradedLatencyThreshold.
+func NewPollerTestConfigurations(poller backendsampler.InClusterPoller, degradedLatencyThreshold time.Duration) []TestConfiguration {
+	var configurations []TestConfiguration
+	for _, b := range poller.Backends {
+		var tlsConfig *tls.Config
+		if b.InsecureSkipTLSVerify {
+			tlsConfig = &tls.Config{InsecureSkipVerify: true}
+		}
+		threshold := degradedLatencyThreshold
+		if b.DegradedLatencyThreshold.Duration > 0 {
+			threshold = b.DegradedLatencyThreshold.Duration
+		}
+		recordType := probe.DNSRecordType(b.DNSRecordType)
+		if len(recordType) == 0 {
+			recordType = probe.DNSRecordA
+		}
+		for _, connectionType := range b.ConnectionTypes {
+			configurations = append(configurations, TestConfiguration{
+				TestDescriptor: TestDescriptor{
+					TargetServer:     ServerNameType(b.Name),
+					LoadBalancerType: poller.LoadBalancerType,
+					ConnectionType:   connectionType,
+					Protocol:         b.Protocol,
+				},
+				Path:                     b.Path,
+				Timeout:                  b.Timeout.Duration,
+				SampleInterval:           b.Interval.Duration,
+				DegradedLatencyThreshold: threshold,
+				Target:                   b.Target,
+				TLSConfig:                tlsConfig,
+				Plaintext:                b.Plaintext,
+				DNSName:                  b.DNSName,
+				DNSRecordType:            recordType,
+				GRPCService:              b.GRPCService,
+			})
+		}
+	}
+	return configurations
+}
@@ -0,0 +1,83 @@
+package ci
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/backend/probe"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewPollerTestConfigurations(t *testing.T) {
+	poller := backendsampler.InClusterPoller{
+		Name:             "ingress-monitor",
+		LoadBalancerType: backend.ServiceNetworkType,
+		Backends: []backendsampler.InClusterBackend{
+			{
+				Name:                  "my-ingress",
+				Target:                "router-internal-default.openshift-ingress.svc:443",
+				Path:                  "/healthz",
+				Protocol:              backend.ProtocolHTTP1,
+				ConnectionTypes:       []monitorapi.BackendConnectionType{monitorapi.NewConnectionType, monitorapi.ReusedConnectionType},
+				Interval:              metav1.Duration{Duration: 2 * time.Second},
+				Timeout:               metav1.Duration{Duration: 5 * time.Second},
+				InsecureSkipTLSVerify: true,
+				// the ingress has its own threshold
+				DegradedLatencyThreshold: metav1.Duration{Duration: 500 * time.Millisecond},
+			},
+			{
+				Name:            "cluster-dns",
+				Target:          "172.30.0.10:53",
+				Protocol:        backend.ProtocolDNS,
+				ConnectionTypes: []monitorapi.BackendConnectionType{monitorapi.NewConnectionType},
+				DNSName:         "kubernetes.default.svc.cluster.local.",
+			},
+		},
+	}
+
+	configurations := NewPollerTestConfigurations(poller, 2*time.Second)
+	names := []string{}
+	for _, tc := range configurations {
+		if err := tc.Validate(); err != nil {
+			t.Errorf("%s: expected a valid configuration, but got: %v", tc.Name(), err)
+		}
+		names = append(names, tc.Name())
+	}
+	want := []string{
+		"my-ingress-http1-service-network-new-connections",
+		"my-ingress-http1-service-network-reused-connections",
+		"cluster-dns-dns-service-network-new-connections",
+	}
+	if len(names) != len(want) {
+		t.Fatalf("expected %v, but got: %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("expected %v, but got: %v", want, names)
+		}
+	}
+
+	ingress := configurations[0]
+	if ingress.SampleInterval != 2*time.Second || ingress.Timeout != 5*time.Second || ingress.Path != "/healthz" {
+		t.Errorf("unexpected configuration: %+v", ingress)
+	}
+	if ingress.TLSConfig == nil || !ingress.TLSConfig.InsecureSkipVerify {
+		t.Errorf("expected the certificate of the target not to be verified")
+	}
+	if host := ingress.targetHost(); host != "https://router-internal-default.openshift-ingress.svc:443" {
+		t.Errorf("unexpected target host: %s", host)
+	}
+	if ingress.DegradedLatencyThreshold != 500*time.Millisecond {
+		t.Errorf("expected the threshold of the backend, but got: %s", ingress.DegradedLatencyThreshold)
+	}
+	if dns := configurations[2]; dns.DNSRecordType != probe.DNSRecordA || dns.TLSConfig != nil {
+		t.Errorf("expected an A record, and no TLS configuration: %+v", dns)
+	}
+	if dns := configurations[2]; dns.DegradedLatencyThreshold != 2*time.Second {
+		t.Errorf("expected the threshold of the run, but got: %s", dns.DegradedLatencyThreshold)
+	}
+}
@@ -0,0 +1,154 @@
+package ci
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	"github.com/openshift/origin/pkg/disruption/backend/probe"
+	backendsampler "github.com/openshift/origin/pkg/disruption/backend/sampler"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	"k8s.io/client-go/transport"
+)
+
+func (r *restConfigDependency) NewProber(tc TestConfiguration) (backendsampler.Prober, error) {
+	if tc.Protocol == backend.ProtocolDNS {
+		if len(tc.Target) == 0 {
+			return nil, fmt.Errorf("Target must be the DNS server for the %s protocol", tc.Protocol)
+		}
+		return probe.NewDNSProber(tc.Target, tc.DNSName, tc.DNSRecordType)
+	}
+
+	if tc.Protocol == backend.ProtocolWatch {
+		// the connection type decides whether every watch opens
+		// a new connection, or they share a single one.
+		rt, err := r.NewTransport(tc)
+		if err != nil {
+			return nil, err
+		}
+		host := r.config.Host
+		if len(tc.Target) > 0 {
+			host = tc.targetHost()
+		}
+		return probe.NewWatchProber(&http.Client{Transport: rt}, host, tc.Path, tc.Watch), nil
+	}
+
+	target := tc.Target
+	if len(target) == 0 {
+		var err error
+		if target, err = hostPortFromURL(r.config.Host); err != nil {
+			return nil, err
+		}
+	}
+
+	var tlsConfig *tls.Config
+	if !tc.Plaintext {
+		var err error
+		if tlsConfig, err = r.tlsConfigFor(tc); err != nil {
+			return nil, err
+		}
+	}
+	reuseConnection := tc.ConnectionType == monitorapi.ReusedConnectionType
+
+	switch tc.Protocol {
+	case backend.ProtocolTCP:
+		return probe.NewTCPConnectProber(target), nil
+	case backend.ProtocolTLS:
+		if tlsConfig == nil {
+			return nil, fmt.Errorf("the %s protocol can not be used with Plaintext", tc.Protocol)
+		}
+		return probe.NewTLSHandshakeProber(target, tlsConfig), nil
+	case backend.ProtocolGRPC:
+		return probe.NewGRPCHealthProber(target, tc.GRPCService, tlsConfig, reuseConnection), nil
+	case backend.ProtocolWebSocket:
+		scheme := "wss"
+		if tlsConfig == nil {
+			scheme = "ws"
+		}
+		header := http.Header{}
+		// we only hand the token to the server of the rest Config.
+		if len(tc.Target) == 0 {
+			token, err := r.bearerToken()
+			if err != nil {
+				return nil, err
+			}
+			if len(token) > 0 {
+				header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		return probe.NewWebSocketProber(fmt.Sprintf("%s://%s%s", scheme, target, tc.Path), header, tlsConfig, reuseConnection), nil
+	}
+	return nil, fmt.Errorf("no prober for the %s protocol", tc.Protocol)
+}
+
+func (r *restConfigDependency) tlsConfigFor(tc TestConfiguration) (*tls.Config, error) {
+	if tc.TLSConfig != nil {
+		return tc.TLSConfig, nil
+	}
+	if len(tc.Target) > 0 {
+		// the Target is not the apiserver, we don't hand it the
+		// credentials, and it is verified against the system roots.
+		return &tls.Config{}, nil
+	}
+	kubeTransportConfig, err := r.config.TransportConfig()
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := transport.TLSConfigFor(kubeTransportConfig)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	return tlsConfig, nil
+}
+
+func (r *restConfigDependency) bearerToken() (string, error) {
+	if len(r.config.BearerToken) > 0 {
+		return r.config.BearerToken, nil
+	}
+	if len(r.config.BearerTokenFile) == 0 {
+		return "", nil
+	}
+	token, err := os.ReadFile(r.config.BearerTokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the bearer token - %v", err)
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+// targetHost returns the scheme and the host of the Target, for the
+// protocols that send HTTP requests.
+func (tc TestConfiguration) targetHost() string {
+	if tc.Plaintext {
+		return fmt.Sprintf("http://%s", tc.Target)
+	}
+	return fmt.Sprintf("https://%s", tc.Target)
+}
+
+// hostPortFromURL returns the host:port of the given rest Config host,
+// which may or may not have a scheme.
+func hostPortFromURL(host string) (string, error) {
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	u, err := url.Parse(host)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse host %q - %v", host, err)
+	}
+	if len(u.Port()) > 0 {
+		return u.Host, nil
+	}
+	port := "443"
+	if u.Scheme == "http" {
+		port = "80"
+	}
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
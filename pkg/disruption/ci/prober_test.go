@@ -0,0 +1,61 @@
+package ci
+
+import (
+	"testing"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/cert"
+)
+
+func TestTLSConfigFor(t *testing.T) {
+	certData, keyData, err := cert.GenerateSelfSignedCertKey("localhost", nil, nil)
+	if err != nil {
+		t.Fatalf("failed to generate the certificate: %v", err)
+	}
+	dependency := &restConfigDependency{
+		config: &rest.Config{
+			Host: "https://api.cluster:6443",
+			TLSClientConfig: rest.TLSClientConfig{
+				CAData:   certData,
+				CertData: certData,
+				KeyData:  keyData,
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		target           string
+		wantCredentials  bool
+		wantClusterRoots bool
+	}{
+		{
+			name:             "apiserver",
+			wantCredentials:  true,
+			wantClusterRoots: true,
+		},
+		{
+			name:   "target",
+			target: "router-internal-default.openshift-ingress.svc:443",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tlsConfig, err := dependency.tlsConfigFor(TestConfiguration{
+				TestDescriptor: TestDescriptor{Protocol: backend.ProtocolTLS},
+				Target:         test.target,
+			})
+			if err != nil {
+				t.Fatalf("expected no error, but got: %v", err)
+			}
+			if hasCredentials := len(tlsConfig.Certificates) > 0 || tlsConfig.GetClientCertificate != nil; hasCredentials != test.wantCredentials {
+				t.Errorf("expected client credentials: %t, but got: %t", test.wantCredentials, hasCredentials)
+			}
+			if hasClusterRoots := tlsConfig.RootCAs != nil; hasClusterRoots != test.wantClusterRoots {
+				t.Errorf("expected the cluster roots: %t, but got: %t", test.wantClusterRoots, hasClusterRoots)
+			}
+		})
+	}
+}
@@ -19,9 +19,10 @@ type testRemoteFactory struct {
 
 // RemoteSampler has the machinery to start disruption monitor in the cluster
 type RemoteSampler struct {
-	lock   sync.Mutex
-	cancel context.CancelFunc
-	config *rest.Config
+	lock     sync.Mutex
+	cancel   context.CancelFunc
+	config   *rest.Config
+	monitors *sampler.InClusterMonitorConfiguration
 }
 
 func (bs *RemoteSampler) GetTargetServerName() string {
@@ -80,7 +81,7 @@ func (bs *RemoteSampler) RunEndpointMonitoring(ctx context.Context, m monitorapi
 
 func (bs *RemoteSampler) StartEndpointMonitoring(ctx context.Context, m monitorapi.RecorderWriter, eventRecorder events.EventRecorder) error {
 	framework.Logf("DisruptionTest: starting in-cluster monitors")
-	return sampler.StartInClusterMonitors(ctx, bs.config)
+	return sampler.StartInClusterMonitors(ctx, bs.config, bs.monitors)
 }
 
 func (bs *RemoteSampler) Stop() {
@@ -107,5 +108,9 @@ func (b *testRemoteFactory) New(_ TestConfiguration) (Sampler, error) {
 	if b.err != nil {
 		return nil, b.err
 	}
-	return &RemoteSampler{config: b.dependency.GetRestConfig()}, nil
+	monitors, err := sampler.InClusterMonitorConfigurationFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return &RemoteSampler{config: b.dependency.GetRestConfig(), monitors: monitors}, nil
 }
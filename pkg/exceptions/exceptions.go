@@ -0,0 +1,218 @@
+package exceptions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// Exception records why a known problem is allowed, who owns getting it fixed, and when the allowance should be
+// revisited.  Exceptions that expire keep allowing the problem, but are reported so the allow-lists do not grow
+// forever.
+type Exception struct {
+	// Owner is the team or person responsible for removing the exception, for instance sig-network.
+	Owner string `json:"owner,omitempty"`
+	// Bug links the bug tracking the fix.
+	Bug string `json:"bug,omitempty"`
+	// Expires is the date, 2006-01-02, or time, RFC3339, after which the exception is reported as expired.
+	Expires string `json:"expires,omitempty"`
+}
+
+// Validate checks that the exception has an owner, a bug, and an expiry date that can be parsed.
+func (e Exception) Validate() error {
+	if len(e.Owner) == 0 {
+		return fmt.Errorf("an owner is required")
+	}
+	if len(e.Bug) == 0 {
+		return fmt.Errorf("a bug is required")
+	}
+	if len(e.Expires) == 0 {
+		return fmt.Errorf("an expiry date is required")
+	}
+	if _, err := e.ExpiresAt(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ExpiresAt returns when the exception expires, or the zero time if it never does.  A date expires at the end of the
+// day in UTC.
+func (e Exception) ExpiresAt() (time.Time, error) {
+	if len(e.Expires) == 0 {
+		return time.Time{}, nil
+	}
+	if expires, err := time.Parse("2006-01-02", e.Expires); err == nil {
+		return expires.Add(24 * time.Hour), nil
+	}
+	expires, err := time.Parse(time.RFC3339, e.Expires)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expires %q, expected 2006-01-02 or RFC3339", e.Expires)
+	}
+	return expires, nil
+}
+
+// Expired returns true if the exception has an expiry date at or before now.
+func (e Exception) Expired(now time.Time) bool {
+	expires, err := e.ExpiresAt()
+	if err != nil || expires.IsZero() {
+		return false
+	}
+	return !now.Before(expires)
+}
+
+func (e Exception) String() string {
+	bug := e.Bug
+	if len(bug) == 0 {
+		bug = "no bug"
+	}
+	return fmt.Sprintf("owner=%s bug=%s expires=%s", e.Owner, bug, e.Expires)
+}
+
+// Registry tracks the exceptions of one allow-list and which of them matched during a run.  It is safe for
+// concurrent use.
+type Registry struct {
+	// subsystem names the allow-list in test names, for instance "pathological event".
+	subsystem string
+
+	lock       sync.Mutex
+	exceptions map[string]Exception
+	matched    map[string]bool
+	// invalid describes the exceptions that were rejected by Add because they failed validation.
+	invalid map[string]string
+}
+
+func NewRegistry(subsystem string) *Registry {
+	return &Registry{
+		subsystem:  subsystem,
+		exceptions: map[string]Exception{},
+		matched:    map[string]bool{},
+		invalid:    map[string]string{},
+	}
+}
+
+// Add registers an exception by a name that is unique within the registry.  Exceptions that fail validation are
+// not registered, but are reported by JUnits.
+func (r *Registry) Add(name string, exception Exception) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if len(name) == 0 {
+		return fmt.Errorf("must specify a name for %s exceptions", r.subsystem)
+	}
+	if _, ok := r.exceptions[name]; ok {
+		return fmt.Errorf("%s exception %q is already registered", r.subsystem, name)
+	}
+	if err := exception.Validate(); err != nil {
+		r.invalid[name] = fmt.Sprintf("%s %s: %v", name, exception, err)
+		return fmt.Errorf("%s exception %q: %w", r.subsystem, name, err)
+	}
+	r.exceptions[name] = exception
+	return nil
+}
+
+func (r *Registry) AddOrDie(name string, exception Exception) {
+	if err := r.Add(name, exception); err != nil {
+		panic(err)
+	}
+}
+
+// Matched records that the named exception allowed something during the run.  Unknown names are ignored.
+func (r *Registry) Matched(name string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.exceptions[name]; ok {
+		r.matched[name] = true
+	}
+}
+
+// Expired returns a description of every exception that expired at or before now, sorted by name.
+func (r *Registry) Expired(now time.Time) []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	ret := []string{}
+	for name, exception := range r.exceptions {
+		if exception.Expired(now) {
+			ret = append(ret, fmt.Sprintf("%s %s", name, exception))
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Stale returns a description of every exception that never matched, sorted by name.
+func (r *Registry) Stale() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	ret := []string{}
+	for name, exception := range r.exceptions {
+		if !r.matched[name] {
+			ret = append(ret, fmt.Sprintf("%s %s", name, exception))
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Invalid returns a description of every exception that was rejected because it failed validation, sorted by name.
+func (r *Registry) Invalid() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	ret := []string{}
+	for _, description := range r.invalid {
+		ret = append(ret, description)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// JUnits reports the expired and invalid exceptions as a flake, a failure and a success for the same test, so they
+// are noticed without failing the run.  Stale exceptions are listed in the output of a passing test, one run is not enough to
+// tell an exception is no longer needed, but they can be aggregated across runs.
+func (r *Registry) JUnits(now time.Time) []*junitapi.JUnitTestCase {
+	expiredTestName := fmt.Sprintf("[sig-arch] %s exceptions should not be past their expiry date", r.subsystem)
+	staleTestName := fmt.Sprintf("[sig-arch] %s exceptions should match during the run", r.subsystem)
+	invalidTestName := fmt.Sprintf("[sig-arch] %s exceptions should have an owner, bug and expiry date", r.subsystem)
+
+	ret := []*junitapi.JUnitTestCase{}
+	if expired := r.Expired(now); len(expired) > 0 {
+		output := fmt.Sprintf("%d %s exceptions have expired, fix the bugs and remove them or extend them:\n\n%s",
+			len(expired), r.subsystem, strings.Join(expired, "\n"))
+		ret = append(ret, &junitapi.JUnitTestCase{
+			Name: expiredTestName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: output,
+			},
+			SystemOut: output,
+		})
+	}
+	ret = append(ret, &junitapi.JUnitTestCase{Name: expiredTestName})
+
+	if invalid := r.Invalid(); len(invalid) > 0 {
+		output := fmt.Sprintf("%d %s exceptions are invalid and were ignored, add the missing owner, bug or expiry date:\n\n%s",
+			len(invalid), r.subsystem, strings.Join(invalid, "\n"))
+		ret = append(ret, &junitapi.JUnitTestCase{
+			Name: invalidTestName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: output,
+			},
+			SystemOut: output,
+		})
+	}
+	ret = append(ret, &junitapi.JUnitTestCase{Name: invalidTestName})
+
+	staleTest := &junitapi.JUnitTestCase{Name: staleTestName}
+	if stale := r.Stale(); len(stale) > 0 {
+		staleTest.SystemOut = fmt.Sprintf("%d %s exceptions did not match anything during the run and may be stale:\n\n%s",
+			len(stale), r.subsystem, strings.Join(stale, "\n"))
+	}
+	ret = append(ret, staleTest)
+	return ret
+}
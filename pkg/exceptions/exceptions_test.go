@@ -0,0 +1,91 @@
+package exceptions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExceptionExpired(t *testing.T) {
+	now := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		expires  string
+		expected bool
+	}{
+		{name: "never", expires: "", expected: false},
+		{name: "end of today", expires: "2024-01-31", expected: false},
+		{name: "yesterday", expires: "2024-01-30", expected: true},
+		{name: "earlier today", expires: "2024-01-31T11:00:00Z", expected: true},
+		{name: "later today", expires: "2024-01-31T13:00:00Z", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Exception{Expires: tt.expires}.Expired(now))
+		})
+	}
+}
+
+func TestExceptionValidate(t *testing.T) {
+	assert.NoError(t, Exception{Owner: "sig-network", Bug: "https://bugs/1", Expires: "2024-01-31"}.Validate())
+	assert.ErrorContains(t, Exception{Bug: "https://bugs/1", Expires: "2024-01-31"}.Validate(), "an owner is required")
+	assert.ErrorContains(t, Exception{Owner: "sig-network", Expires: "2024-01-31"}.Validate(), "a bug is required")
+	assert.ErrorContains(t, Exception{Owner: "sig-network", Bug: "https://bugs/1"}.Validate(), "an expiry date is required")
+	assert.ErrorContains(t, Exception{Owner: "sig-network", Bug: "https://bugs/1", Expires: "soon"}.Validate(), "invalid expires")
+}
+
+func TestRegistry(t *testing.T) {
+	now := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	registry := NewRegistry("widget")
+	registry.AddOrDie("expired-matched", Exception{Owner: "sig-a", Bug: "https://bugs/1", Expires: "2024-01-01"})
+	registry.AddOrDie("expired-stale", Exception{Owner: "sig-b", Bug: "https://bugs/2", Expires: "2024-01-02"})
+	registry.AddOrDie("current", Exception{Owner: "sig-c", Bug: "https://bugs/3", Expires: "2024-06-01"})
+
+	require.ErrorContains(t, registry.Add("current", Exception{Owner: "sig-c", Bug: "https://bugs/3", Expires: "2024-06-01"}), "already registered")
+	require.ErrorContains(t, registry.Add("no-owner", Exception{Expires: "2024-06-01"}), "an owner is required")
+
+	registry.Matched("expired-matched")
+	registry.Matched("current")
+	registry.Matched("unknown")
+
+	assert.Equal(t, []string{
+		"expired-matched owner=sig-a bug=https://bugs/1 expires=2024-01-01",
+		"expired-stale owner=sig-b bug=https://bugs/2 expires=2024-01-02",
+	}, registry.Expired(now))
+	assert.Equal(t, []string{
+		"expired-stale owner=sig-b bug=https://bugs/2 expires=2024-01-02",
+	}, registry.Stale())
+	assert.Equal(t, []string{
+		"no-owner owner= bug=no bug expires=2024-06-01: an owner is required",
+	}, registry.Invalid())
+
+	junits := registry.JUnits(now)
+	require.Len(t, junits, 5)
+	// a failure and a success for the same test is a flake.
+	assert.Equal(t, "[sig-arch] widget exceptions should not be past their expiry date", junits[0].Name)
+	require.NotNil(t, junits[0].FailureOutput)
+	assert.Contains(t, junits[0].FailureOutput.Output, "expired-stale")
+	assert.Equal(t, junits[0].Name, junits[1].Name)
+	assert.Nil(t, junits[1].FailureOutput)
+	assert.Equal(t, "[sig-arch] widget exceptions should have an owner, bug and expiry date", junits[2].Name)
+	require.NotNil(t, junits[2].FailureOutput)
+	assert.Contains(t, junits[2].FailureOutput.Output, "no-owner")
+	assert.Equal(t, junits[2].Name, junits[3].Name)
+	assert.Nil(t, junits[3].FailureOutput)
+	assert.Equal(t, "[sig-arch] widget exceptions should match during the run", junits[4].Name)
+	assert.Nil(t, junits[4].FailureOutput)
+	assert.Contains(t, junits[4].SystemOut, "expired-stale")
+
+	// nothing expired and everything matched passes without output.
+	registry = NewRegistry("widget")
+	registry.AddOrDie("current", Exception{Owner: "sig-c", Bug: "https://bugs/3", Expires: "2024-06-01"})
+	registry.Matched("current")
+	junits = registry.JUnits(now)
+	require.Len(t, junits, 3)
+	for _, junit := range junits {
+		assert.Nil(t, junit.FailureOutput)
+		assert.Empty(t, junit.SystemOut)
+	}
+}
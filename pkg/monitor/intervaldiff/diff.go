@@ -0,0 +1,278 @@
+// Package intervaldiff compares the intervals of two runs, usually of two payloads, and reports the disruption, alerts,
+// pathological events, and other intervals that appear or grow in the second run.
+//
+// Runs never share UIDs, generated pod names, or IPs, so locators are normalized before they are compared, and every
+// time is relative to the start of its own run.
+package intervaldiff
+
+import (
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// kubernetes generates names and hashes from this alphabet, which has no vowels so that words are not generated.
+const generatedNameAlphabet = "[bcdfghjklmnpqrstvwxz2456789]"
+
+var (
+	// deployment pods are <deployment>-<pod-template-hash>-<suffix>.
+	deploymentPodNameRegex = regexp.MustCompile(`-` + generatedNameAlphabet + `{6,10}-` + generatedNameAlphabet + `{5}$`)
+	// daemonset, job, and generateName pods are <name>-<suffix>.
+	generatedNameRegex = regexp.MustCompile(`-` + generatedNameAlphabet + `{5}$`)
+	// anything that might be an address.  Candidates are only replaced when they parse as one.
+	ipCandidateRegex = regexp.MustCompile(`[0-9a-fA-F.:]*[.:][0-9a-fA-F.:]*`)
+)
+
+// NormalizeLocator returns a locator that matches the same thing in another run.  UIDs are dropped, generated pod name
+// suffixes become <hash>, and IPs become <ip>.
+func NormalizeLocator(locator monitorapi.Locator) string {
+	keys := []string{}
+	for k := range locator.Keys {
+		if k == monitorapi.LocatorUIDKey {
+			continue
+		}
+		keys = append(keys, string(k))
+	}
+	sort.Strings(keys)
+
+	parts := []string{}
+	if len(locator.Type) > 0 {
+		parts = append(parts, string(locator.Type))
+	}
+	for _, k := range keys {
+		parts = append(parts, k+"/"+normalizeValue(locator.Keys[monitorapi.LocatorKey(k)]))
+	}
+	return strings.Join(parts, " ")
+}
+
+func normalizeValue(value string) string {
+	value = ipCandidateRegex.ReplaceAllStringFunc(value, func(candidate string) string {
+		if net.ParseIP(candidate) != nil {
+			return "<ip>"
+		}
+		if host, port, err := net.SplitHostPort(candidate); err == nil && net.ParseIP(host) != nil {
+			return "<ip>:" + port
+		}
+		return candidate
+	})
+	if deploymentPodNameRegex.MatchString(value) {
+		return deploymentPodNameRegex.ReplaceAllString(value, "-<hash>")
+	}
+	return generatedNameRegex.ReplaceAllString(value, "-<hash>")
+}
+
+// Run is one side of the comparison.
+type Run struct {
+	Name      string
+	Intervals monitorapi.Intervals
+}
+
+// RunSummary describes one side of the comparison.
+type RunSummary struct {
+	Name      string        `json:"name"`
+	Start     time.Time     `json:"start"`
+	Duration  time.Duration `json:"duration"`
+	Intervals int           `json:"intervals"`
+}
+
+// Difference is one thing that appears or grows in run B.  Durations are totals over the run.  Offsets are relative to
+// the start of each run, and are only set when the thing happened in that run.
+type Difference struct {
+	Key string `json:"key"`
+
+	CountA    int           `json:"countA"`
+	CountB    int           `json:"countB"`
+	DurationA time.Duration `json:"durationA"`
+	DurationB time.Duration `json:"durationB"`
+
+	FirstOffsetA *time.Duration `json:"firstOffsetA,omitempty"`
+	FirstOffsetB *time.Duration `json:"firstOffsetB,omitempty"`
+
+	// New is set when the thing did not happen in run A at all.
+	New bool `json:"new"`
+}
+
+// Report holds everything that appears or grows in run B compared with run A, largest growth first.
+type Report struct {
+	A RunSummary `json:"a"`
+	B RunSummary `json:"b"`
+
+	// Disruption is the total disruption per backend.
+	Disruption []Difference `json:"disruption"`
+	// Alerts is the total time each alert spent pending or firing.
+	Alerts []Difference `json:"alerts"`
+	// PathologicalEvents counts are the highest repeat count of each event.
+	PathologicalEvents []Difference `json:"pathologicalEvents"`
+	// Intervals are grouped by source, normalized locator, and reason.
+	Intervals []Difference `json:"intervals"`
+}
+
+// Options tune what Compare reports.
+type Options struct {
+	// MinLevel excludes intervals below this level from Report.Intervals.  Disruption, alerts, and pathological events
+	// are always compared.
+	MinLevel monitorapi.IntervalLevel
+	// MinGrowth is the least a duration must grow by to be reported.  Any new occurrence is always reported.
+	MinGrowth time.Duration
+}
+
+// Compare returns what appears or grows in b compared with a.
+func Compare(a, b Run, options Options) *Report {
+	summaryA, summaryB := summarize(a), summarize(b)
+
+	report := &Report{
+		A:                  summaryA.RunSummary,
+		B:                  summaryB.RunSummary,
+		Disruption:         compareTotals(summaryA.disruption, summaryB.disruption, options.MinGrowth),
+		Alerts:             compareTotals(summaryA.alerts, summaryB.alerts, options.MinGrowth),
+		PathologicalEvents: compareTotals(summaryA.pathologicalEvents, summaryB.pathologicalEvents, options.MinGrowth),
+		Intervals:          compareTotals(summaryA.intervalsAtLevel(options.MinLevel), summaryB.intervalsAtLevel(options.MinLevel), options.MinGrowth),
+	}
+	return report
+}
+
+type total struct {
+	count       int
+	duration    time.Duration
+	firstOffset time.Duration
+	level       monitorapi.IntervalLevel
+}
+
+type runSummary struct {
+	RunSummary
+
+	disruption         map[string]*total
+	alerts             map[string]*total
+	pathologicalEvents map[string]*total
+	intervals          map[string]*total
+}
+
+func summarize(run Run) *runSummary {
+	ret := &runSummary{
+		RunSummary:         RunSummary{Name: run.Name, Intervals: len(run.Intervals)},
+		disruption:         map[string]*total{},
+		alerts:             map[string]*total{},
+		pathologicalEvents: map[string]*total{},
+		intervals:          map[string]*total{},
+	}
+
+	var end time.Time
+	for _, interval := range run.Intervals {
+		if !interval.From.IsZero() && (ret.Start.IsZero() || interval.From.Before(ret.Start)) {
+			ret.Start = interval.From
+		}
+		if interval.From.After(end) {
+			end = interval.From
+		}
+		if interval.To.After(end) {
+			end = interval.To
+		}
+	}
+	if !ret.Start.IsZero() {
+		ret.Duration = end.Sub(ret.Start)
+	}
+
+	add := func(totals map[string]*total, key string, interval monitorapi.Interval, count int) {
+		curr, ok := totals[key]
+		offset := interval.From.Sub(ret.Start)
+		if !ok {
+			curr = &total{firstOffset: offset}
+			totals[key] = curr
+		}
+		curr.count += count
+		if offset < curr.firstOffset {
+			curr.firstOffset = offset
+		}
+		if interval.Level > curr.level {
+			curr.level = interval.Level
+		}
+		to := interval.To
+		if to.IsZero() {
+			to = end
+		}
+		// the same one second minimum BackendDisruptionSeconds uses.
+		curr.duration += monitorapi.Intervals{{From: interval.From, To: to}}.Duration(time.Second)
+	}
+
+	for _, interval := range run.Intervals {
+		locator := NormalizeLocator(interval.StructuredLocator)
+		add(ret.intervals, strings.TrimSpace(string(interval.Source)+" "+locator+" "+string(interval.StructuredMessage.Reason)), interval, 1)
+
+		switch {
+		case interval.Source == monitorapi.SourceDisruption && monitorapi.IsErrorEvent(interval):
+			backend := interval.StructuredLocator.Keys[monitorapi.LocatorBackendDisruptionNameKey]
+			if len(backend) == 0 {
+				backend = locator
+			}
+			add(ret.disruption, backend, interval, 1)
+
+		case interval.Source == monitorapi.SourceAlert:
+			add(ret.alerts, locator+" "+interval.StructuredMessage.Annotations[monitorapi.AnnotationAlertState], interval, 1)
+
+		case interval.StructuredMessage.Annotations[monitorapi.AnnotationPathological] == "true":
+			key := locator + " " + string(interval.StructuredMessage.Reason)
+			count, _ := strconv.Atoi(interval.StructuredMessage.Annotations[monitorapi.AnnotationCount])
+			add(ret.pathologicalEvents, key, interval, 0)
+			if curr := ret.pathologicalEvents[key]; count > curr.count {
+				curr.count = count
+			}
+		}
+	}
+	return ret
+}
+
+func (s *runSummary) intervalsAtLevel(minLevel monitorapi.IntervalLevel) map[string]*total {
+	ret := map[string]*total{}
+	for key, curr := range s.intervals {
+		if curr.level >= minLevel {
+			ret[key] = curr
+		}
+	}
+	return ret
+}
+
+func compareTotals(a, b map[string]*total, minGrowth time.Duration) []Difference {
+	ret := []Difference{}
+	for key, totalB := range b {
+		totalA, inA := a[key]
+		if inA && totalB.count <= totalA.count {
+			growth := totalB.duration - totalA.duration
+			if growth <= 0 || growth < minGrowth {
+				continue
+			}
+		}
+		offsetB := totalB.firstOffset
+		diff := Difference{
+			Key:          key,
+			CountB:       totalB.count,
+			DurationB:    totalB.duration,
+			FirstOffsetB: &offsetB,
+			New:          !inA,
+		}
+		if inA {
+			offsetA := totalA.firstOffset
+			diff.CountA = totalA.count
+			diff.DurationA = totalA.duration
+			diff.FirstOffsetA = &offsetA
+		}
+		ret = append(ret, diff)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		growthI, growthJ := ret[i].DurationB-ret[i].DurationA, ret[j].DurationB-ret[j].DurationA
+		if growthI != growthJ {
+			return growthI > growthJ
+		}
+		countI, countJ := ret[i].CountB-ret[i].CountA, ret[j].CountB-ret[j].CountA
+		if countI != countJ {
+			return countI > countJ
+		}
+		return ret[i].Key < ret[j].Key
+	})
+	return ret
+}
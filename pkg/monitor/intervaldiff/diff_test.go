@@ -0,0 +1,158 @@
+package intervaldiff
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestNormalizeLocator(t *testing.T) {
+	tests := []struct {
+		name string
+		keys map[monitorapi.LocatorKey]string
+		want string
+	}{
+		{
+			name: "deployment pod",
+			keys: map[monitorapi.LocatorKey]string{"namespace": "openshift-apiserver", "pod": "apiserver-7d4f8b9c6-x2lqz", "uid": "1234"},
+			want: "Pod namespace/openshift-apiserver pod/apiserver-<hash>",
+		},
+		{
+			name: "daemonset pod",
+			keys: map[monitorapi.LocatorKey]string{"namespace": "openshift-dns", "pod": "dns-default-8vb2k"},
+			want: "Pod namespace/openshift-dns pod/dns-default-<hash>",
+		},
+		{
+			name: "static pods and words are kept",
+			keys: map[monitorapi.LocatorKey]string{"namespace": "openshift-etcd", "pod": "etcd-guard-master-0"},
+			want: "Pod namespace/openshift-etcd pod/etcd-guard-master-0",
+		},
+		{
+			name: "addresses",
+			keys: map[monitorapi.LocatorKey]string{"connection": "10.0.12.4:6443 to [fd00::1]:443 at 12:30:45"},
+			want: "Pod connection/<ip>:6443 to [<ip>]:443 at 12:30:45",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeLocator(monitorapi.Locator{Type: monitorapi.LocatorTypePod, Keys: tt.keys}); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	startA := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	startB := time.Date(2024, 2, 7, 8, 0, 0, 0, time.UTC)
+
+	disruption := func(start time.Time, offset, duration time.Duration) monitorapi.Interval {
+		return monitorapi.Interval{
+			Condition: monitorapi.Condition{
+				Level: monitorapi.Error,
+				StructuredLocator: monitorapi.Locator{Type: monitorapi.LocatorTypeDisruption, Keys: map[monitorapi.LocatorKey]string{
+					monitorapi.LocatorBackendDisruptionNameKey: "kube-api-new-connections",
+				}},
+			},
+			Source: monitorapi.SourceDisruption,
+			From:   start.Add(offset),
+			To:     start.Add(offset + duration),
+		}
+	}
+	alert := func(start time.Time, name string, offset, duration time.Duration) monitorapi.Interval {
+		return monitorapi.Interval{
+			Condition: monitorapi.Condition{
+				Level:             monitorapi.Warning,
+				StructuredLocator: monitorapi.Locator{Type: monitorapi.LocatorTypeAlert, Keys: map[monitorapi.LocatorKey]string{monitorapi.LocatorAlertKey: name}},
+				StructuredMessage: monitorapi.Message{Annotations: map[monitorapi.AnnotationKey]string{monitorapi.AnnotationAlertState: "firing"}},
+			},
+			Source: monitorapi.SourceAlert,
+			From:   start.Add(offset),
+			To:     start.Add(offset + duration),
+		}
+	}
+	pathological := func(start time.Time, pod, count string) monitorapi.Interval {
+		return monitorapi.Interval{
+			Condition: monitorapi.Condition{
+				Level: monitorapi.Info,
+				StructuredLocator: monitorapi.Locator{Type: monitorapi.LocatorTypePod, Keys: map[monitorapi.LocatorKey]string{
+					monitorapi.LocatorNamespaceKey: "openshift-dns", monitorapi.LocatorPodKey: pod,
+				}},
+				StructuredMessage: monitorapi.Message{Reason: "BackOff", Annotations: map[monitorapi.AnnotationKey]string{
+					monitorapi.AnnotationPathological: "true", monitorapi.AnnotationCount: count,
+				}},
+			},
+			Source: monitorapi.SourceKubeEvent,
+			From:   start.Add(time.Minute),
+			To:     start.Add(time.Minute),
+		}
+	}
+
+	a := Run{Name: "a", Intervals: monitorapi.Intervals{
+		disruption(startA, time.Minute, 3*time.Second),
+		alert(startA, "KubePodNotReady", time.Minute, 10*time.Minute),
+		alert(startA, "TargetDown", time.Minute, 10*time.Minute),
+		pathological(startA, "dns-default-8vb2k", "25"),
+	}}
+	b := Run{Name: "b", Intervals: monitorapi.Intervals{
+		disruption(startB, 2*time.Minute, 10*time.Second),
+		disruption(startB, 5*time.Minute, 10*time.Second),
+		alert(startB, "KubePodNotReady", time.Minute, 12*time.Second+10*time.Minute),
+		alert(startB, "TargetDown", time.Minute, 10*time.Minute+2*time.Second),
+		alert(startB, "etcdMembersDown", 3*time.Minute, time.Minute),
+		pathological(startB, "dns-default-x2lqz", "40"),
+	}}
+
+	report := Compare(a, b, Options{MinLevel: monitorapi.Warning, MinGrowth: 5 * time.Second})
+
+	if len(report.Disruption) != 1 {
+		t.Fatalf("expected disruption to grow, got %#v", report.Disruption)
+	}
+	// each run starts with its first interval, a minute after startB.
+	if d := report.Disruption[0]; d.DurationA != 3*time.Second || d.DurationB != 20*time.Second || d.CountB != 2 || *d.FirstOffsetB != time.Minute {
+		t.Errorf("unexpected disruption %#v", d)
+	}
+
+	// TargetDown grew by less than the minimum.
+	if len(report.Alerts) != 2 {
+		t.Fatalf("expected two alerts, got %#v", report.Alerts)
+	}
+	if d := report.Alerts[0]; d.Key != "Alert alert/etcdMembersDown firing" || !d.New || d.FirstOffsetA != nil {
+		t.Errorf("expected the new alert first, got %#v", d)
+	}
+	if d := report.Alerts[1]; d.Key != "Alert alert/KubePodNotReady firing" || d.New || d.DurationB-d.DurationA != 12*time.Second {
+		t.Errorf("unexpected alert %#v", d)
+	}
+
+	// the pod names differ only by their generated suffix.
+	if len(report.PathologicalEvents) != 1 {
+		t.Fatalf("expected one pathological event, got %#v", report.PathologicalEvents)
+	}
+	if d := report.PathologicalEvents[0]; d.New || d.CountA != 25 || d.CountB != 40 {
+		t.Errorf("unexpected pathological event %#v", d)
+	}
+
+	for _, d := range report.Intervals {
+		if strings.HasPrefix(d.Key, string(monitorapi.SourceKubeEvent)) {
+			t.Errorf("expected Info intervals to be left out, got %#v", d)
+		}
+	}
+
+	out := &bytes.Buffer{}
+	if err := report.WriteText(out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Alerts that appear or grow in B: 2") {
+		t.Errorf("unexpected text output:\n%s", out.String())
+	}
+	html, err := report.ToHTML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(html, []byte("Alert alert/etcdMembersDown firing")) {
+		t.Errorf("expected the html to list the new alert")
+	}
+}
@@ -0,0 +1,158 @@
+package intervaldiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+type section struct {
+	Title       string
+	Unit        string
+	Differences []Difference
+}
+
+func (r *Report) sections() []section {
+	return []section{
+		{Title: "Disruption", Unit: "duration", Differences: r.Disruption},
+		{Title: "Alerts", Unit: "duration", Differences: r.Alerts},
+		{Title: "Pathological events", Unit: "count", Differences: r.PathologicalEvents},
+		{Title: "Intervals", Unit: "duration", Differences: r.Intervals},
+	}
+}
+
+// ToJSON renders the report as json.  Durations are in nanoseconds.
+func (r *Report) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "    ")
+}
+
+// WriteText writes the report as tables.
+func (r *Report) WriteText(out io.Writer) error {
+	fmt.Fprintf(out, "A: %s, %d intervals over %s starting %s\n", r.A.Name, r.A.Intervals, r.A.Duration.Round(time.Second), r.A.Start.UTC().Format(time.RFC3339))
+	fmt.Fprintf(out, "B: %s, %d intervals over %s starting %s\n", r.B.Name, r.B.Intervals, r.B.Duration.Round(time.Second), r.B.Start.UTC().Format(time.RFC3339))
+
+	for _, section := range r.sections() {
+		fmt.Fprintf(out, "\n%s that appear or grow in B: %d\n", section.Title, len(section.Differences))
+		if len(section.Differences) == 0 {
+			continue
+		}
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(w, "KEY\tA\tB\tCHANGE\tFIRST IN A\tFIRST IN B\t\n")
+		for _, diff := range section.Differences {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t\n", diff.Key, diff.ValueA(section.Unit), diff.ValueB(section.Unit), diff.Change(section.Unit), formatOffset(diff.FirstOffsetA), formatOffset(diff.FirstOffsetB))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ToHTML renders the report as a single html page.
+func (r *Report) ToHTML() ([]byte, error) {
+	out := &bytes.Buffer{}
+	err := reportTemplate.Execute(out, struct {
+		*Report
+		Sections []section
+	}{
+		Report:   r,
+		Sections: r.sections(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// ValueA describes the count or duration in run A.
+func (d Difference) ValueA(unit string) string {
+	if d.New {
+		return "-"
+	}
+	return formatValue(unit, d.CountA, d.DurationA)
+}
+
+// ValueB describes the count or duration in run B.
+func (d Difference) ValueB(unit string) string {
+	return formatValue(unit, d.CountB, d.DurationB)
+}
+
+// Change describes how much the count or duration grew.
+func (d Difference) Change(unit string) string {
+	if d.New {
+		return "new"
+	}
+	if unit == "count" {
+		return fmt.Sprintf("+%d", d.CountB-d.CountA)
+	}
+	growth := (d.DurationB - d.DurationA).Round(time.Second)
+	change := growth.String()
+	if growth >= 0 {
+		change = "+" + change
+	}
+	if d.CountB > d.CountA {
+		change += fmt.Sprintf(" (+%dx)", d.CountB-d.CountA)
+	}
+	return change
+}
+
+func formatValue(unit string, count int, duration time.Duration) string {
+	if unit == "count" {
+		return fmt.Sprintf("%d", count)
+	}
+	return fmt.Sprintf("%s (%dx)", duration.Round(time.Second), count)
+}
+
+func formatOffset(offset *time.Duration) string {
+	if offset == nil {
+		return "-"
+	}
+	return "+" + offset.Round(time.Second).String()
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"offset": formatOffset,
+	"time": func(t time.Time) string {
+		return t.UTC().Format(time.RFC3339)
+	},
+	"round": func(d time.Duration) time.Duration {
+		return d.Round(time.Second)
+	},
+}).Parse(`<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <title>Intervals that appear or grow in {{ .B.Name }} compared with {{ .A.Name }}</title>
+    <style>
+        body { font-family: -apple-system, "Segoe UI", Roboto, Helvetica, Arial, sans-serif; font-size: 13px; margin: 16px; }
+        table { border-collapse: collapse; margin-bottom: 16px; }
+        th, td { border: 1px solid #dee2e6; padding: 3px 8px; text-align: left; vertical-align: top; }
+        th { background: #f8f9fa; }
+        td.key { font-family: monospace; word-break: break-all; max-width: 800px; }
+        tr.new td { background: #fff5f5; }
+    </style>
+</head>
+<body>
+<h1>Intervals that appear or grow in B</h1>
+<table>
+    <tr><th></th><th>Run</th><th>Start</th><th>Duration</th><th>Intervals</th></tr>
+    <tr><td>A</td><td>{{ .A.Name }}</td><td>{{ time .A.Start }}</td><td>{{ round .A.Duration }}</td><td>{{ .A.Intervals }}</td></tr>
+    <tr><td>B</td><td>{{ .B.Name }}</td><td>{{ time .B.Start }}</td><td>{{ round .B.Duration }}</td><td>{{ .B.Intervals }}</td></tr>
+</table>
+<p>Times are relative to the start of each run.  New rows did not happen in A at all.</p>
+{{ range .Sections }}{{ $unit := .Unit }}
+<h2>{{ .Title }} ({{ len .Differences }})</h2>
+{{ if .Differences }}<table>
+    <tr><th>Key</th><th>A</th><th>B</th><th>Change</th><th>First in A</th><th>First in B</th></tr>
+    {{ range .Differences }}<tr{{ if .New }} class="new"{{ end }}>
+        <td class="key">{{ .Key }}</td><td>{{ .ValueA $unit }}</td><td>{{ .ValueB $unit }}</td><td>{{ .Change $unit }}</td><td>{{ offset .FirstOffsetA }}</td><td>{{ offset .FirstOffsetB }}</td>
+    </tr>
+    {{ end }}
+</table>{{ else }}<p>Nothing.</p>{{ end }}
+{{ end }}
+</body>
+</html>
+`))
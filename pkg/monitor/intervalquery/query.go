@@ -0,0 +1,328 @@
+// Package intervalquery compiles small filter expressions over monitorapi.Interval fields into an
+// EventIntervalMatchesFunc, so new views of the intervals don't require a new Go filter function.
+//
+// An expression is made of comparisons joined by and, or, not and parentheses:
+//
+//	source=Alert and level>=Warning
+//	locator.namespace=~"^openshift-etcd" and not reason=Ready
+//	(annotation.alertstate=firing || annotation.alertstate=pending) && duration>5m
+//	from>=2023-02-14T20:33:40Z and locator.type=Disruption
+//
+// Supported fields are source, level, reason, cause, message, display, duration, from, to, locator (the
+// legacy flat locator string), locator.type, locator.<key>, and annotation.<key>.  Missing locator and
+// annotation keys compare as the empty string.
+//
+// String fields support =, ==, !=, =~ and !~ where the last two take a regular expression.  level, duration,
+// from, and to additionally support <, <=, >, and >=.  Values may be bare words or quoted with " or '.
+package intervalquery
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// Compile parses the expression and returns a function that matches the intervals it selects.
+// An empty expression matches every interval.
+func Compile(expression string) (monitorapi.EventIntervalMatchesFunc, error) {
+	if len(strings.TrimSpace(expression)) == 0 {
+		return func(monitorapi.Interval) bool { return true }, nil
+	}
+
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	matcher, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.peek().value, p.peek().position)
+	}
+	return matcher, nil
+}
+
+// MustCompile is like Compile but panics if the expression cannot be parsed.
+func MustCompile(expression string) monitorapi.EventIntervalMatchesFunc {
+	matcher, err := Compile(expression)
+	if err != nil {
+		panic(err)
+	}
+	return matcher
+}
+
+type parser struct {
+	tokens []token
+	next   int
+}
+
+func (p *parser) done() bool {
+	return p.next >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{kind: tokenEOF, position: -1}
+	}
+	return p.tokens[p.next]
+}
+
+func (p *parser) consume() token {
+	ret := p.peek()
+	p.next++
+	return ret
+}
+
+func (p *parser) parseOr() (monitorapi.EventIntervalMatchesFunc, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	filters := []monitorapi.EventIntervalMatchesFunc{left}
+	for p.peek().kind == tokenOr {
+		p.consume()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, right)
+	}
+	if len(filters) == 1 {
+		return left, nil
+	}
+	return monitorapi.Or(filters...), nil
+}
+
+func (p *parser) parseAnd() (monitorapi.EventIntervalMatchesFunc, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	filters := []monitorapi.EventIntervalMatchesFunc{left}
+	for p.peek().kind == tokenAnd {
+		p.consume()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, right)
+	}
+	if len(filters) == 1 {
+		return left, nil
+	}
+	return monitorapi.And(filters...), nil
+}
+
+func (p *parser) parseNot() (monitorapi.EventIntervalMatchesFunc, error) {
+	if p.peek().kind == tokenNot {
+		p.consume()
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return monitorapi.Not(inner), nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (monitorapi.EventIntervalMatchesFunc, error) {
+	switch curr := p.peek(); curr.kind {
+	case tokenOpenParen:
+		p.consume()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.consume(); closing.kind != tokenCloseParen {
+			return nil, fmt.Errorf("expected ) at position %d", closing.position)
+		}
+		return inner, nil
+	case tokenWord:
+		return p.parseComparison()
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", curr.value, curr.position)
+	}
+}
+
+func (p *parser) parseComparison() (monitorapi.EventIntervalMatchesFunc, error) {
+	fieldToken := p.consume()
+	operatorToken := p.consume()
+	if operatorToken.kind != tokenOperator {
+		return nil, fmt.Errorf("expected an operator after %q at position %d", fieldToken.value, operatorToken.position)
+	}
+	valueToken := p.consume()
+	if valueToken.kind != tokenWord && valueToken.kind != tokenString {
+		return nil, fmt.Errorf("expected a value after %q at position %d", operatorToken.value, valueToken.position)
+	}
+
+	matcher, err := newComparison(fieldToken.value, operatorToken.value, valueToken.value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid comparison at position %d: %w", fieldToken.position, err)
+	}
+	return matcher, nil
+}
+
+func newComparison(field, operator, value string) (monitorapi.EventIntervalMatchesFunc, error) {
+	switch {
+	case field == "level":
+		level, err := levelFromString(value)
+		if err != nil {
+			return nil, err
+		}
+		return orderedComparison(operator, func(interval monitorapi.Interval) int {
+			return compareInts(int(interval.Level), int(level))
+		})
+
+	case field == "duration":
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, err
+		}
+		return orderedComparison(operator, func(interval monitorapi.Interval) int {
+			return compareInts64(int64(interval.To.Sub(interval.From)), int64(duration))
+		})
+
+	case field == "from" || field == "to":
+		limit, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, err
+		}
+		return orderedComparison(operator, func(interval monitorapi.Interval) int {
+			actual := interval.From
+			if field == "to" {
+				actual = interval.To
+			}
+			return compareInts64(actual.UnixNano(), limit.UnixNano())
+		})
+
+	case field == "display":
+		display, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, err
+		}
+		return equalityComparison(operator, func(interval monitorapi.Interval) bool {
+			return interval.Display == display
+		})
+	}
+
+	getter, err := stringFieldGetter(field)
+	if err != nil {
+		return nil, err
+	}
+	switch operator {
+	case "=", "==":
+		return func(interval monitorapi.Interval) bool { return getter(interval) == value }, nil
+	case "!=":
+		return func(interval monitorapi.Interval) bool { return getter(interval) != value }, nil
+	case "=~", "!~":
+		regex, err := regexp.Compile(value)
+		if err != nil {
+			return nil, err
+		}
+		if operator == "!~" {
+			return func(interval monitorapi.Interval) bool { return !regex.MatchString(getter(interval)) }, nil
+		}
+		return func(interval monitorapi.Interval) bool { return regex.MatchString(getter(interval)) }, nil
+	default:
+		return nil, fmt.Errorf("operator %q is not supported for %q", operator, field)
+	}
+}
+
+func stringFieldGetter(field string) (func(monitorapi.Interval) string, error) {
+	switch {
+	case field == "source":
+		return func(interval monitorapi.Interval) string { return string(interval.Source) }, nil
+	case field == "reason":
+		return func(interval monitorapi.Interval) string { return string(interval.StructuredMessage.Reason) }, nil
+	case field == "cause":
+		return func(interval monitorapi.Interval) string { return interval.StructuredMessage.Cause }, nil
+	case field == "message":
+		return func(interval monitorapi.Interval) string {
+			if len(interval.StructuredMessage.HumanMessage) > 0 {
+				return interval.StructuredMessage.HumanMessage
+			}
+			return interval.Message
+		}, nil
+	case field == "locator":
+		return func(interval monitorapi.Interval) string {
+			if len(interval.StructuredLocator.Keys) > 0 {
+				return interval.StructuredLocator.OldLocator()
+			}
+			return interval.Locator
+		}, nil
+	case field == "locator.type":
+		return func(interval monitorapi.Interval) string { return string(interval.StructuredLocator.Type) }, nil
+	case strings.HasPrefix(field, "locator.") && len(field) > len("locator."):
+		key := monitorapi.LocatorKey(strings.TrimPrefix(field, "locator."))
+		return func(interval monitorapi.Interval) string { return interval.StructuredLocator.Keys[key] }, nil
+	case strings.HasPrefix(field, "annotation.") && len(field) > len("annotation."):
+		key := monitorapi.AnnotationKey(strings.TrimPrefix(field, "annotation."))
+		return func(interval monitorapi.Interval) string { return interval.StructuredMessage.Annotations[key] }, nil
+	default:
+		return nil, fmt.Errorf("unknown field %q", field)
+	}
+}
+
+// orderedComparison builds a matcher from compare, which returns <0, 0, or >0 when the interval's value is
+// less than, equal to, or greater than the expression's value.
+func orderedComparison(operator string, compare func(monitorapi.Interval) int) (monitorapi.EventIntervalMatchesFunc, error) {
+	switch operator {
+	case "=", "==":
+		return func(interval monitorapi.Interval) bool { return compare(interval) == 0 }, nil
+	case "!=":
+		return func(interval monitorapi.Interval) bool { return compare(interval) != 0 }, nil
+	case "<":
+		return func(interval monitorapi.Interval) bool { return compare(interval) < 0 }, nil
+	case "<=":
+		return func(interval monitorapi.Interval) bool { return compare(interval) <= 0 }, nil
+	case ">":
+		return func(interval monitorapi.Interval) bool { return compare(interval) > 0 }, nil
+	case ">=":
+		return func(interval monitorapi.Interval) bool { return compare(interval) >= 0 }, nil
+	default:
+		return nil, fmt.Errorf("operator %q is not supported", operator)
+	}
+}
+
+func equalityComparison(operator string, equal func(monitorapi.Interval) bool) (monitorapi.EventIntervalMatchesFunc, error) {
+	switch operator {
+	case "=", "==":
+		return equal, nil
+	case "!=":
+		return monitorapi.Not(equal), nil
+	default:
+		return nil, fmt.Errorf("operator %q is not supported", operator)
+	}
+}
+
+func levelFromString(value string) (monitorapi.IntervalLevel, error) {
+	for _, level := range []monitorapi.IntervalLevel{monitorapi.Info, monitorapi.Warning, monitorapi.Error} {
+		if strings.EqualFold(level.String(), value) {
+			return level, nil
+		}
+	}
+	return monitorapi.Error, fmt.Errorf("unknown level %q, expected Info, Warning, or Error", value)
+}
+
+func compareInts(a, b int) int {
+	return compareInts64(int64(a), int64(b))
+}
+
+func compareInts64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,81 @@
+package intervalquery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestCompile(t *testing.T) {
+	from := time.Date(2023, 2, 14, 20, 33, 40, 0, time.UTC)
+	alert := monitorapi.NewInterval(monitorapi.SourceAlert, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().PodFromNames("openshift-etcd", "etcd-0", "uid-0")).
+		Message(monitorapi.NewMessage().HumanMessage("pod not ready").WithAnnotation(monitorapi.AnnotationAlertState, "firing")).
+		Display().
+		Build(from, from.Add(10*time.Minute))
+	event := monitorapi.NewInterval(monitorapi.SourceKubeEvent, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName("node-a")).
+		Message(monitorapi.NewMessage().Reason(monitorapi.NodeNotReadyReason).HumanMessage("node is not ready")).
+		Build(from.Add(time.Hour), from.Add(time.Hour))
+
+	tests := []struct {
+		name        string
+		expression  string
+		wantAlert   bool
+		wantEvent   bool
+		expectedErr bool
+	}{
+		{name: "empty", expression: "", wantAlert: true, wantEvent: true},
+		{name: "source", expression: "source=Alert", wantAlert: true},
+		{name: "source not equal", expression: "source != Alert", wantEvent: true},
+		{name: "level ordering", expression: "level>=warning", wantAlert: true},
+		{name: "level equality", expression: "level==Info", wantEvent: true},
+		{name: "reason", expression: "reason=NotReady", wantEvent: true},
+		{name: "message regex", expression: `message=~"^pod .* ready$"`, wantAlert: true},
+		{name: "locator key", expression: "locator.namespace=openshift-etcd", wantAlert: true},
+		{name: "locator key regex", expression: `locator.namespace=~'^openshift-'`, wantAlert: true},
+		{name: "missing locator key", expression: `locator.namespace=""`, wantEvent: true},
+		{name: "locator type", expression: "locator.type=Node", wantEvent: true},
+		{name: "annotation", expression: "annotation.alertstate=firing", wantAlert: true},
+		{name: "display", expression: "display=true", wantAlert: true},
+		{name: "duration", expression: "duration>5m", wantAlert: true},
+		{name: "from", expression: "from>=2023-02-14T21:00:00Z", wantEvent: true},
+		{name: "to", expression: "to<2023-02-14T21:00:00Z", wantAlert: true},
+		{name: "and", expression: "source=Alert and level=Info"},
+		{name: "or", expression: "source=Alert || level=Info", wantAlert: true, wantEvent: true},
+		{name: "not", expression: "not source=Alert", wantEvent: true},
+		{name: "bang", expression: "!(source=Alert)", wantEvent: true},
+		{name: "precedence", expression: "source=Alert or source=KubeEvent and level=Error", wantAlert: true},
+		{name: "parens", expression: "(source=Alert or source=KubeEvent) and level=Error"},
+		{name: "unknown field", expression: "bogus=foo", expectedErr: true},
+		{name: "bad operator for string", expression: "source>Alert", expectedErr: true},
+		{name: "bad level", expression: "level=Critical", expectedErr: true},
+		{name: "bad duration", expression: "duration>soon", expectedErr: true},
+		{name: "bad regex", expression: `message=~"("`, expectedErr: true},
+		{name: "unterminated string", expression: `message="foo`, expectedErr: true},
+		{name: "missing value", expression: "source=", expectedErr: true},
+		{name: "missing paren", expression: "(source=Alert", expectedErr: true},
+		{name: "trailing tokens", expression: "source=Alert )", expectedErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matcher, err := Compile(tt.expression)
+			if tt.expectedErr {
+				if err == nil {
+					t.Fatalf("expected error for %q", tt.expression)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual := matcher(alert); actual != tt.wantAlert {
+				t.Errorf("alert: expected %v, got %v", tt.wantAlert, actual)
+			}
+			if actual := matcher(event); actual != tt.wantEvent {
+				t.Errorf("event: expected %v, got %v", tt.wantEvent, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package intervalquery
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOpenParen
+	tokenCloseParen
+)
+
+type token struct {
+	kind     tokenKind
+	value    string
+	position int
+}
+
+// operators are ordered so that longer operators are matched before their prefixes.
+var operators = []string{"==", "!=", "=~", "!~", "<=", ">=", "=", "<", ">"}
+
+// wordTerminators end a bare word in addition to whitespace.
+const wordTerminators = "()!=<>~\"'&|"
+
+func tokenize(expression string) ([]token, error) {
+	tokens := []token{}
+	for i := 0; i < len(expression); {
+		c := expression[i]
+		rest := expression[i:]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenOpenParen, value: "(", position: i})
+			i++
+
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenCloseParen, value: ")", position: i})
+			i++
+
+		case strings.HasPrefix(rest, "&&"):
+			tokens = append(tokens, token{kind: tokenAnd, value: "&&", position: i})
+			i += 2
+
+		case strings.HasPrefix(rest, "||"):
+			tokens = append(tokens, token{kind: tokenOr, value: "||", position: i})
+			i += 2
+
+		case c == '"' || c == '\'':
+			value, length, err := readQuoted(rest)
+			if err != nil {
+				return nil, fmt.Errorf("%v at position %d", err, i)
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value, position: i})
+			i += length
+
+		default:
+			if operator := operatorPrefix(rest); len(operator) > 0 {
+				tokens = append(tokens, token{kind: tokenOperator, value: operator, position: i})
+				i += len(operator)
+				continue
+			}
+			if c == '!' {
+				tokens = append(tokens, token{kind: tokenNot, value: "!", position: i})
+				i++
+				continue
+			}
+
+			end := i
+			for end < len(expression) && !unicode.IsSpace(rune(expression[end])) && !strings.ContainsRune(wordTerminators, rune(expression[end])) {
+				end++
+			}
+			if end == i {
+				return nil, fmt.Errorf("unexpected %q at position %d", string(c), i)
+			}
+			word := expression[i:end]
+			switch strings.ToLower(word) {
+			case "and":
+				tokens = append(tokens, token{kind: tokenAnd, value: word, position: i})
+			case "or":
+				tokens = append(tokens, token{kind: tokenOr, value: word, position: i})
+			case "not":
+				tokens = append(tokens, token{kind: tokenNot, value: word, position: i})
+			default:
+				tokens = append(tokens, token{kind: tokenWord, value: word, position: i})
+			}
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+func operatorPrefix(s string) string {
+	for _, operator := range operators {
+		if strings.HasPrefix(s, operator) {
+			return operator
+		}
+	}
+	return ""
+}
+
+// readQuoted reads a string starting with a quote and returns the unquoted value and the number of bytes consumed.
+// A backslash escapes the quote or another backslash, and is kept as-is otherwise so regular expressions
+// like "\d+" don't need double escaping.
+func readQuoted(s string) (string, int, error) {
+	quote := s[0]
+	value := &strings.Builder{}
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) && (s[i+1] == quote || s[i+1] == '\\') {
+				i++
+			}
+			value.WriteByte(s[i])
+		case quote:
+			return value.String(), i + 1, nil
+		default:
+			value.WriteByte(s[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
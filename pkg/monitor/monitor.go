@@ -31,6 +31,8 @@ type Monitor struct {
 
 	recorder monitorapi.Recorder
 	junits   []*junitapi.JUnitTestCase
+	// testJunits are the junits of the e2e tests, see AddTestJUnits.
+	testJunits []*junitapi.JUnitTestCase
 
 	lock      sync.Mutex
 	stopFn    context.CancelFunc
@@ -137,10 +139,14 @@ func (m *Monitor) Stop(ctx context.Context) (ResultState, error) {
 	}
 	m.junits = append(m.junits, cleanupJunits...)
 
+	return resultStateFor(m.junits), nil
+}
+
+// resultStateFor returns Failed if any test only failed.  Tests that both failed and passed are flakes.
+func resultStateFor(junits []*junitapi.JUnitTestCase) ResultState {
 	successfulTestNames := sets.NewString()
 	failedTestNames := sets.NewString()
-	resultState := Succeeded
-	for _, junit := range m.junits {
+	for _, junit := range junits {
 		if junit.FailureOutput != nil {
 			failedTestNames.Insert(junit.Name)
 			continue
@@ -149,10 +155,9 @@ func (m *Monitor) Stop(ctx context.Context) (ResultState, error) {
 	}
 	onlyFailingTests := failedTestNames.Difference(successfulTestNames)
 	if len(onlyFailingTests) > 0 {
-		resultState = Failed
+		return Failed
 	}
-
-	return resultState, nil
+	return Succeeded
 }
 
 func (m *Monitor) SerializeResults(ctx context.Context, junitSuiteName, timeSuffix string) error {
@@ -178,6 +183,8 @@ func (m *Monitor) SerializeResults(ctx context.Context, junitSuiteName, timeSuff
 		return err
 	}
 
+	m.monitorTestRegistry.ObserveJUnits(append(append([]*junitapi.JUnitTestCase{}, m.testJunits...), m.junits...))
+
 	fmt.Fprintf(os.Stderr, "Writing to storage.\n")
 	fmt.Fprintf(os.Stderr, "  m.startTime = %s\n", m.startTime)
 	fmt.Fprintf(os.Stderr, "  m.stopTime  = %s\n", m.stopTime)
@@ -197,7 +204,7 @@ func (m *Monitor) SerializeResults(ctx context.Context, junitSuiteName, timeSuff
 
 	fmt.Fprintf(os.Stderr, "Writing junits.\n")
 	var junitSuite *junitapi.JUnitTestSuite
-	if junitSuite, err = m.serializeJunit(ctx, m.storageDir, junitSuiteName, timeSuffix); err != nil {
+	if junitSuite, err = serializeJunit(m.storageDir, junitSuiteName, timeSuffix, m.junits); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to write junit xml, err: %v\n", err)
 		return err
 	}
@@ -209,7 +216,21 @@ func (m *Monitor) SerializeResults(ctx context.Context, junitSuiteName, timeSuff
 	return nil
 }
 
-func (m *Monitor) serializeJunit(ctx context.Context, storageDir, junitSuiteName, fileSuffix string) (*junitapi.JUnitTestSuite, error) {
+func (m *Monitor) AddTestJUnits(junits ...*junitapi.JUnitTestCase) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.testJunits = append(m.testJunits, junits...)
+}
+
+func (m *Monitor) JUnits() []*junitapi.JUnitTestCase {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	ret := make([]*junitapi.JUnitTestCase, len(m.junits))
+	copy(ret, m.junits)
+	return ret
+}
+
+func serializeJunit(storageDir, junitSuiteName, fileSuffix string, junits []*junitapi.JUnitTestCase) (*junitapi.JUnitTestSuite, error) {
 	junitSuite := junitapi.JUnitTestSuite{
 		Name:       junitSuiteName,
 		NumTests:   0,
@@ -220,8 +241,8 @@ func (m *Monitor) serializeJunit(ctx context.Context, storageDir, junitSuiteName
 		TestCases:  nil,
 		Children:   nil,
 	}
-	for i := range m.junits {
-		currJunit := m.junits[i]
+	for i := range junits {
+		currJunit := junits[i]
 
 		junitSuite.NumTests++
 		if currJunit.FailureOutput != nil {
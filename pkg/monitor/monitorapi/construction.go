@@ -392,6 +392,21 @@ func (b *LocatorBuilder) ClusterOperator(name string) Locator {
 	return b.Build()
 }
 
+// LocateResource locates any resource by group, resource, namespace, and name.  The group and the namespace are
+// left out for the core group and for cluster scoped resources.
+func (b *LocatorBuilder) LocateResource(group, resource, namespace, name string) Locator {
+	b.targetType = LocatorTypeResource
+	if len(group) > 0 {
+		b.annotations[LocatorGroupKey] = group
+	}
+	b.annotations[LocatorResourceKey] = resource
+	if len(namespace) > 0 {
+		b.annotations[LocatorNamespaceKey] = namespace
+	}
+	b.annotations[LocatorNameKey] = name
+	return b.Build()
+}
+
 func (b *LocatorBuilder) Build() Locator {
 	ret := Locator{
 		Type: b.targetType,
@@ -110,6 +110,7 @@ const (
 	LocatorTypeClusterVersion  LocatorType = "ClusterVersion"
 	LocatorTypeKind            LocatorType = "Kind"
 	LocatorTypeCloudMetrics    LocatorType = "CloudMetrics"
+	LocatorTypeResource        LocatorType = "Resource"
 )
 
 type LocatorKey string
@@ -140,6 +141,10 @@ const (
 	LocatorRowKey                   LocatorKey = "row"
 	LocatorServerKey                LocatorKey = "server"
 	LocatorMetricKey                LocatorKey = "metric"
+	LocatorGroupKey                 LocatorKey = "group"
+	LocatorResourceKey              LocatorKey = "resource"
+	// LocatorShardKey tells apart the intervals of each shard of a suite, every shard runs against its own cluster.
+	LocatorShardKey LocatorKey = "shard"
 )
 
 type Locator struct {
@@ -157,6 +162,8 @@ const (
 	DisruptionBeganEventReason              IntervalReason = "DisruptionBegan"
 	DisruptionEndedEventReason              IntervalReason = "DisruptionEnded"
 	DisruptionSamplerOutageBeganEventReason IntervalReason = "DisruptionSamplerOutageBegan"
+	DisruptionLatencyDegradedReason         IntervalReason = "DisruptionLatencyDegraded"
+	DisruptionLatencySummaryReason          IntervalReason = "DisruptionLatencySummary"
 	GracefulAPIServerShutdown               IntervalReason = "GracefulAPIServerShutdown"
 	IncompleteAPIServerShutdown             IntervalReason = "IncompleteAPIServerShutdown"
 
@@ -196,6 +203,10 @@ const (
 
 	Timeout IntervalReason = "Timeout"
 
+	ResourceAddedReason    IntervalReason = "ResourceAdded"
+	ResourceModifiedReason IntervalReason = "ResourceModified"
+	ResourceDeletedReason  IntervalReason = "ResourceDeleted"
+
 	E2ETestStarted  IntervalReason = "E2ETestStarted"
 	E2ETestFinished IntervalReason = "E2ETestFinished"
 
@@ -234,6 +245,16 @@ const (
 	AnnotationRoles          AnnotationKey = "roles"
 	AnnotationStatus         AnnotationKey = "status"
 	AnnotationCondition      AnnotationKey = "condition"
+
+	// latency percentiles and the degraded threshold, in milliseconds
+	AnnotationLatencyP50       AnnotationKey = "p50-ms"
+	AnnotationLatencyP90       AnnotationKey = "p90-ms"
+	AnnotationLatencyP99       AnnotationKey = "p99-ms"
+	AnnotationLatencyThreshold AnnotationKey = "threshold-ms"
+
+	// who we guessed changed a resource, and the fields they changed
+	AnnotationModifiers AnnotationKey = "modifiers"
+	AnnotationFieldDiff AnnotationKey = "fields"
 )
 
 // ConstructionOwner was originally meant to signify that an interval was derived from other intervals.
@@ -270,6 +291,7 @@ const (
 	SourceAlert                     IntervalSource = "Alert"
 	SourceAPIServerShutdown         IntervalSource = "APIServerShutdown"
 	SourceDisruption                IntervalSource = "Disruption"
+	SourceDisruptionLatency         IntervalSource = "DisruptionLatency"
 	SourceE2ETest                   IntervalSource = "E2ETest"
 	SourceKubeEvent                 IntervalSource = "KubeEvent"
 	SourceNetworkManagerLog         IntervalSource = "NetworkMangerLog"
@@ -290,6 +312,7 @@ const (
 	SourceNodeState                              = "NodeState"
 	SourcePodState                               = "PodState"
 	SourceCloudMetrics                           = "CloudMetrics"
+	SourceResourceWatch           IntervalSource = "ResourceWatch"
 )
 
 type Interval struct {
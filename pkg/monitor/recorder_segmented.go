@@ -0,0 +1,231 @@
+package monitor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DefaultSegmentSize is the number of closed intervals held in memory before they are written to a segment.
+const DefaultSegmentSize = 50000
+
+type segmentedRecorder struct {
+	dir         string
+	segmentSize int
+
+	lock sync.Mutex
+	// pending holds closed intervals that have not been written to a segment yet.
+	pending monitorapi.Intervals
+	// open holds intervals from StartInterval that have not been ended.  They stay in memory so EndInterval
+	// can update them.
+	open          map[int]*monitorapi.Interval
+	nextOpenIndex int
+	segments      []segment
+
+	// resources are small compared to intervals, so they stay in memory.
+	resources monitorapi.Recorder
+}
+
+// segment is an immutable JSONL file of intervals along with the time range it covers.
+type segment struct {
+	filename     string
+	count        int
+	earliestFrom time.Time
+	latestEnd    time.Time
+}
+
+// NewSegmentedRecorder creates a recorder that keeps at most segmentSize closed intervals in memory.  Older
+// intervals are written, one per line, to time indexed segment files in dir.  Intervals(from, to) only reads the
+// segments that overlap the requested range.
+func NewSegmentedRecorder(dir string, segmentSize int) (monitorapi.Recorder, error) {
+	if segmentSize <= 0 {
+		return nil, fmt.Errorf("segmentSize must be positive, got %d", segmentSize)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &segmentedRecorder{
+		dir:         dir,
+		segmentSize: segmentSize,
+		open:        map[int]*monitorapi.Interval{},
+		resources:   NewRecorder(),
+	}, nil
+}
+
+var _ monitorapi.Recorder = &segmentedRecorder{}
+
+func (m *segmentedRecorder) CurrentResourceState() monitorapi.ResourcesMap {
+	return m.resources.CurrentResourceState()
+}
+
+func (m *segmentedRecorder) RecordResource(resourceType string, obj runtime.Object) {
+	m.resources.RecordResource(resourceType, obj)
+}
+
+// Record captures one or more conditions at the current time. All conditions are recorded
+// in monotonic order as EventInterval objects.
+func (m *segmentedRecorder) Record(conditions ...monitorapi.Condition) {
+	m.RecordAt(time.Now().UTC(), conditions...)
+}
+
+// RecordAt captures one or more conditions at the provided time. All conditions are recorded
+// as EventInterval objects.
+func (m *segmentedRecorder) RecordAt(t time.Time, conditions ...monitorapi.Condition) {
+	if len(conditions) == 0 {
+		return
+	}
+	intervals := monitorapi.Intervals{}
+	for _, condition := range conditions {
+		intervals = append(intervals, monitorapi.Interval{
+			Condition: condition,
+			From:      t,
+			To:        t,
+		})
+	}
+	m.AddIntervals(intervals...)
+}
+
+// AddIntervals provides a mechanism to directly inject eventIntervals
+func (m *segmentedRecorder) AddIntervals(eventIntervals ...monitorapi.Interval) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.pending = append(m.pending, eventIntervals...)
+	m.spillIfNeeded()
+}
+
+// StartInterval inserts a record at time t with the provided condition and returns an opaque
+// locator to the interval. The caller may close the sample at any point by invoking EndInterval().
+func (m *segmentedRecorder) StartInterval(interval monitorapi.Interval) int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	index := m.nextOpenIndex
+	m.nextOpenIndex++
+	m.open[index] = &interval
+	return index
+}
+
+// EndInterval updates the To of the interval started by StartInterval if it is greater than
+// the from.  Once ended, the interval is eligible to be written to a segment and may not be ended again.
+func (m *segmentedRecorder) EndInterval(startedInterval int, t time.Time) *monitorapi.Interval {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	interval, ok := m.open[startedInterval]
+	if !ok {
+		return nil
+	}
+	delete(m.open, startedInterval)
+	if interval.From.Before(t) {
+		interval.To = t
+	}
+	m.pending = append(m.pending, *interval)
+	m.spillIfNeeded()
+
+	ret := *interval
+	return &ret
+}
+
+// Intervals returns all events that occur between from and to, including
+// any sampled conditions that were encountered during that period.
+// Intervals are returned in order of their occurrence. The returned slice
+// is a copy of the recorder's state and is safe to update.
+func (m *segmentedRecorder) Intervals(from, to time.Time) monitorapi.Intervals {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	ret := monitorapi.Intervals{}
+	for _, curr := range m.segments {
+		if !from.IsZero() && curr.latestEnd.Before(from) {
+			continue
+		}
+		if !to.IsZero() && curr.earliestFrom.After(to) {
+			continue
+		}
+		intervals, err := readSegment(curr.filename)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error reading interval segment %q: %v\n", curr.filename, err)
+		}
+		ret = append(ret, intervals...)
+	}
+	ret = append(ret, m.pending...)
+	for _, interval := range m.open {
+		ret = append(ret, *interval)
+	}
+
+	// we must sort *before*, we use the slice function
+	sort.Sort(ret)
+	return ret.Slice(from, to)
+}
+
+// spillIfNeeded writes pending to a new segment once it reaches segmentSize.  Must be called with the lock held.
+// If the segment cannot be written, the intervals are kept in memory so no data is lost.
+func (m *segmentedRecorder) spillIfNeeded() {
+	if len(m.pending) < m.segmentSize {
+		return
+	}
+
+	sort.Sort(m.pending)
+	curr := segment{
+		filename:     filepath.Join(m.dir, fmt.Sprintf("intervals-segment-%06d.jsonl", len(m.segments))),
+		count:        len(m.pending),
+		earliestFrom: m.pending[0].From,
+	}
+	buf := &bytes.Buffer{}
+	for _, interval := range m.pending {
+		if end := intervalEnd(interval); end.After(curr.latestEnd) {
+			curr.latestEnd = end
+		}
+		// the serialized EventInterval truncates to seconds, so store the interval itself to keep full precision.
+		intervalJSON, err := json.Marshal(interval)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error serializing: %v\n", err)
+			return
+		}
+		buf.Write(intervalJSON)
+		buf.WriteString("\n")
+	}
+	if err := os.WriteFile(curr.filename, buf.Bytes(), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "error writing interval segment %q, keeping intervals in memory: %v\n", curr.filename, err)
+		return
+	}
+
+	m.segments = append(m.segments, curr)
+	m.pending = nil
+}
+
+// intervalEnd returns the latest instant covered by the interval.  Instants may have a zero To.
+func intervalEnd(interval monitorapi.Interval) time.Time {
+	if interval.To.After(interval.From) {
+		return interval.To
+	}
+	return interval.From
+}
+
+func readSegment(filename string) (monitorapi.Intervals, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	ret := monitorapi.Intervals{}
+	scanner := bufio.NewScanner(file)
+	// intervals with large messages can exceed the default token size.
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		interval := monitorapi.Interval{}
+		if err := json.Unmarshal(scanner.Bytes(), &interval); err != nil {
+			return ret, err
+		}
+		ret = append(ret, interval)
+	}
+	return ret, scanner.Err()
+}
@@ -0,0 +1,76 @@
+package monitor
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestSegmentedRecorder_Intervals(t *testing.T) {
+	base := time.Date(2023, 2, 14, 20, 33, 40, 123000000, time.UTC)
+	newCondition := func(message string) monitorapi.Condition {
+		return monitorapi.NewInterval(monitorapi.SourceTestData, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName("foo")).
+			Message(monitorapi.NewMessage().HumanMessage(message)).
+			BuildCondition()
+	}
+
+	dir := t.TempDir()
+	segmented, err := NewSegmentedRecorder(dir, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inMemory := NewRecorder()
+
+	for _, recorder := range []monitorapi.Recorder{segmented, inMemory} {
+		openIndex := recorder.StartInterval(monitorapi.Interval{Condition: newCondition("open"), From: base})
+		for i := 0; i < 10; i++ {
+			recorder.RecordAt(base.Add(time.Duration(i)*time.Minute), newCondition("instant"))
+		}
+		recorder.EndInterval(openIndex, base.Add(90*time.Second))
+		recorder.StartInterval(monitorapi.Interval{Condition: newCondition("still-open"), From: base.Add(5 * time.Minute)})
+	}
+
+	segments, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segments) != 3 {
+		t.Fatalf("expected 3 segments, got %d", len(segments))
+	}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+	}{
+		{name: "everything"},
+		{name: "from", from: base.Add(7 * time.Minute)},
+		{name: "to", to: base.Add(2 * time.Minute)},
+		{name: "range", from: base.Add(3 * time.Minute), to: base.Add(6 * time.Minute)},
+		{name: "after", from: base.Add(time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the in-memory recorder returns its backing slice, copy it before clearing times below.
+			expected := append(monitorapi.Intervals{}, inMemory.Intervals(tt.from, tt.to)...)
+			actual := segmented.Intervals(tt.from, tt.to)
+			if len(expected) != len(actual) {
+				t.Fatalf("expected %d intervals, got %d", len(expected), len(actual))
+			}
+			for i := range expected {
+				if !expected[i].From.Equal(actual[i].From) || !expected[i].To.Equal(actual[i].To) {
+					t.Fatalf("unexpected time at %d: %s", i, diff.ObjectReflectDiff(expected[i], actual[i]))
+				}
+				expected[i].From, expected[i].To = time.Time{}, time.Time{}
+				actual[i].From, actual[i].To = time.Time{}, time.Time{}
+				if !reflect.DeepEqual(expected[i], actual[i]) {
+					t.Fatalf("unexpected interval at %d: %s", i, diff.ObjectReflectDiff(expected[i], actual[i]))
+				}
+			}
+		})
+	}
+}
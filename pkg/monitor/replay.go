@@ -0,0 +1,199 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/riskanalysis"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// replayMonitor drives the monitor tests from intervals and tracked resources that were serialized by a
+// previous run.  There is no cluster, so StartCollection and CollectData are never called.  Monitor tests
+// that depend on state gathered during collection will report that through their junits.
+type replayMonitor struct {
+	monitorTestRegistry monitortestframework.MonitorTestRegistry
+	storageDir          string
+
+	recorder          monitorapi.Recorder
+	recordedResources monitorapi.ResourcesMap
+	alreadySerialized map[string]bool
+	junits            []*junitapi.JUnitTestCase
+	testJunits        []*junitapi.JUnitTestCase
+	startTime         time.Time
+	stopTime          time.Time
+	lock              sync.Mutex
+	started           bool
+}
+
+// NewReplayMonitor creates a monitor that replays previously serialized intervals and resources through
+// ConstructComputedIntervals, EvaluateTestsFromConstructedIntervals, and WriteContentToStorage.
+func NewReplayMonitor(
+	intervals monitorapi.Intervals,
+	recordedResources monitorapi.ResourcesMap,
+	storageDir string,
+	monitorTestRegistry monitortestframework.MonitorTestRegistry) Interface {
+
+	recorder := NewRecorder()
+	recorder.AddIntervals(intervals...)
+
+	// the serialized intervals usually include the computed intervals from the original run.  Track what we
+	// loaded so that re-computing them does not produce duplicates.
+	alreadySerialized := map[string]bool{}
+	for _, interval := range intervals {
+		if key, err := monitorserialization.IntervalToOneLineJSON(interval); err == nil {
+			alreadySerialized[string(key)] = true
+		}
+	}
+
+	if recordedResources == nil {
+		recordedResources = monitorapi.ResourcesMap{}
+	}
+
+	return &replayMonitor{
+		monitorTestRegistry: monitorTestRegistry,
+		storageDir:          storageDir,
+		recorder:            recorder,
+		recordedResources:   recordedResources,
+		alreadySerialized:   alreadySerialized,
+	}
+}
+
+var _ Interface = &replayMonitor{}
+
+// Start establishes the bounds of the replay from the loaded intervals.
+func (m *replayMonitor) Start(ctx context.Context) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.started {
+		return fmt.Errorf("monitor already started")
+	}
+	m.started = true
+
+	for _, interval := range m.recorder.Intervals(time.Time{}, time.Time{}) {
+		if !interval.From.IsZero() && (m.startTime.IsZero() || interval.From.Before(m.startTime)) {
+			m.startTime = interval.From
+		}
+		if interval.To.After(m.stopTime) {
+			m.stopTime = interval.To
+		}
+		if interval.From.After(m.stopTime) {
+			m.stopTime = interval.From
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Replaying intervals from %s to %s\n", m.startTime, m.stopTime)
+
+	return nil
+}
+
+func (m *replayMonitor) Stop(ctx context.Context) (ResultState, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if !m.started {
+		return Failed, fmt.Errorf("monitor not started")
+	}
+
+	fmt.Fprintf(os.Stderr, "Computing intervals.\n")
+	computedIntervals, computedJunit, err := m.monitorTestRegistry.ConstructComputedIntervals(
+		ctx,
+		m.recorder.Intervals(time.Time{}, time.Time{}),
+		m.recordedResources,
+		m.startTime,
+		m.stopTime)
+	if err != nil {
+		// these errors are represented as junit, always continue to the next step
+		fmt.Fprintf(os.Stderr, "Error computing intervals, continuing, junit will reflect this. %v\n", err)
+	}
+	newIntervals := monitorapi.Intervals{}
+	for _, interval := range computedIntervals {
+		if key, err := monitorserialization.IntervalToOneLineJSON(interval); err == nil && m.alreadySerialized[string(key)] {
+			continue
+		}
+		newIntervals = append(newIntervals, interval)
+	}
+	fmt.Fprintf(os.Stderr, "Computed %d intervals, %d were not present in the replayed intervals.\n", len(computedIntervals), len(newIntervals))
+	m.recorder.AddIntervals(newIntervals...)
+	m.junits = append(m.junits, computedJunit...)
+
+	fmt.Fprintf(os.Stderr, "Evaluating tests.\n")
+	monitorTestJunits, err := m.monitorTestRegistry.EvaluateTestsFromConstructedIntervals(
+		ctx,
+		m.recorder.Intervals(m.startTime, m.stopTime),
+	)
+	if err != nil {
+		// these errors are represented as junit, always continue to the next step
+		fmt.Fprintf(os.Stderr, "Error evaluating tests, continuing, junit will reflect this. %v\n", err)
+	}
+	m.junits = append(m.junits, monitorTestJunits...)
+
+	fmt.Fprintf(os.Stderr, "Cleaning up.\n")
+	cleanupJunits, err := m.monitorTestRegistry.Cleanup(ctx)
+	if err != nil {
+		// these errors are represented as junit, always continue to the next step
+		fmt.Fprintf(os.Stderr, "Error cleaning up, continuing, junit will reflect this. %v\n", err)
+	}
+	m.junits = append(m.junits, cleanupJunits...)
+
+	return resultStateFor(m.junits), nil
+}
+
+func (m *replayMonitor) SerializeResults(ctx context.Context, junitSuiteName, timeSuffix string) error {
+	fmt.Fprintf(os.Stderr, "Serializing results.\n")
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if err := os.MkdirAll(filepath.Join(m.storageDir, monitorapi.EventDir), os.ModePerm); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create monitor-events directory, err: %v\n", err)
+		return err
+	}
+
+	m.monitorTestRegistry.ObserveJUnits(append(append([]*junitapi.JUnitTestCase{}, m.testJunits...), m.junits...))
+
+	fmt.Fprintf(os.Stderr, "Writing to storage.\n")
+	monitorTestJunits, err := m.monitorTestRegistry.WriteContentToStorage(
+		ctx,
+		m.storageDir,
+		timeSuffix,
+		m.recorder.Intervals(m.startTime, m.stopTime),
+		m.recordedResources,
+	)
+	if err != nil {
+		// these errors are represented as junit, always continue to the next step
+		fmt.Fprintf(os.Stderr, "Error writing to storage, continuing, junit will reflect this. %v\n", err)
+	}
+	m.junits = append(m.junits, monitorTestJunits...)
+
+	fmt.Fprintf(os.Stderr, "Writing junits.\n")
+	junitSuite, err := serializeJunit(m.storageDir, junitSuiteName, timeSuffix, m.junits)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write junit xml, err: %v\n", err)
+		return err
+	}
+
+	if err := riskanalysis.WriteJobRunTestFailureSummary(m.storageDir, timeSuffix, junitSuite, "", "_monitor"); err != nil {
+		fmt.Fprintf(os.Stderr, "error: Unable to write e2e job run failures summary: %v", err)
+	}
+
+	return nil
+}
+
+func (m *replayMonitor) AddTestJUnits(junits ...*junitapi.JUnitTestCase) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.testJunits = append(m.testJunits, junits...)
+}
+
+func (m *replayMonitor) JUnits() []*junitapi.JUnitTestCase {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	ret := make([]*junitapi.JUnitTestCase, len(m.junits))
+	copy(ret, m.junits)
+	return ret
+}
@@ -0,0 +1,196 @@
+package monitorserialization
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// chromeTrace is the JSON object format of the Chrome Trace Event format, which perfetto and chrome://tracing load.
+// https://docs.google.com/document/d/1CvAClvFfyA5R-PhYUmn5OOQtYMH4h6I0nSsKchNAySU
+type chromeTrace struct {
+	TraceEvents     []chromeTraceEvent `json:"traceEvents"`
+	DisplayTimeUnit string             `json:"displayTimeUnit"`
+}
+
+type chromeTraceEvent struct {
+	Name     string `json:"name"`
+	Category string `json:"cat,omitempty"`
+	Phase    string `json:"ph"`
+	// Timestamp and Duration are in microseconds.  Timestamps are since the unix epoch so that traces from other
+	// sources, like node tracing, line up.
+	Timestamp int64                  `json:"ts"`
+	Duration  *int64                 `json:"dur,omitempty"`
+	Scope     string                 `json:"s,omitempty"`
+	PID       int                    `json:"pid"`
+	TID       int                    `json:"tid"`
+	Args      map[string]interface{} `json:"args,omitempty"`
+}
+
+// IntervalsToChromeTrace renders intervals in the Chrome Trace Event format.  Every locator type is a process and every
+// locator is a thread in it.  Slices on a thread must nest, so a locator with overlapping intervals gets one thread per
+// overlapping interval.  Intervals that never ended last until the latest time in intervals.
+func IntervalsToChromeTrace(intervals monitorapi.Intervals) ([]byte, error) {
+	var end time.Time
+	for _, interval := range intervals {
+		if interval.From.After(end) {
+			end = interval.From
+		}
+		if interval.To.After(end) {
+			end = interval.To
+		}
+	}
+
+	byType := map[monitorapi.LocatorType]map[string][]monitorapi.Interval{}
+	for _, interval := range intervals {
+		locatorType := interval.StructuredLocator.Type
+		if len(locatorType) == 0 {
+			locatorType = "Unknown"
+		}
+		if _, ok := byType[locatorType]; !ok {
+			byType[locatorType] = map[string][]monitorapi.Interval{}
+		}
+		locator := interval.StructuredLocator.OldLocator()
+		byType[locatorType][locator] = append(byType[locatorType][locator], interval)
+	}
+
+	locatorTypes := []string{}
+	for locatorType := range byType {
+		locatorTypes = append(locatorTypes, string(locatorType))
+	}
+	sort.Strings(locatorTypes)
+
+	trace := chromeTrace{
+		TraceEvents:     []chromeTraceEvent{},
+		DisplayTimeUnit: "ms",
+	}
+	tid := 0
+	for i, locatorType := range locatorTypes {
+		pid := i + 1
+		trace.TraceEvents = append(trace.TraceEvents, chromeTraceEvent{
+			Name:  "process_name",
+			Phase: "M",
+			PID:   pid,
+			Args:  map[string]interface{}{"name": locatorType},
+		})
+
+		byLocator := byType[monitorapi.LocatorType(locatorType)]
+		locators := []string{}
+		for locator := range byLocator {
+			locators = append(locators, locator)
+		}
+		sort.Strings(locators)
+
+		for _, locator := range locators {
+			for lane, laneIntervals := range chromeTraceLanes(byLocator[locator], end) {
+				tid++
+				threadName := locator
+				if len(threadName) == 0 {
+					threadName = "<no locator>"
+				}
+				if lane > 0 {
+					threadName = fmt.Sprintf("%s (%d)", threadName, lane+1)
+				}
+				trace.TraceEvents = append(trace.TraceEvents, chromeTraceEvent{
+					Name:  "thread_name",
+					Phase: "M",
+					PID:   pid,
+					TID:   tid,
+					Args:  map[string]interface{}{"name": threadName},
+				})
+				for _, interval := range laneIntervals {
+					trace.TraceEvents = append(trace.TraceEvents, chromeTraceEventFor(interval, pid, tid, end))
+				}
+			}
+		}
+	}
+
+	return json.MarshalIndent(trace, "", "  ")
+}
+
+// chromeTraceLanes splits intervals into lanes in which no two intervals overlap.
+func chromeTraceLanes(intervals []monitorapi.Interval, end time.Time) [][]monitorapi.Interval {
+	sorted := make([]monitorapi.Interval, len(intervals))
+	copy(sorted, intervals)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].From.Before(sorted[j].From)
+	})
+
+	lanes := [][]monitorapi.Interval{}
+	laneEnds := []time.Time{}
+	for _, interval := range sorted {
+		to := interval.To
+		if to.IsZero() {
+			to = end
+		}
+		lane := 0
+		for ; lane < len(lanes); lane++ {
+			// instants never overlap anything.
+			if !laneEnds[lane].After(interval.From) || interval.From.Equal(to) {
+				break
+			}
+		}
+		if lane == len(lanes) {
+			lanes = append(lanes, nil)
+			laneEnds = append(laneEnds, time.Time{})
+		}
+		lanes[lane] = append(lanes[lane], interval)
+		if to.After(laneEnds[lane]) {
+			laneEnds[lane] = to
+		}
+	}
+	return lanes
+}
+
+func chromeTraceEventFor(interval monitorapi.Interval, pid, tid int, end time.Time) chromeTraceEvent {
+	name := string(interval.StructuredMessage.Reason)
+	if len(name) == 0 {
+		name = string(interval.Source)
+	}
+	if len(name) == 0 {
+		name = interval.Level.String()
+	}
+
+	args := map[string]interface{}{
+		"level":  interval.Level.String(),
+		"source": string(interval.Source),
+	}
+	for k, v := range interval.StructuredLocator.Keys {
+		args["locator."+string(k)] = v
+	}
+	for k, v := range interval.StructuredMessage.Annotations {
+		args[string(k)] = v
+	}
+	if len(interval.StructuredMessage.Cause) > 0 {
+		args["cause"] = interval.StructuredMessage.Cause
+	}
+	if len(interval.StructuredMessage.HumanMessage) > 0 {
+		args["message"] = interval.StructuredMessage.HumanMessage
+	}
+
+	ret := chromeTraceEvent{
+		Name:      name,
+		Category:  string(interval.Source),
+		Timestamp: interval.From.UnixMicro(),
+		PID:       pid,
+		TID:       tid,
+		Args:      args,
+	}
+	to := interval.To
+	if to.IsZero() {
+		to = end
+		args["ongoing"] = "true"
+	}
+	if !to.After(interval.From) {
+		ret.Phase = "i"
+		ret.Scope = "t"
+		return ret
+	}
+	duration := to.Sub(interval.From).Microseconds()
+	ret.Phase = "X"
+	ret.Duration = &duration
+	return ret
+}
@@ -0,0 +1,104 @@
+package monitorserialization
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestIntervalsToChromeTrace(t *testing.T) {
+	start := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	podLocator := monitorapi.Locator{
+		Type: monitorapi.LocatorTypePod,
+		Keys: map[monitorapi.LocatorKey]string{monitorapi.LocatorNamespaceKey: "ns", monitorapi.LocatorPodKey: "pod-a"},
+	}
+	alertLocator := monitorapi.Locator{
+		Type: monitorapi.LocatorTypeAlert,
+		Keys: map[monitorapi.LocatorKey]string{monitorapi.LocatorAlertKey: "KubePodNotReady"},
+	}
+	intervals := monitorapi.Intervals{
+		{
+			Condition: monitorapi.Condition{
+				Level:             monitorapi.Warning,
+				StructuredLocator: podLocator,
+				StructuredMessage: monitorapi.Message{Reason: "NotReady", HumanMessage: "not ready", Annotations: map[monitorapi.AnnotationKey]string{"node": "node-a"}},
+			},
+			Source: monitorapi.SourcePodState,
+			From:   start,
+			To:     start.Add(2 * time.Second),
+		},
+		{
+			// overlaps the first interval, so it needs a second thread.
+			Condition: monitorapi.Condition{Level: monitorapi.Info, StructuredLocator: podLocator},
+			Source:    monitorapi.SourcePodState,
+			From:      start.Add(time.Second),
+			To:        start.Add(3 * time.Second),
+		},
+		{
+			// an instant
+			Condition: monitorapi.Condition{Level: monitorapi.Info, StructuredLocator: podLocator},
+			Source:    monitorapi.SourcePodState,
+			From:      start.Add(time.Second),
+			To:        start.Add(time.Second),
+		},
+		{
+			// never ended
+			Condition: monitorapi.Condition{Level: monitorapi.Error, StructuredLocator: alertLocator},
+			Source:    monitorapi.SourceAlert,
+			From:      start.Add(time.Second),
+		},
+	}
+
+	data, err := IntervalsToChromeTrace(intervals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trace := chromeTrace{}
+	if err := json.Unmarshal(data, &trace); err != nil {
+		t.Fatal(err)
+	}
+
+	processes := map[int]string{}
+	threads := map[int]string{}
+	slices := map[int][]chromeTraceEvent{}
+	for _, event := range trace.TraceEvents {
+		switch {
+		case event.Phase == "M" && event.Name == "process_name":
+			processes[event.PID] = event.Args["name"].(string)
+		case event.Phase == "M" && event.Name == "thread_name":
+			threads[event.TID] = event.Args["name"].(string)
+		default:
+			slices[event.TID] = append(slices[event.TID], event)
+		}
+	}
+
+	if len(processes) != 2 || processes[1] != "Alert" || processes[2] != "Pod" {
+		t.Errorf("expected Alert and Pod processes, got %v", processes)
+	}
+	expectedThreads := map[int]string{1: "alert/KubePodNotReady", 2: "namespace/ns pod/pod-a", 3: "namespace/ns pod/pod-a (2)"}
+	for tid, name := range expectedThreads {
+		if threads[tid] != name {
+			t.Errorf("expected thread %d to be %q, got %q", tid, name, threads[tid])
+		}
+	}
+
+	alert := slices[1][0]
+	if alert.Phase != "X" || *alert.Duration != 2*time.Second.Microseconds() || alert.Args["ongoing"] != "true" {
+		t.Errorf("expected the alert to last until the latest time, got %#v", alert)
+	}
+	if len(slices[2]) != 2 || len(slices[3]) != 1 {
+		t.Fatalf("expected the overlapping interval on its own thread, got %#v", slices)
+	}
+	notReady := slices[2][0]
+	if notReady.Name != "NotReady" || notReady.Timestamp != start.UnixMicro() || *notReady.Duration != 2*time.Second.Microseconds() {
+		t.Errorf("unexpected slice %#v", notReady)
+	}
+	if notReady.Args["node"] != "node-a" || notReady.Args["message"] != "not ready" || notReady.Args["locator.pod"] != "pod-a" {
+		t.Errorf("expected annotations, message, and locator as args, got %v", notReady.Args)
+	}
+	if instant := slices[2][1]; instant.Phase != "i" || instant.Duration != nil {
+		t.Errorf("expected an instant, got %#v", instant)
+	}
+}
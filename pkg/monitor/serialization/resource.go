@@ -4,10 +4,13 @@ import (
 	"archive/zip"
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strings"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -73,3 +76,80 @@ func InstanceMapToFile(filename string, resourceType string, instances monitorap
 
 	return ioutil.WriteFile(filename, byteBuffer.Bytes(), 0644)
 }
+
+// knownResourceTypes maps the resourceType names passed to RecordResource to the typed objects that
+// monitor tests expect to find in the ResourcesMap.  Unknown types are returned as unstructured.
+var knownResourceTypes = map[string]func() runtime.Object{
+	"pods":   func() runtime.Object { return &corev1.Pod{} },
+	"events": func() runtime.Object { return &corev1.Event{} },
+}
+
+// InstanceMapFromFile reads a resource-<type><timeSuffix>.zip written by InstanceMapToFile and returns
+// the resourceType it contains along with the instances.
+func InstanceMapFromFile(filename string) (string, monitorapi.InstanceMap, error) {
+	zipReader, err := zip.OpenReader(filename)
+	if err != nil {
+		return "", nil, err
+	}
+	defer zipReader.Close()
+
+	resourceType := ""
+	instances := monitorapi.InstanceMap{}
+	for _, zipFile := range zipReader.File {
+		currResourceType := strings.TrimSuffix(filepath.Base(zipFile.Name), ".json")
+		if len(resourceType) == 0 {
+			resourceType = currResourceType
+		}
+		if currResourceType != resourceType {
+			return "", nil, fmt.Errorf("%q contains multiple resource types: %q and %q", filename, resourceType, currResourceType)
+		}
+
+		nsReader, err := zipFile.Open()
+		if err != nil {
+			return "", nil, err
+		}
+		data, err := ioutil.ReadAll(nsReader)
+		nsReader.Close()
+		if err != nil {
+			return "", nil, err
+		}
+
+		// the items are usually missing apiVersion and kind, so we cannot use the unstructured decoder.
+		nsList := struct {
+			Items []map[string]interface{} `json:"items"`
+		}{}
+		if err := json.Unmarshal(data, &nsList); err != nil {
+			return "", nil, fmt.Errorf("failed to decode %q in %q: %w", zipFile.Name, filename, err)
+		}
+		for i := range nsList.Items {
+			obj, err := toTypedObject(resourceType, &unstructured.Unstructured{Object: nsList.Items[i]})
+			if err != nil {
+				return "", nil, err
+			}
+			metadata, err := meta.Accessor(obj)
+			if err != nil {
+				return "", nil, err
+			}
+			key := monitorapi.InstanceKey{
+				Namespace: metadata.GetNamespace(),
+				Name:      metadata.GetName(),
+				UID:       fmt.Sprintf("%v", metadata.GetUID()),
+			}
+			instances[key] = obj
+		}
+	}
+
+	return resourceType, instances, nil
+}
+
+func toTypedObject(resourceType string, item *unstructured.Unstructured) (runtime.Object, error) {
+	newFn, ok := knownResourceTypes[resourceType]
+	if !ok {
+		return item, nil
+	}
+	obj := newFn()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, obj); err != nil {
+		return nil, fmt.Errorf("failed to convert %s %s/%s: %w", resourceType, item.GetNamespace(), item.GetName(), err)
+	}
+	return obj, nil
+}
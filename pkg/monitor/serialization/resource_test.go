@@ -0,0 +1,70 @@
+package monitorserialization
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestInstanceMapRoundTrip(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-a", Name: "pod-a", UID: "uid-a"},
+		Spec:       corev1.PodSpec{NodeName: "node-a"},
+	}
+	otherPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-b", Name: "pod-b", UID: "uid-b"},
+	}
+	instances := monitorapi.InstanceMap{
+		{Namespace: "ns-a", Name: "pod-a", UID: "uid-a"}: pod,
+		{Namespace: "ns-b", Name: "pod-b", UID: "uid-b"}: otherPod,
+	}
+
+	filename := filepath.Join(t.TempDir(), "resource-pods_20230214-203340.zip")
+	if err := InstanceMapToFile(filename, "pods", instances); err != nil {
+		t.Fatal(err)
+	}
+
+	resourceType, actual, err := InstanceMapFromFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resourceType != "pods" {
+		t.Errorf("expected pods, got %q", resourceType)
+	}
+	if len(actual) != 2 {
+		t.Fatalf("expected 2 instances, got %d", len(actual))
+	}
+	actualPod, ok := actual[monitorapi.InstanceKey{Namespace: "ns-a", Name: "pod-a", UID: "uid-a"}].(*corev1.Pod)
+	if !ok {
+		t.Fatalf("expected typed pod, got %T", actual[monitorapi.InstanceKey{Namespace: "ns-a", Name: "pod-a", UID: "uid-a"}])
+	}
+	if actualPod.Spec.NodeName != "node-a" {
+		t.Errorf("expected node-a, got %q", actualPod.Spec.NodeName)
+	}
+}
+
+func TestInstanceMapFromFileUnknownType(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-a", Name: "cm-a", UID: "uid-a"},
+	}
+	instances := monitorapi.InstanceMap{
+		{Namespace: "ns-a", Name: "cm-a", UID: "uid-a"}: cm,
+	}
+
+	filename := filepath.Join(t.TempDir(), "resource-configmaps.zip")
+	if err := InstanceMapToFile(filename, "configmaps", instances); err != nil {
+		t.Fatal(err)
+	}
+
+	_, actual, err := InstanceMapFromFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := actual[monitorapi.InstanceKey{Namespace: "ns-a", Name: "cm-a", UID: "uid-a"}].(*unstructured.Unstructured); !ok {
+		t.Fatalf("expected unstructured, got %#v", actual)
+	}
+}
@@ -2,12 +2,20 @@ package monitor
 
 import (
 	"context"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 )
 
 type Interface interface {
 	Start(ctx context.Context) error
 	Stop(ctx context.Context) (ResultState, error)
 	SerializeResults(ctx context.Context, junitSuiteName, timeSuffix string) error
+	// AddTestJUnits adds the junits of the e2e tests the monitor ran alongside.  They are not written with the
+	// junits of the monitor tests, they are only linked from the content the monitor tests write, so they must be
+	// added before SerializeResults.
+	AddTestJUnits(junits ...*junitapi.JUnitTestCase)
+	// JUnits returns the junits of the monitor tests so far, all of them once SerializeResults returns.
+	JUnits() []*junitapi.JUnitTestCase
 }
 
 type ResultState string
@@ -88,7 +88,7 @@ func (r *monitorTestRegistry) StartCollection(ctx context.Context, adminRESTConf
 		go func(ctx context.Context, invariant *monitorTesttItem) {
 			defer wg.Done()
 
-			testName := fmt.Sprintf("[Jira:%q] monitor test %v setup", invariant.jiraComponent, invariant.name)
+			testName := phaseTestName(invariant.jiraComponent, invariant.name, PhaseSetup)
 			logrus.Infof("  Starting %v for %v", invariant.name, invariant.jiraComponent)
 
 			start := time.Now()
@@ -157,7 +157,7 @@ func (r *monitorTestRegistry) CollectData(ctx context.Context, storageDir string
 		wg.Add(1)
 		go func(ctx context.Context, monitorTest *monitorTesttItem) {
 			defer wg.Done()
-			testName := fmt.Sprintf("[Jira:%q] monitor test %v collection", monitorTest.jiraComponent, monitorTest.name)
+			testName := phaseTestName(monitorTest.jiraComponent, monitorTest.name, PhaseCollection)
 
 			start := time.Now()
 			logrus.Infof("  Starting CollectData for %s", testName)
@@ -236,7 +236,7 @@ func (r *monitorTestRegistry) ConstructComputedIntervals(ctx context.Context, st
 	errs := []error{}
 
 	for _, monitorTest := range r.monitorTests {
-		testName := fmt.Sprintf("[Jira:%q] monitor test %v interval construction", monitorTest.jiraComponent, monitorTest.name)
+		testName := phaseTestName(monitorTest.jiraComponent, monitorTest.name, PhaseIntervalConstruction)
 
 		start := time.Now()
 		localIntervals, err := constructComputedIntervalsWithPanicProtection(ctx, monitorTest.monitorTest, startingIntervals, recordedResources, beginning, end)
@@ -285,7 +285,7 @@ func (r *monitorTestRegistry) EvaluateTestsFromConstructedIntervals(ctx context.
 	errs := []error{}
 
 	for _, monitorTest := range r.monitorTests {
-		testName := fmt.Sprintf("[Jira:%q] monitor test %v test evaluation", monitorTest.jiraComponent, monitorTest.name)
+		testName := phaseTestName(monitorTest.jiraComponent, monitorTest.name, PhaseTestEvaluation)
 
 		start := time.Now()
 		localJunits, err := evaluateTestsFromConstructedIntervalsWithPanicProtection(ctx, monitorTest.monitorTest, finalIntervals)
@@ -329,12 +329,20 @@ func (r *monitorTestRegistry) EvaluateTestsFromConstructedIntervals(ctx context.
 	return junits, utilerrors.NewAggregate(errs)
 }
 
+func (r *monitorTestRegistry) ObserveJUnits(junits []*junitapi.JUnitTestCase) {
+	for _, monitorTest := range r.monitorTests {
+		if observer, ok := monitorTest.monitorTest.(JUnitObserver); ok {
+			observer.ObserveJUnits(junits)
+		}
+	}
+}
+
 func (r *monitorTestRegistry) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) ([]*junitapi.JUnitTestCase, error) {
 	junits := []*junitapi.JUnitTestCase{}
 	errs := []error{}
 
 	for _, monitorTest := range r.monitorTests {
-		testName := fmt.Sprintf("[Jira:%q] monitor test %v writing to storage", monitorTest.jiraComponent, monitorTest.name)
+		testName := phaseTestName(monitorTest.jiraComponent, monitorTest.name, PhaseWritingToStorage)
 
 		start := time.Now()
 
@@ -391,7 +399,7 @@ func (r *monitorTestRegistry) Cleanup(ctx context.Context) ([]*junitapi.JUnitTes
 	errs := []error{}
 
 	for _, monitorTest := range r.monitorTests {
-		testName := fmt.Sprintf("[Jira:%q] monitor test %v cleanup", monitorTest.jiraComponent, monitorTest.name)
+		testName := phaseTestName(monitorTest.jiraComponent, monitorTest.name, PhaseCleanup)
 		log := logrus.WithField("monitorTest", monitorTest.name)
 
 		start := time.Now()
@@ -0,0 +1,39 @@
+package monitortestframework
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Phase is a step every monitor test goes through.  Each phase of each monitor test is reported as its own junit.
+type Phase string
+
+const (
+	PhaseSetup                Phase = "setup"
+	PhaseCollection           Phase = "collection"
+	PhaseIntervalConstruction Phase = "interval construction"
+	PhaseTestEvaluation       Phase = "test evaluation"
+	PhaseWritingToStorage     Phase = "writing to storage"
+	PhaseCleanup              Phase = "cleanup"
+)
+
+var phaseTestNameRegex = regexp.MustCompile(`^\[Jira:("(?:[^"\\]|\\.)*")\] monitor test (.+) (setup|collection|interval construction|test evaluation|writing to storage|cleanup)$`)
+
+func phaseTestName(jiraComponent, monitorTest string, phase Phase) string {
+	return fmt.Sprintf("[Jira:%q] monitor test %v %v", jiraComponent, monitorTest, phase)
+}
+
+// ParsePhaseTestName returns the monitor test and phase of the junit reporting that phase, ok is false for any
+// other junit.
+func ParsePhaseTestName(testName string) (jiraComponent, monitorTest string, phase Phase, ok bool) {
+	matches := phaseTestNameRegex.FindStringSubmatch(testName)
+	if matches == nil {
+		return "", "", "", false
+	}
+	jiraComponent, err := strconv.Unquote(matches[1])
+	if err != nil {
+		return "", "", "", false
+	}
+	return jiraComponent, matches[2], Phase(matches[3]), true
+}
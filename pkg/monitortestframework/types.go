@@ -74,6 +74,13 @@ type MonitorTest interface {
 	Cleanup(ctx context.Context) error
 }
 
+// JUnitObserver may be implemented by a MonitorTest that links the junits of the run from the content it writes.
+// ObserveJUnits is called with the junits of the e2e tests, when the monitor is run by a suite, and of every monitor
+// test so far, before WriteContentToStorage.
+type JUnitObserver interface {
+	ObserveJUnits(junits []*junitapi.JUnitTestCase)
+}
+
 type MonitorTestRegistry interface {
 	AddRegistryOrDie(registry MonitorTestRegistry)
 
@@ -106,6 +113,9 @@ type MonitorTestRegistry interface {
 	// Errors reported will be indicated as junit test failure and will cause job runs to fail.
 	EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error)
 
+	// ObserveJUnits passes junits to every MonitorTest that implements JUnitObserver.
+	ObserveJUnits(junits []*junitapi.JUnitTestCase)
+
 	// WriteContentToStorage writes content to the storage directory that is collected by openshift CI.
 	// Do not write.
 	// 1. junits.  Those should be returned from EvaluateTestsFromConstructedIntervals
@@ -0,0 +1,269 @@
+package alertpolicy
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/exceptions"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// PolicyAPIVersion is the only version of the policy file we understand.  Bump it for incompatible changes.
+	PolicyAPIVersion = "alerts.openshift.io/v1"
+	PolicyKind       = "AlertPolicy"
+)
+
+// Phase is the kind of run an allowed alert applies to.
+type Phase string
+
+const (
+	ConformancePhase Phase = "conformance"
+	UpgradePhase     Phase = "upgrade"
+)
+
+// State is the alert state an allowed alert or an alert test applies to.  Like allowedalerts.AlertState, the states
+// after pending are ordered, so a test on info fails when the alert fires at info, warning, or critical.
+type State string
+
+const (
+	PendingState  State = "pending"
+	FiringState   State = "firing"
+	InfoState     State = "info"
+	WarningState  State = "warning"
+	CriticalState State = "critical"
+)
+
+// AlertPolicy is the file format for alert allowances curated outside origin.  It is read as yaml or json.
+type AlertPolicy struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	// AllowedAlerts are added to the alerts allowed by the backstop test, which covers every alert without a
+	// test of its own.
+	AllowedAlerts []AllowedAlert `json:"allowedAlerts,omitempty"`
+	// AlertTests add per alert tests, or replace the built-in test for the same alert, namespace, and state.
+	AlertTests []AlertTest `json:"alertTests,omitempty"`
+}
+
+// Scope limits where a policy entry applies and records why it exists.
+type Scope struct {
+	// JobType limits the entry to matching jobs.  Empty fields match any job.
+	JobType JobTypeSelector `json:"jobType,omitempty"`
+	// Reason explains why the entry exists.
+	Reason string `json:"reason,omitempty"`
+	// Exception is the owner, the bug tracking the alert, and the expiry.  Allowed alerts with a bug are reported as
+	// known violations.  Unlike the built-in exceptions, policy entries stop applying when they expire.
+	exceptions.Exception `json:",inline"`
+}
+
+type JobTypeSelector struct {
+	Release      string `json:"release,omitempty"`
+	FromRelease  string `json:"fromRelease,omitempty"`
+	Platform     string `json:"platform,omitempty"`
+	Architecture string `json:"architecture,omitempty"`
+	Network      string `json:"network,omitempty"`
+	Topology     string `json:"topology,omitempty"`
+}
+
+// AllowedAlert allows an alert without its own test to be pending or firing.
+type AllowedAlert struct {
+	AlertName string `json:"alertName"`
+	// Namespace must equal the namespace of the alert.  Empty matches alerts without a namespace.
+	Namespace string `json:"namespace,omitempty"`
+	// Selector lists additional labels the alert must have when it is read from prometheus.
+	Selector map[string]string `json:"selector,omitempty"`
+	// States is pending, firing, or both.  Empty means both.
+	States []State `json:"states,omitempty"`
+	// Phases is conformance, upgrade, or both.  Empty means both.
+	Phases []Phase `json:"phases,omitempty"`
+	// FeatureSets limits the entry to clusters with one of these feature sets.  Empty means any.
+	FeatureSets []string `json:"featureSets,omitempty"`
+
+	Scope `json:",inline"`
+}
+
+// AlertTest is a test on how long an alert may be at or above a state.
+type AlertTest struct {
+	AlertName string `json:"alertName"`
+	// Namespace limits the test to alerts in the namespace.  Empty tests the alert in every namespace.
+	Namespace string `json:"namespace,omitempty"`
+	// Component is the component the test is reported against, for instance bz-etcd.
+	Component string `json:"component"`
+	// State is pending, info, warning, or critical.
+	State State `json:"state"`
+	// FlakeAfter and FailAfter replace the historical data with fixed allowances.  When only one is set the
+	// other comes from historical data.
+	FlakeAfter *metav1.Duration `json:"flakeAfter,omitempty"`
+	FailAfter  *metav1.Duration `json:"failAfter,omitempty"`
+	// NeverFail reports the test as a flake at most.
+	NeverFail bool `json:"neverFail,omitempty"`
+
+	Scope `json:",inline"`
+}
+
+// ReadPolicyFile reads and validates an AlertPolicy from a yaml or json file.
+func ReadPolicyFile(filename string) (*AlertPolicy, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := ParsePolicy(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid alert policy %q: %w", filename, err)
+	}
+	return policy, nil
+}
+
+// ParsePolicy reads and validates an AlertPolicy from yaml or json.
+func ParsePolicy(data []byte) (*AlertPolicy, error) {
+	policy := &AlertPolicy{}
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, err
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (p *AlertPolicy) Validate() error {
+	if p.APIVersion != PolicyAPIVersion {
+		return fmt.Errorf("unsupported apiVersion %q, expected %q", p.APIVersion, PolicyAPIVersion)
+	}
+	if p.Kind != PolicyKind {
+		return fmt.Errorf("unsupported kind %q, expected %q", p.Kind, PolicyKind)
+	}
+	for i, allowed := range p.AllowedAlerts {
+		if len(allowed.AlertName) == 0 {
+			return fmt.Errorf("allowedAlerts[%d]: missing alertName", i)
+		}
+		for _, state := range allowed.States {
+			if state != PendingState && state != FiringState {
+				return fmt.Errorf("allowedAlerts[%d]: unknown state %q, expected %s or %s", i, state, PendingState, FiringState)
+			}
+		}
+		for _, phase := range allowed.Phases {
+			if phase != ConformancePhase && phase != UpgradePhase {
+				return fmt.Errorf("allowedAlerts[%d]: unknown phase %q, expected %s or %s", i, phase, ConformancePhase, UpgradePhase)
+			}
+		}
+		if err := allowed.Scope.validate(); err != nil {
+			return fmt.Errorf("allowedAlerts[%d]: %w", i, err)
+		}
+	}
+	for i, test := range p.AlertTests {
+		if len(test.AlertName) == 0 {
+			return fmt.Errorf("alertTests[%d]: missing alertName", i)
+		}
+		if len(test.Component) == 0 {
+			return fmt.Errorf("alertTests[%d]: missing component", i)
+		}
+		switch test.State {
+		case PendingState, InfoState, WarningState, CriticalState:
+		default:
+			return fmt.Errorf("alertTests[%d]: unknown state %q, expected %s, %s, %s, or %s", i, test.State, PendingState, InfoState, WarningState, CriticalState)
+		}
+		if err := test.Scope.validate(); err != nil {
+			return fmt.Errorf("alertTests[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (s Scope) validate() error {
+	if len(s.Reason) == 0 && len(s.Bug) == 0 {
+		return fmt.Errorf("a reason or bug is required")
+	}
+	if _, err := s.ExpiresAt(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Applies returns true if the entry has not expired and matches the job.  A nil jobType only matches entries that
+// are not limited to a job type.
+func (s Scope) Applies(now time.Time, jobType *platformidentification.JobType) bool {
+	if s.Expired(now) {
+		return false
+	}
+	return s.JobType.Matches(jobType)
+}
+
+// Description is the reason and bug for reporting.
+func (s Scope) Description() string {
+	switch {
+	case len(s.Bug) == 0:
+		return s.Reason
+	case len(s.Reason) == 0:
+		return s.Bug
+	default:
+		return fmt.Sprintf("%s (%s)", s.Reason, s.Bug)
+	}
+}
+
+func (s JobTypeSelector) Matches(jobType *platformidentification.JobType) bool {
+	if s == (JobTypeSelector{}) {
+		return true
+	}
+	if jobType == nil {
+		return false
+	}
+	matches := func(selector, value string) bool {
+		return len(selector) == 0 || selector == value
+	}
+	return matches(s.Release, jobType.Release) &&
+		matches(s.FromRelease, jobType.FromRelease) &&
+		matches(s.Platform, jobType.Platform) &&
+		matches(s.Architecture, jobType.Architecture) &&
+		matches(s.Network, jobType.Network) &&
+		matches(s.Topology, jobType.Topology)
+}
+
+// AppliesTo returns true if the allowed alert covers the phase and feature set.
+func (a AllowedAlert) AppliesTo(phase Phase, featureSet string) bool {
+	if len(a.Phases) > 0 && !contains(a.Phases, phase) {
+		return false
+	}
+	if len(a.FeatureSets) > 0 && !contains(a.FeatureSets, featureSet) {
+		return false
+	}
+	return true
+}
+
+// Allows returns true if the allowed alert covers the state, pending or firing.
+func (a AllowedAlert) Allows(state State) bool {
+	return len(a.States) == 0 || contains(a.States, state)
+}
+
+func contains[T comparable](values []T, value T) bool {
+	for _, curr := range values {
+		if curr == value {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	currentPolicyLock sync.Mutex
+	currentPolicy     = &AlertPolicy{APIVersion: PolicyAPIVersion, Kind: PolicyKind}
+)
+
+// SetPolicy replaces the policy applied on top of the built-in allowed alerts and alert tests.
+func SetPolicy(policy *AlertPolicy) {
+	currentPolicyLock.Lock()
+	defer currentPolicyLock.Unlock()
+	currentPolicy = policy
+}
+
+// CurrentPolicy returns the policy set by SetPolicy, which is empty by default.
+func CurrentPolicy() *AlertPolicy {
+	currentPolicyLock.Lock()
+	defer currentPolicyLock.Unlock()
+	return currentPolicy
+}
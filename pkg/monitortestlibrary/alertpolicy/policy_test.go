@@ -0,0 +1,204 @@
+package alertpolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/exceptions"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicy(t *testing.T) {
+	yamlPolicy := `
+apiVersion: alerts.openshift.io/v1
+kind: AlertPolicy
+allowedAlerts:
+- alertName: KubeDaemonSetRolloutStuck
+  namespace: openshift-dns
+  states: [firing]
+  phases: [upgrade]
+  jobType:
+    platform: metal
+  owner: sig-network-edge
+  bug: https://issues.redhat.com/browse/OCPBUGS-1
+  expires: "2024-01-31"
+alertTests:
+- alertName: etcdMembersDown
+  namespace: openshift-etcd
+  component: bz-etcd
+  state: warning
+  failAfter: 10m
+  reason: members go down while nodes reboot
+`
+	jsonPolicy := `{
+  "apiVersion": "alerts.openshift.io/v1",
+  "kind": "AlertPolicy",
+  "allowedAlerts": [{
+    "alertName": "KubeDaemonSetRolloutStuck",
+    "namespace": "openshift-dns",
+    "states": ["firing"],
+    "phases": ["upgrade"],
+    "jobType": {"platform": "metal"},
+    "owner": "sig-network-edge",
+    "bug": "https://issues.redhat.com/browse/OCPBUGS-1",
+    "expires": "2024-01-31"
+  }],
+  "alertTests": [{
+    "alertName": "etcdMembersDown",
+    "namespace": "openshift-etcd",
+    "component": "bz-etcd",
+    "state": "warning",
+    "failAfter": "10m",
+    "reason": "members go down while nodes reboot"
+  }]
+}`
+
+	for name, data := range map[string]string{"yaml": yamlPolicy, "json": jsonPolicy} {
+		t.Run(name, func(t *testing.T) {
+			policy, err := ParsePolicy([]byte(data))
+			require.NoError(t, err)
+
+			require.Len(t, policy.AllowedAlerts, 1)
+			allowed := policy.AllowedAlerts[0]
+			assert.Equal(t, "KubeDaemonSetRolloutStuck", allowed.AlertName)
+			assert.Equal(t, "openshift-dns", allowed.Namespace)
+			assert.Equal(t, "metal", allowed.JobType.Platform)
+			assert.Equal(t, "sig-network-edge", allowed.Owner)
+			assert.True(t, allowed.Allows(FiringState))
+			assert.False(t, allowed.Allows(PendingState))
+			assert.True(t, allowed.AppliesTo(UpgradePhase, "Default"))
+			assert.False(t, allowed.AppliesTo(ConformancePhase, "Default"))
+			assert.Equal(t, "https://issues.redhat.com/browse/OCPBUGS-1", allowed.Description())
+
+			require.Len(t, policy.AlertTests, 1)
+			test := policy.AlertTests[0]
+			assert.Equal(t, WarningState, test.State)
+			require.NotNil(t, test.FailAfter)
+			assert.Equal(t, 10*time.Minute, test.FailAfter.Duration)
+			assert.Nil(t, test.FlakeAfter)
+			assert.Equal(t, "members go down while nodes reboot", test.Description())
+		})
+	}
+}
+
+func TestParsePolicyErrors(t *testing.T) {
+	header := "apiVersion: alerts.openshift.io/v1\nkind: AlertPolicy\n"
+	tests := []struct {
+		name   string
+		policy string
+		errMsg string
+	}{
+		{
+			name:   "wrong version",
+			policy: "apiVersion: alerts.openshift.io/v2\nkind: AlertPolicy\n",
+			errMsg: "unsupported apiVersion",
+		},
+		{
+			name:   "unknown field",
+			policy: header + "allowedAlerts:\n- alertName: Foo\n  reason: r\n  namespaces: [a]\n",
+			errMsg: "unknown field",
+		},
+		{
+			name:   "no reason or bug",
+			policy: header + "allowedAlerts:\n- alertName: Foo\n",
+			errMsg: "allowedAlerts[0]: a reason or bug is required",
+		},
+		{
+			name:   "bad allowed state",
+			policy: header + "allowedAlerts:\n- alertName: Foo\n  reason: r\n  states: [critical]\n",
+			errMsg: "unknown state",
+		},
+		{
+			name:   "bad phase",
+			policy: header + "allowedAlerts:\n- alertName: Foo\n  reason: r\n  phases: [install]\n",
+			errMsg: "unknown phase",
+		},
+		{
+			name:   "bad expires",
+			policy: header + "allowedAlerts:\n- alertName: Foo\n  reason: r\n  expires: next week\n",
+			errMsg: "invalid expires",
+		},
+		{
+			name:   "missing component",
+			policy: header + "alertTests:\n- alertName: Foo\n  state: pending\n  reason: r\n",
+			errMsg: "alertTests[0]: missing component",
+		},
+		{
+			name:   "bad test state",
+			policy: header + "alertTests:\n- alertName: Foo\n  component: c\n  state: firing\n  reason: r\n",
+			errMsg: "unknown state",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePolicy([]byte(tt.policy))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestScopeApplies(t *testing.T) {
+	metal := &platformidentification.JobType{Release: "4.15", Platform: "metal", Architecture: "amd64", Network: "ovn", Topology: "ha"}
+	aws := &platformidentification.JobType{Release: "4.15", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "ha"}
+	now := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		scope    Scope
+		jobType  *platformidentification.JobType
+		expected bool
+	}{
+		{
+			name:     "unscoped",
+			jobType:  aws,
+			expected: true,
+		},
+		{
+			name:     "unscoped without a job type",
+			expected: true,
+		},
+		{
+			name:     "scoped without a job type",
+			scope:    Scope{JobType: JobTypeSelector{Platform: "metal"}},
+			expected: false,
+		},
+		{
+			name:     "matching job type",
+			scope:    Scope{JobType: JobTypeSelector{Platform: "metal", Release: "4.15"}},
+			jobType:  metal,
+			expected: true,
+		},
+		{
+			name:     "other job type",
+			scope:    Scope{JobType: JobTypeSelector{Platform: "metal"}},
+			jobType:  aws,
+			expected: false,
+		},
+		{
+			name:     "expires at the end of the day",
+			scope:    Scope{Exception: exceptions.Exception{Expires: "2024-01-31"}},
+			jobType:  aws,
+			expected: true,
+		},
+		{
+			name:     "expired date",
+			scope:    Scope{Exception: exceptions.Exception{Expires: "2024-01-30"}},
+			jobType:  aws,
+			expected: false,
+		},
+		{
+			name:     "expired time",
+			scope:    Scope{Exception: exceptions.Exception{Expires: "2024-01-31T11:00:00Z"}},
+			jobType:  aws,
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.scope.Applies(now, tt.jobType))
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package allowedalerts
 
 import (
+	"time"
+
 	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/alertpolicy"
 	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 )
 
@@ -79,5 +82,6 @@ func AllAlertTests(jobType *platformidentification.JobType, clusterStability *mo
 
 	ret = append(ret, newAlertTest("bz-apiserver-auth", "PodSecurityViolation", jobType).firing().toTests()...)
 
-	return ret
+	// tests from the alert policy file, if any, are added last so they can replace the ones above.
+	return applyAlertPolicy(ret, alertpolicy.CurrentPolicy(), jobType, time.Now())
 }
@@ -30,23 +30,24 @@ func NewAllowedWhenEtcdRevisionChange(ctx context.Context, kubeClient kubernetes
 	}, nil
 }
 
-func (d *etcdRevisionChangeAllowance) FailAfter(key historicaldata.AlertDataKey) (time.Duration, error) {
+func (d *etcdRevisionChangeAllowance) FailAfter(key historicaldata.AlertDataKey) (time.Duration, string, error) {
 	// if the number of revisions is different compared to what we have collected at the beginning of the test suite
 	// increase allowed time for the alert
 	// the rationale is that some tests might roll out a new version of etcd during each rollout we allow max 3 elections per revision (we assume there are 3 master machines at most)
 	// in the future, we could make this function more dynamic
 	// we will leave it simple for now
 	if d.numberOfRevisionDuringTest > 2 {
-		return time.Duration(d.numberOfRevisionDuringTest) * 15 * time.Minute, nil
+		return time.Duration(d.numberOfRevisionDuringTest) * 15 * time.Minute,
+			fmt.Sprintf("(allowed 15m for each of the %d etcd revisions during the test)", d.numberOfRevisionDuringTest), nil
 
 	}
-	allowed, _, _ := getClosestPercentilesValues(key)
-	return allowed.P99, nil
+	allowed, details, _ := getClosestPercentilesValues(key)
+	return allowed.P99, details, nil
 }
 
-func (d *etcdRevisionChangeAllowance) FlakeAfter(key historicaldata.AlertDataKey) time.Duration {
-	allowed, _, _ := getClosestPercentilesValues(key)
-	return allowed.P95
+func (d *etcdRevisionChangeAllowance) FlakeAfter(key historicaldata.AlertDataKey) (time.Duration, string) {
+	allowed, details, _ := getClosestPercentilesValues(key)
+	return allowed.P95, details
 }
 
 // GetEstimatedNumberOfRevisionsForEtcdOperator calculates the number of revisions that have occurred between now and duration
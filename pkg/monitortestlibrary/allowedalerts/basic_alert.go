@@ -234,20 +234,20 @@ func (a *basicAlertTest) failOrFlake(firingIntervals, pendingIntervals monitorap
 		JobType:        *a.jobType,
 	}
 
-	failAfter, err := a.allowanceCalculator.FailAfter(dataKey)
+	failAfter, failDetails, err := a.allowanceCalculator.FailAfter(dataKey)
 	if err != nil {
 		return fail, fmt.Sprintf("unable to calculate allowance for %s which was at %s, err %v\n\n%s", a.AlertName(), a.AlertState(), err, strings.Join(describe, "\n"))
 	}
-	flakeAfter := a.allowanceCalculator.FlakeAfter(dataKey)
+	flakeAfter, flakeDetails := a.allowanceCalculator.FlakeAfter(dataKey)
 
 	switch {
 	case durationAtOrAboveLevel > failAfter:
-		return fail, fmt.Sprintf("%s was at or above %s for at least %s on %#v (maxAllowed=%s): pending for %s, firing for %s:\n\n%s",
-			a.AlertName(), a.AlertState(), durationAtOrAboveLevel, *a.jobType, failAfter, pendingDuration, firingDuration, strings.Join(describe, "\n"))
+		return fail, fmt.Sprintf("%s was at or above %s for at least %s on %#v (maxAllowed=%s) %s: pending for %s, firing for %s:\n\n%s",
+			a.AlertName(), a.AlertState(), durationAtOrAboveLevel, *a.jobType, failAfter, failDetails, pendingDuration, firingDuration, strings.Join(describe, "\n"))
 
 	case durationAtOrAboveLevel > flakeAfter:
-		return flake, fmt.Sprintf("%s was at or above %s for at least %s on %#v (maxAllowed=%s): pending for %s, firing for %s:\n\n%s",
-			a.AlertName(), a.AlertState(), durationAtOrAboveLevel, *a.jobType, flakeAfter, pendingDuration, firingDuration, strings.Join(describe, "\n"))
+		return flake, fmt.Sprintf("%s was at or above %s for at least %s on %#v (maxAllowed=%s) %s: pending for %s, firing for %s:\n\n%s",
+			a.AlertName(), a.AlertState(), durationAtOrAboveLevel, *a.jobType, flakeAfter, flakeDetails, pendingDuration, firingDuration, strings.Join(describe, "\n"))
 	}
 
 	return pass, ""
@@ -18,22 +18,24 @@ func neverFail(flakeDelegate AlertTestAllowanceCalculator) AlertTestAllowanceCal
 	}
 }
 
-func (d *neverFailAllowance) FailAfter(key historicaldata2.AlertDataKey) (time.Duration, error) {
-	return 24 * time.Hour, nil
+func (d *neverFailAllowance) FailAfter(key historicaldata2.AlertDataKey) (time.Duration, string, error) {
+	return 24 * time.Hour, "(this alert never fails)", nil
 }
 
-func (d *neverFailAllowance) FlakeAfter(key historicaldata2.AlertDataKey) time.Duration {
+func (d *neverFailAllowance) FlakeAfter(key historicaldata2.AlertDataKey) (time.Duration, string) {
 	return d.flakeDelegate.FlakeAfter(key)
 }
 
 // AlertTestAllowanceCalculator provides the duration after which an alert test should flake and fail.
 // For instance, for if the alert test is checking pending, and the alert is pending for 4s and the FailAfter
 // returns 6s and the FlakeAfter returns 2s, then test will flake.
+// Both also return how the duration was chosen, like the match quality and fallback of the historical data, to be
+// included in the test output.
 type AlertTestAllowanceCalculator interface {
 	// FailAfter returns a duration an alert can be at or above the required state before failing.
-	FailAfter(key historicaldata2.AlertDataKey) (time.Duration, error)
+	FailAfter(key historicaldata2.AlertDataKey) (time.Duration, string, error)
 	// FlakeAfter returns a duration an alert can be at or above the required state before flaking.
-	FlakeAfter(key historicaldata2.AlertDataKey) time.Duration
+	FlakeAfter(key historicaldata2.AlertDataKey) (time.Duration, string)
 }
 
 type percentileAllowances struct {
@@ -41,14 +43,14 @@ type percentileAllowances struct {
 
 var DefaultAllowances = &percentileAllowances{}
 
-func (d *percentileAllowances) FailAfter(key historicaldata2.AlertDataKey) (time.Duration, error) {
-	allowed, _, _ := getClosestPercentilesValues(key)
-	return allowed.P99, nil
+func (d *percentileAllowances) FailAfter(key historicaldata2.AlertDataKey) (time.Duration, string, error) {
+	allowed, details, _ := getClosestPercentilesValues(key)
+	return allowed.P99, details, nil
 }
 
-func (d *percentileAllowances) FlakeAfter(key historicaldata2.AlertDataKey) time.Duration {
-	allowed, _, _ := getClosestPercentilesValues(key)
-	return allowed.P95
+func (d *percentileAllowances) FlakeAfter(key historicaldata2.AlertDataKey) (time.Duration, string) {
+	allowed, details, _ := getClosestPercentilesValues(key)
+	return allowed.P95, details
 }
 
 // getClosestPercentilesValues uses the backend and information about the cluster to choose the best historical p99 to operate against.
@@ -65,13 +67,13 @@ func alwaysFlake() AlertTestAllowanceCalculator {
 type alwaysFlakeAllowance struct {
 }
 
-func (d *alwaysFlakeAllowance) FailAfter(key historicaldata2.AlertDataKey) (time.Duration, error) {
+func (d *alwaysFlakeAllowance) FailAfter(key historicaldata2.AlertDataKey) (time.Duration, string, error) {
 	// make it effectively impossible for a test failure here, we only want flakes
-	return 24 * time.Hour, nil
+	return 24 * time.Hour, "(this alert never fails)", nil
 }
 
-func (d *alwaysFlakeAllowance) FlakeAfter(key historicaldata2.AlertDataKey) time.Duration {
-	return 1 * time.Second
+func (d *alwaysFlakeAllowance) FlakeAfter(key historicaldata2.AlertDataKey) (time.Duration, string) {
+	return 1 * time.Second, "(this alert flakes if it occurs at all)"
 }
 
 func failOnAny() AlertTestAllowanceCalculator {
@@ -82,11 +84,11 @@ func failOnAny() AlertTestAllowanceCalculator {
 type alwaysFailAllowance struct {
 }
 
-func (d *alwaysFailAllowance) FailAfter(key historicaldata2.AlertDataKey) (time.Duration, error) {
-	return 1 * time.Second, nil
+func (d *alwaysFailAllowance) FailAfter(key historicaldata2.AlertDataKey) (time.Duration, string, error) {
+	return 1 * time.Second, "(this alert fails if it occurs at all)", nil
 }
 
-func (d *alwaysFailAllowance) FlakeAfter(key historicaldata2.AlertDataKey) time.Duration {
+func (d *alwaysFailAllowance) FlakeAfter(key historicaldata2.AlertDataKey) (time.Duration, string) {
 	// flake is irrelevant here, we're going to fail on ANY duration
-	return 24 * time.Hour
+	return 24 * time.Hour, "(this alert fails if it occurs at all)"
 }
@@ -0,0 +1,90 @@
+package allowedalerts
+
+import (
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/alertpolicy"
+	historicaldata2 "github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+// policyAllowance uses the fixed durations from an alert policy, and historical data for any that are not set.
+type policyAllowance struct {
+	flakeAfter *time.Duration
+	failAfter  *time.Duration
+	delegate   AlertTestAllowanceCalculator
+}
+
+func (d *policyAllowance) FailAfter(key historicaldata2.AlertDataKey) (time.Duration, string, error) {
+	if d.failAfter != nil {
+		return *d.failAfter, "(failAfter from the alert policy)", nil
+	}
+	return d.delegate.FailAfter(key)
+}
+
+func (d *policyAllowance) FlakeAfter(key historicaldata2.AlertDataKey) (time.Duration, string) {
+	if d.flakeAfter != nil {
+		return *d.flakeAfter, "(flakeAfter from the alert policy)"
+	}
+	return d.delegate.FlakeAfter(key)
+}
+
+type alertTestKey struct {
+	alertName  string
+	namespace  string
+	alertState AlertState
+}
+
+// applyAlertPolicy adds the alert tests from the policy that apply to the job, replacing any built-in test for the
+// same alert, namespace, and state.
+func applyAlertPolicy(tests []AlertTest, policy *alertpolicy.AlertPolicy, jobType *platformidentification.JobType, now time.Time) []AlertTest {
+	policyTests := []AlertTest{}
+	replaced := map[alertTestKey]bool{}
+	for _, policyTest := range policy.AlertTests {
+		if !policyTest.Applies(now, jobType) {
+			continue
+		}
+
+		builder := newAlertTest(policyTest.Component, policyTest.AlertName, jobType).inNamespace(policyTest.Namespace)
+		switch policyTest.State {
+		case alertpolicy.PendingState:
+			builder.pending()
+		case alertpolicy.InfoState:
+			builder.firing()
+		case alertpolicy.WarningState:
+			builder.warning()
+		case alertpolicy.CriticalState:
+			builder.critical()
+		}
+		if policyTest.FlakeAfter != nil || policyTest.FailAfter != nil {
+			allowance := &policyAllowance{delegate: DefaultAllowances}
+			if policyTest.FlakeAfter != nil {
+				allowance.flakeAfter = &policyTest.FlakeAfter.Duration
+			}
+			if policyTest.FailAfter != nil {
+				allowance.failAfter = &policyTest.FailAfter.Duration
+			}
+			builder.withAllowance(allowance)
+		}
+		if policyTest.NeverFail {
+			builder.neverFail()
+		}
+
+		policyTests = append(policyTests, builder.toTests()...)
+		replaced[alertTestKey{alertName: policyTest.AlertName, namespace: policyTest.Namespace, alertState: builder.alertState}] = true
+	}
+	if len(policyTests) == 0 {
+		return tests
+	}
+
+	ret := []AlertTest{}
+	for _, test := range tests {
+		if basicTest, ok := test.(*basicAlertTest); ok {
+			if replaced[alertTestKey{alertName: basicTest.alertName, namespace: basicTest.namespace, alertState: basicTest.alertState}] {
+				continue
+			}
+		}
+		ret = append(ret, test)
+	}
+	return append(ret, policyTests...)
+}
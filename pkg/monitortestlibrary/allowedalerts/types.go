@@ -25,8 +25,11 @@ var (
 func GetHistoricalData() *historicaldata.AlertBestMatcher {
 	readResults.Do(
 		func() {
-			var err error
-			historicalData, err = historicaldata.NewAlertMatcher(queryResults)
+			data, err := historicaldata.ReadFromCurrentSource(historicaldata.AlertDataType, queryResults)
+			if err != nil {
+				panic(err)
+			}
+			historicalData, err = historicaldata.NewAlertMatcher(data)
 			if err != nil {
 				panic(err)
 			}
@@ -60,8 +60,11 @@ var (
 func GetCurrentResults() *historicaldata.DisruptionBestMatcher {
 	readResults.Do(
 		func() {
-			var err error
-			historicalData, err = historicaldata.NewDisruptionMatcher(queryResults)
+			data, err := historicaldata.ReadFromCurrentSource(historicaldata.DisruptionDataType, queryResults)
+			if err != nil {
+				panic(err)
+			}
+			historicalData, err = historicaldata.NewDisruptionMatcher(data)
 			if err != nil {
 				panic(err)
 			}
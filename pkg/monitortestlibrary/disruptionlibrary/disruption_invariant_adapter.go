@@ -124,7 +124,7 @@ func createDisruptionJunit(
 		return &junitapi.JUnitTestCase{
 			Name: testName,
 			SkipMessage: &junitapi.SkipMessage{
-				Message: "No historical data to calculate allowedDisruption",
+				Message: fmt.Sprintf("No historical data to calculate allowedDisruption %s", disruptionDetails),
 			},
 		}
 	}
@@ -160,8 +160,12 @@ func createDisruptionJunit(
 	finalAllowedDisruption := time.Duration(roundedFinal) * time.Second
 
 	if roundedDisruptionDuration <= finalAllowedDisruption {
+		// record how the historical data was chosen so passing runs on sparse job types can still be audited.
 		return &junitapi.JUnitTestCase{
 			Name: testName,
+			SystemOut: fmt.Sprintf("%v disruption of %s is within the allowed disruption (maxAllowed=%s) %s:\n%s",
+				locator.OldLocator(), roundedDisruptionDuration, finalAllowedDisruption, disruptionDetails,
+				strings.Join(allowedDetails, "\n")),
 		}
 	}
 
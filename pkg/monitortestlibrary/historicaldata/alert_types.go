@@ -3,7 +3,6 @@ package historicaldata
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
 	"strconv"
 	"time"
 
@@ -33,6 +32,8 @@ type AlertDataKey struct {
 
 type AlertBestMatcher struct {
 	HistoricalData map[AlertDataKey]AlertStatisticalData
+	// MatchConfig overrides CurrentMatchConfig when set.
+	MatchConfig *MatchConfig
 }
 
 func NewAlertMatcher(historicalJSON []byte) (*AlertBestMatcher, error) {
@@ -83,62 +84,62 @@ func NewAlertMatcherWithHistoricalData(data map[AlertDataKey]AlertStatisticalDat
 }
 
 func (b *AlertBestMatcher) bestMatch(key AlertDataKey) (AlertStatisticalData, string, error) {
-	exactMatchKey := key
 	logrus.WithField("alertName", key.AlertName).WithField("entries", len(b.HistoricalData)).
 		Debugf("searching for best match for %+v", key.JobType)
 
-	if percentiles, ok := b.HistoricalData[exactMatchKey]; ok {
-		if percentiles.JobRuns >= defaultMinJobRuns {
-			logrus.Infof("found exact match: %+v", percentiles)
-			return percentiles, "", nil
-		}
-		percentiles, matchReason, err := b.evaluateBestGuesser(PreviousReleaseUpgrade, exactMatchKey)
-		if err != nil {
-			return AlertStatisticalData{}, "", err
-		}
-		if percentiles != (AlertStatisticalData{}) {
-			return percentiles, matchReason, nil
+	candidates := []matchCandidate{}
+	for candidateKey, data := range b.HistoricalData {
+		if candidateKey.AlertName != key.AlertName || candidateKey.AlertNamespace != key.AlertNamespace || candidateKey.AlertLevel != key.AlertLevel {
+			continue
 		}
+		candidates = append(candidates, matchCandidate{
+			jobType: candidateKey.JobType,
+			p50:     data.P50,
+			p75:     data.P75,
+			p95:     data.P95,
+			p99:     data.P99,
+			jobRuns: data.JobRuns,
+		})
 	}
 
 	// tested in TestGetClosestP95Value in allowedbackendisruption.  Should get a local test at some point.
-	for _, nextBestGuesser := range nextBestGuessers {
-		percentiles, matchReason, err := b.evaluateBestGuesser(nextBestGuesser, exactMatchKey)
-		if err != nil {
-			return AlertStatisticalData{}, "", err
-		}
-		if percentiles != (AlertStatisticalData{}) {
-			return percentiles, matchReason, nil
+	match, result := findBestMatch(b.matchConfig(), key.JobType, candidates, defaultMinJobRuns, func(jobRuns int64) bool {
+		return jobRuns >= defaultMinJobRuns
+	})
+	matchKey := key
+	matchKey.JobType = match.jobType
+	switch result.Quality {
+	case NoMatch:
+		// We now only track disruption data for frequently run jobs where we have enough runs to make a reliable P95 or P99
+		// determination. If we did not record historical data for this NURP combination, we do not wish to enforce
+		// disruption testing on a per job basis. Return an empty data result to signal we have no data, and skip the test.
+		return AlertStatisticalData{}, result.String(), nil
+
+	case BlendedMatch:
+		return AlertStatisticalData{
+			AlertDataKey: matchKey,
+			Name:         key.AlertName,
+			P50:          match.p50,
+			P75:          match.p75,
+			P95:          match.p95,
+			P99:          match.p99,
+			JobRuns:      match.jobRuns,
+		}, result.String(), nil
+
+	default:
+		percentiles := b.HistoricalData[matchKey]
+		if result.Quality == ExactMatch {
+			logrus.Infof("found exact match: %+v", percentiles)
 		}
+		return percentiles, result.String(), nil
 	}
-
-	// TODO: ensure our core platforms are here, error if not. We need to be sure our aggregated jobs are running this
-	// but in a way that won't require manual code maintenance every release...
-
-	// We now only track disruption data for frequently run jobs where we have enough runs to make a reliable P95 or P99
-	// determination. If we did not record historical data for this NURP combination, we do not wish to enforce
-	// disruption testing on a per job basis. Return an empty data result to signal we have no data, and skip the test.
-	return AlertStatisticalData{},
-		fmt.Sprintf("(no exact or fuzzy match for jobType=%#v)", key.JobType),
-		nil
 }
 
-func (b *AlertBestMatcher) evaluateBestGuesser(nextBestGuesser NextBestKey, exactMatchKey AlertDataKey) (AlertStatisticalData, string, error) {
-	nextBestJobType, ok := nextBestGuesser(exactMatchKey.JobType)
-	if !ok {
-		return AlertStatisticalData{}, "", nil
-	}
-	nextBestMatchKey := AlertDataKey{
-		AlertName:      exactMatchKey.AlertName,
-		AlertNamespace: exactMatchKey.AlertNamespace,
-		AlertLevel:     exactMatchKey.AlertLevel,
-
-		JobType: nextBestJobType,
-	}
-	if percentiles, ok := b.HistoricalData[nextBestMatchKey]; ok && percentiles.JobRuns >= defaultMinJobRuns {
-		return percentiles, fmt.Sprintf("(no exact match for %#v, fell back to %#v)", exactMatchKey, nextBestMatchKey), nil
+func (b *AlertBestMatcher) matchConfig() MatchConfig {
+	if b.MatchConfig != nil {
+		return *b.MatchConfig
 	}
-	return AlertStatisticalData{}, "", nil
+	return CurrentMatchConfig()
 }
 
 // BestMatchDuration returns the best possible match for this historical data.  It attempts an exact match first, then
@@ -48,6 +48,8 @@ type DataKey struct {
 
 type DisruptionBestMatcher struct {
 	HistoricalData map[DataKey]DisruptionStatisticalData
+	// MatchConfig overrides CurrentMatchConfig when set.
+	MatchConfig *MatchConfig
 }
 
 func NewDisruptionMatcher(historicalJSON []byte) (*DisruptionBestMatcher, error) {
@@ -98,65 +100,63 @@ func NewDisruptionMatcherWithHistoricalData(data map[DataKey]DisruptionStatistic
 }
 
 func (b *DisruptionBestMatcher) bestMatch(name string, jobType platformidentification.JobType, minJobRuns int) (DisruptionStatisticalData, string, error) {
-	exactMatchKey := DataKey{
-		BackendName: name,
-		JobType:     jobType,
-	}
 	logrus.WithField("backend", name).Infof("searching for bestMatch for %+v", jobType)
 	logrus.Infof("historicalData has %d entries", len(b.HistoricalData))
-	if percentiles, ok := b.HistoricalData[exactMatchKey]; ok {
-		if percentiles.JobRuns >= int64(minJobRuns) {
-			logrus.Infof("found exact match: %+v", percentiles)
-			return percentiles, "", nil
-		}
 
-		percentiles, matchReason, err := b.evaluateBestGuesser(name, PreviousReleaseUpgrade, exactMatchKey, jobType)
-		if err != nil {
-			return DisruptionStatisticalData{}, "", err
-		}
-		if percentiles != (DisruptionStatisticalData{}) {
-			return percentiles, matchReason, nil
+	candidates := []matchCandidate{}
+	for key, data := range b.HistoricalData {
+		if key.BackendName != name {
+			continue
 		}
+		candidates = append(candidates, matchCandidate{
+			jobType: key.JobType,
+			p50:     data.P50,
+			p75:     data.P75,
+			p95:     data.P95,
+			p99:     data.P99,
+			jobRuns: data.JobRuns,
+		})
 	}
+
 	// tested in TestGetClosestP99Value in allowedbackendisruption.  Should get a local test at some point.
-	for _, nextBestGuesser := range nextBestGuessers {
-		percentiles, matchReason, err := b.evaluateBestGuesser(name, nextBestGuesser, exactMatchKey, jobType)
-		if err != nil {
-			return DisruptionStatisticalData{}, "", err
-		}
-		if percentiles != (DisruptionStatisticalData{}) {
-			return percentiles, matchReason, nil
-		}
+	match, result := findBestMatch(b.matchConfig(), jobType, candidates, int64(minJobRuns), func(jobRuns int64) bool {
+		return jobRuns > defaultMinJobRuns
+	})
+	switch result.Quality {
+	case NoMatch:
+		logrus.Warn("no exact or fuzzy match, no results will be returned, test will be skipped")
+
+		// TODO: ensure our core platforms are here, error if not. We need to be sure our aggregated jobs are running this
+		// but in a way that won't require manual code maintenance every release...
+
+		// We now only track disruption data for frequently run jobs where we have enough runs to make a reliable P95 or P99
+		// determination. If we did not record historical data for this NURP combination, we do not wish to enforce
+		// disruption testing on a per job basis. Return an empty data result to signal we have no data, and skip the test.
+		return DisruptionStatisticalData{}, result.String(), nil
+
+	case BlendedMatch:
+		logrus.Infof("blended %d neighbours using %q: %+v", len(result.MatchedJobTypes), result.Step, match)
+		return DisruptionStatisticalData{
+			DataKey: DataKey{BackendName: name, JobType: match.jobType},
+			P50:     match.p50,
+			P75:     match.p75,
+			P95:     match.p95,
+			P99:     match.p99,
+			JobRuns: match.jobRuns,
+		}, result.String(), nil
+
+	default:
+		percentiles := b.HistoricalData[DataKey{BackendName: name, JobType: match.jobType}]
+		logrus.Infof("found %s match: %+v", result.Quality, percentiles)
+		return percentiles, result.String(), nil
 	}
-
-	logrus.Warn("no exact or fuzzy match, no results will be returned, test will be skipped")
-
-	// TODO: ensure our core platforms are here, error if not. We need to be sure our aggregated jobs are running this
-	// but in a way that won't require manual code maintenance every release...
-
-	// We now only track disruption data for frequently run jobs where we have enough runs to make a reliable P95 or P99
-	// determination. If we did not record historical data for this NURP combination, we do not wish to enforce
-	// disruption testing on a per job basis. Return an empty data result to signal we have no data, and skip the test.
-	return DisruptionStatisticalData{},
-		fmt.Sprintf("(no exact or fuzzy match for jobType=%#v)", jobType),
-		nil
 }
 
-func (b *DisruptionBestMatcher) evaluateBestGuesser(name string, nextBestGuesser NextBestKey, exactMatchKey DataKey, jobType platformidentification.JobType) (DisruptionStatisticalData, string, error) {
-	nextBestJobType, ok := nextBestGuesser(jobType)
-	if !ok {
-		return DisruptionStatisticalData{}, "", nil
-	}
-	nextBestMatchKey := DataKey{
-		BackendName: name,
-		JobType:     nextBestJobType,
-	}
-	if percentiles, ok := b.HistoricalData[nextBestMatchKey]; ok && percentiles.JobRuns > defaultMinJobRuns {
-		logrus.Infof("no exact match fell back to %#v", nextBestMatchKey)
-		logrus.Infof("found inexact match: %+v", percentiles)
-		return percentiles, fmt.Sprintf("(no exact match for %#v, fell back to %#v)", exactMatchKey, nextBestMatchKey), nil
+func (b *DisruptionBestMatcher) matchConfig() MatchConfig {
+	if b.MatchConfig != nil {
+		return *b.MatchConfig
 	}
-	return DisruptionStatisticalData{}, "", nil
+	return CurrentMatchConfig()
 }
 
 // BestMatchDuration returns the best possible match for this historical data.  It attempts an exact match first, then
@@ -0,0 +1,256 @@
+package historicaldata
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+// JobTypeField is a field of platformidentification.JobType that a FallbackStep may relax.
+type JobTypeField string
+
+const (
+	NetworkField      JobTypeField = "network"
+	TopologyField     JobTypeField = "topology"
+	ArchitectureField JobTypeField = "architecture"
+)
+
+const previousReleaseStepName = "previous-release"
+
+// FallbackStep is one way to look for historical data when the exact job type does not have enough job runs.
+type FallbackStep struct {
+	// Name is how the step is written on the command line, for instance "previous-release" or "network+topology".
+	Name string
+	// PreviousRelease looks at the congruent job type one release back, see PreviousReleaseUpgrade.
+	PreviousRelease bool
+	// Relax lists the job type fields that may differ from the job under test.
+	Relax []JobTypeField
+}
+
+// FallbackChain is the ordered list of steps tried after an exact match.  The first step to find enough job runs wins.
+type FallbackChain []FallbackStep
+
+func (c FallbackChain) String() string {
+	if len(c) == 0 {
+		return "none"
+	}
+	names := []string{}
+	for _, step := range c {
+		names = append(names, step.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+// ParseFallbackChain parses steps like "previous-release", "network", or "network+topology+architecture".
+// Fields joined by + are relaxed together in a single step.
+func ParseFallbackChain(steps []string) (FallbackChain, error) {
+	ret := FallbackChain{}
+	for _, stepName := range steps {
+		step := FallbackStep{Name: stepName}
+		for _, part := range strings.Split(stepName, "+") {
+			switch part {
+			case previousReleaseStepName:
+				step.PreviousRelease = true
+			case string(NetworkField), string(TopologyField), string(ArchitectureField):
+				step.Relax = append(step.Relax, JobTypeField(part))
+			default:
+				return nil, fmt.Errorf("unknown fallback %q in %q, expected %s, %s, %s, or %s joined by +",
+					part, stepName, previousReleaseStepName, NetworkField, TopologyField, ArchitectureField)
+			}
+		}
+		ret = append(ret, step)
+	}
+	return ret, nil
+}
+
+// MatchConfig controls how the best matchers look for historical data.
+type MatchConfig struct {
+	Chain FallbackChain
+	// Blend combines every neighbour found by a step that relaxes job type fields into a single result weighted
+	// by JobRuns, instead of using the neighbour with the most job runs.
+	Blend bool
+}
+
+// DefaultMatchConfig only falls back to the previous release.  Otherwise if we don't have enough data, we don't run
+// the test.  This was implemented after finding that we fail every attempt at a fallback.  Continuing with the
+// previous release helps us in the transition between major releases, so we kept this fallback.
+var DefaultMatchConfig = MatchConfig{
+	Chain: FallbackChain{{Name: previousReleaseStepName, PreviousRelease: true}},
+}
+
+var (
+	matchConfigLock    sync.Mutex
+	currentMatchConfig = DefaultMatchConfig
+)
+
+// SetMatchConfig replaces the config used by matchers that do not have their own.
+func SetMatchConfig(config MatchConfig) {
+	matchConfigLock.Lock()
+	defer matchConfigLock.Unlock()
+	currentMatchConfig = config
+}
+
+// CurrentMatchConfig returns the config used by matchers that do not have their own.
+func CurrentMatchConfig() MatchConfig {
+	matchConfigLock.Lock()
+	defer matchConfigLock.Unlock()
+	return currentMatchConfig
+}
+
+// MatchQuality describes how closely the historical data used for a test matches the job under test.
+type MatchQuality string
+
+const (
+	ExactMatch    MatchQuality = "exact"
+	FallbackMatch MatchQuality = "fallback"
+	BlendedMatch  MatchQuality = "blended"
+	NoMatch       MatchQuality = "none"
+)
+
+// MatchResult records how historical data was chosen so it can be reported with the test results.
+type MatchResult struct {
+	Quality MatchQuality
+	// Step is the fallback step that found the data.  It is empty for exact matches and when nothing matched.
+	Step  string
+	Chain FallbackChain
+	// JobType is the job under test.
+	JobType platformidentification.JobType
+	// MatchedJobTypes are the job types whose data was used.
+	MatchedJobTypes []platformidentification.JobType
+	JobRuns         int64
+}
+
+func (r MatchResult) String() string {
+	switch r.Quality {
+	case NoMatch:
+		return fmt.Sprintf("(no exact or fuzzy match for jobType=%#v, match quality=%s, fallback chain=[%v])", r.JobType, r.Quality, r.Chain)
+	case ExactMatch:
+		return fmt.Sprintf("(match quality=%s with %d job runs, fallback chain=[%v])", r.Quality, r.JobRuns, r.Chain)
+	default:
+		matched := []string{}
+		for _, jobType := range r.MatchedJobTypes {
+			matched = append(matched, fmt.Sprintf("%#v", jobType))
+		}
+		return fmt.Sprintf("(no exact match for %#v, match quality=%s using fallback %q with %d job runs from %s, fallback chain=[%v])",
+			r.JobType, r.Quality, r.Step, r.JobRuns, strings.Join(matched, ", "), r.Chain)
+	}
+}
+
+// matchCandidate is the part of the statistical data shared by disruption and alerts that matching works on.
+type matchCandidate struct {
+	jobType            platformidentification.JobType
+	p50, p75, p95, p99 float64
+	jobRuns            int64
+}
+
+func (s FallbackStep) matches(target, candidate platformidentification.JobType) bool {
+	for _, field := range s.Relax {
+		switch field {
+		case NetworkField:
+			candidate.Network = target.Network
+		case TopologyField:
+			candidate.Topology = target.Topology
+		case ArchitectureField:
+			candidate.Architecture = target.Architecture
+		}
+	}
+	return candidate == target
+}
+
+// findBestMatch looks for an exact match with at least exactMinJobRuns and then walks the fallback chain.
+// Every candidate must be for the same backend or alert.  The result quality is NoMatch when nothing matched.
+func findBestMatch(config MatchConfig, jobType platformidentification.JobType, candidates []matchCandidate, exactMinJobRuns int64, fallbackAccepts func(jobRuns int64) bool) (matchCandidate, MatchResult) {
+	result := MatchResult{
+		Quality: NoMatch,
+		Chain:   config.Chain,
+		JobType: jobType,
+	}
+	if len(candidates) == 0 {
+		return matchCandidate{}, result
+	}
+
+	for _, candidate := range candidates {
+		if candidate.jobType == jobType && candidate.jobRuns >= exactMinJobRuns {
+			result.Quality = ExactMatch
+			result.MatchedJobTypes = []platformidentification.JobType{candidate.jobType}
+			result.JobRuns = candidate.jobRuns
+			return candidate, result
+		}
+	}
+
+	for _, step := range config.Chain {
+		target := jobType
+		if step.PreviousRelease {
+			target, _ = PreviousReleaseUpgrade(jobType)
+		}
+
+		neighbours := []matchCandidate{}
+		for _, candidate := range candidates {
+			if step.matches(target, candidate.jobType) {
+				neighbours = append(neighbours, candidate)
+			}
+		}
+		if len(neighbours) == 0 {
+			continue
+		}
+		// most job runs first, so without blending we use the best sampled neighbour.
+		sort.Slice(neighbours, func(i, j int) bool {
+			if neighbours[i].jobRuns != neighbours[j].jobRuns {
+				return neighbours[i].jobRuns > neighbours[j].jobRuns
+			}
+			return jobTypeString(neighbours[i].jobType) < jobTypeString(neighbours[j].jobType)
+		})
+
+		if config.Blend && len(neighbours) > 1 {
+			blended := blend(target, neighbours)
+			if !fallbackAccepts(blended.jobRuns) {
+				continue
+			}
+			result.Quality = BlendedMatch
+			result.Step = step.Name
+			for _, neighbour := range neighbours {
+				result.MatchedJobTypes = append(result.MatchedJobTypes, neighbour.jobType)
+			}
+			result.JobRuns = blended.jobRuns
+			return blended, result
+		}
+
+		if !fallbackAccepts(neighbours[0].jobRuns) {
+			continue
+		}
+		result.Quality = FallbackMatch
+		result.Step = step.Name
+		result.MatchedJobTypes = []platformidentification.JobType{neighbours[0].jobType}
+		result.JobRuns = neighbours[0].jobRuns
+		return neighbours[0], result
+	}
+
+	return matchCandidate{}, result
+}
+
+// blend averages the percentiles of the neighbours weighted by their JobRuns.  This is an approximation, the
+// percentile of the combined runs is not the weighted average of the percentiles, but it is close enough to
+// keep a job with too few runs of its own from skipping the test entirely.
+func blend(jobType platformidentification.JobType, neighbours []matchCandidate) matchCandidate {
+	ret := matchCandidate{jobType: jobType}
+	for _, neighbour := range neighbours {
+		weight := float64(neighbour.jobRuns)
+		ret.p50 += neighbour.p50 * weight
+		ret.p75 += neighbour.p75 * weight
+		ret.p95 += neighbour.p95 * weight
+		ret.p99 += neighbour.p99 * weight
+		ret.jobRuns += neighbour.jobRuns
+	}
+	if ret.jobRuns == 0 {
+		return ret
+	}
+	total := float64(ret.jobRuns)
+	ret.p50 /= total
+	ret.p75 /= total
+	ret.p95 /= total
+	ret.p99 /= total
+	return ret
+}
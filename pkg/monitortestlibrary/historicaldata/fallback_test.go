@@ -0,0 +1,119 @@
+package historicaldata
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFallbackChain(t *testing.T) {
+	chain, err := ParseFallbackChain([]string{"previous-release", "network+topology", "architecture"})
+	require.NoError(t, err)
+	assert.Equal(t, FallbackChain{
+		{Name: "previous-release", PreviousRelease: true},
+		{Name: "network+topology", Relax: []JobTypeField{NetworkField, TopologyField}},
+		{Name: "architecture", Relax: []JobTypeField{ArchitectureField}},
+	}, chain)
+	assert.Equal(t, "previous-release,network+topology,architecture", chain.String())
+
+	_, err = ParseFallbackChain([]string{"network+platform"})
+	assert.Error(t, err)
+}
+
+func TestDisruptionBestMatchFallbackChain(t *testing.T) {
+	jobType := platformidentification.JobType{
+		Release:      "4.16",
+		FromRelease:  "4.16",
+		Platform:     "aws",
+		Architecture: "arm64",
+		Network:      "ovn",
+		Topology:     "single",
+	}
+	withChanges := func(network, topology string, jobRuns int64, p99 float64) DisruptionStatisticalData {
+		curr := platformidentification.CloneJobType(jobType)
+		curr.Network = network
+		curr.Topology = topology
+		return DisruptionStatisticalData{
+			DataKey: DataKey{BackendName: "kube-api-new-connections", JobType: curr},
+			P99:     p99,
+			JobRuns: jobRuns,
+		}
+	}
+	historicalData := map[DataKey]DisruptionStatisticalData{}
+	for _, curr := range []DisruptionStatisticalData{
+		withChanges("ovn", "single", 10, 100),
+		withChanges("sdn", "single", 91, 10),
+		withChanges("ovn", "ha", 300, 1),
+		withChanges("sdn", "ha", 100, 2),
+	} {
+		historicalData[curr.DataKey] = curr
+	}
+
+	tests := []struct {
+		name            string
+		fallbacks       []string
+		blend           bool
+		expectedQuality MatchQuality
+		expectedStep    string
+		expectedP99     float64
+		expectedJobRuns int64
+	}{
+		{
+			name:            "default chain has no data for the previous release",
+			fallbacks:       []string{"previous-release"},
+			expectedQuality: NoMatch,
+		},
+		{
+			name:            "relaxing network alone never reaches enough runs without blending",
+			fallbacks:       []string{"network"},
+			expectedQuality: NoMatch,
+		},
+		{
+			name:            "relaxing network with blending",
+			fallbacks:       []string{"network"},
+			blend:           true,
+			expectedQuality: BlendedMatch,
+			expectedStep:    "network",
+			expectedP99:     (10*100 + 91*10) / 101.0,
+			expectedJobRuns: 101,
+		},
+		{
+			name:            "first step that matches wins",
+			fallbacks:       []string{"previous-release", "topology", "network+topology"},
+			expectedQuality: FallbackMatch,
+			expectedStep:    "topology",
+			expectedP99:     1,
+			expectedJobRuns: 300,
+		},
+		{
+			name:            "relaxing both blends every neighbour",
+			fallbacks:       []string{"network+topology"},
+			blend:           true,
+			expectedQuality: BlendedMatch,
+			expectedStep:    "network+topology",
+			expectedP99:     (10*100 + 91*10 + 300*1 + 100*2) / 501.0,
+			expectedJobRuns: 501,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, err := ParseFallbackChain(tt.fallbacks)
+			require.NoError(t, err)
+			matcher := NewDisruptionMatcherWithHistoricalData(historicalData)
+			matcher.MatchConfig = &MatchConfig{Chain: chain, Blend: tt.blend}
+
+			actual, details, err := matcher.BestMatchDuration("kube-api-new-connections", jobType, defaultMinJobRuns)
+			require.NoError(t, err)
+			assert.Contains(t, details, "match quality="+string(tt.expectedQuality))
+			assert.Contains(t, details, "fallback chain=["+chain.String()+"]")
+			if len(tt.expectedStep) > 0 {
+				assert.Contains(t, details, fmt.Sprintf("using fallback %q", tt.expectedStep))
+			}
+			assert.Equal(t, tt.expectedJobRuns, actual.JobRuns)
+			assert.Equal(t, DurationOrDie(tt.expectedP99), actual.P99)
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package historicaldata
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LocalHistoricalData is historical data computed from job run artifacts on disk instead of BigQuery.
+type LocalHistoricalData struct {
+	Disruptions []DisruptionStatisticalData
+	Alerts      []AlertStatisticalData
+	// JobRuns is the number of job run directories that contributed data.
+	JobRuns int
+}
+
+// jobRunArtifacts are the files from a single job run that we can compute historical data from.
+// The shapes mirror what the disruptionserializer, alertanalyzer, and clusterinfoserializer monitor tests write.
+// We cannot import those packages without creating a cycle.
+type jobRunArtifacts struct {
+	clusterData platformidentification.ClusterData
+	// disruptionSeconds is keyed by backend name and summed across every backend-disruption file in the run.
+	disruptionSeconds map[string]float64
+	// alertSeconds is summed across every alerts file in the run.
+	alertSeconds map[alertKey]float64
+}
+
+type alertKey struct {
+	name      string
+	namespace string
+	level     string
+}
+
+type backendDisruptionFile struct {
+	BackendDisruptions map[string]struct {
+		BackendName       string
+		DisruptedDuration metav1.Duration
+	}
+}
+
+type alertsFile struct {
+	Alerts []struct {
+		Name      string
+		Namespace string
+		Level     string
+		Duration  metav1.Duration
+	}
+}
+
+// BuildFromJobArtifacts walks artifactDir looking for job runs.  Every directory containing a cluster-data*.json
+// file is a job run.  The backend-disruption*.json and alerts*.json files in that same directory are attributed
+// to the JobType from the cluster data, and percentiles are computed across all job runs of each JobType.
+func BuildFromJobArtifacts(artifactDir string) (*LocalHistoricalData, error) {
+	jobRunDirs := []string{}
+	err := filepath.WalkDir(artifactDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && isArtifact(d.Name(), "cluster-data") {
+			jobRunDirs = append(jobRunDirs, filepath.Dir(path))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	runs := []*jobRunArtifacts{}
+	seenDirs := map[string]bool{}
+	for _, dir := range jobRunDirs {
+		if seenDirs[dir] {
+			continue
+		}
+		seenDirs[dir] = true
+
+		run, err := readJobRunArtifacts(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading job run in %q: %w", dir, err)
+		}
+		runs = append(runs, run)
+	}
+
+	return computeLocalHistoricalData(runs), nil
+}
+
+func isArtifact(filename, prefix string) bool {
+	return strings.HasPrefix(filename, prefix) && strings.HasSuffix(filename, ".json")
+}
+
+func readJobRunArtifacts(dir string) (*jobRunArtifacts, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &jobRunArtifacts{
+		disruptionSeconds: map[string]float64{},
+		alertSeconds:      map[alertKey]float64{},
+	}
+	foundClusterData := false
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		filename := filepath.Join(dir, entry.Name())
+		switch {
+		case isArtifact(entry.Name(), "cluster-data"):
+			// if a run has more than one, they describe the same cluster.
+			if foundClusterData {
+				continue
+			}
+			if err := readJSON(filename, &ret.clusterData); err != nil {
+				return nil, err
+			}
+			foundClusterData = true
+
+		case isArtifact(entry.Name(), "backend-disruption"):
+			disruptions := &backendDisruptionFile{}
+			if err := readJSON(filename, disruptions); err != nil {
+				return nil, err
+			}
+			for name, disruption := range disruptions.BackendDisruptions {
+				if len(disruption.BackendName) > 0 {
+					name = disruption.BackendName
+				}
+				ret.disruptionSeconds[name] += disruption.DisruptedDuration.Seconds()
+			}
+
+		case isArtifact(entry.Name(), "alerts"):
+			alerts := &alertsFile{}
+			if err := readJSON(filename, alerts); err != nil {
+				return nil, err
+			}
+			for _, alert := range alerts.Alerts {
+				// the alerts file uses Warning and Critical, the matcher keys use the alert states.
+				key := alertKey{name: alert.Name, namespace: alert.Namespace, level: strings.ToLower(alert.Level)}
+				ret.alertSeconds[key] += alert.Duration.Seconds()
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+func readJSON(filename string, into interface{}) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, into); err != nil {
+		return fmt.Errorf("failed to decode %q: %w", filename, err)
+	}
+	return nil
+}
+
+func computeLocalHistoricalData(runs []*jobRunArtifacts) *LocalHistoricalData {
+	disruptionObservations := map[DataKey][]float64{}
+	alertObservations := map[AlertDataKey][]float64{}
+	for _, run := range runs {
+		jobType := platformidentification.CloneJobType(run.clusterData.JobType)
+		for backendName, seconds := range run.disruptionSeconds {
+			key := DataKey{BackendName: backendName, JobType: jobType}
+			disruptionObservations[key] = append(disruptionObservations[key], seconds)
+		}
+		for alert, seconds := range run.alertSeconds {
+			key := AlertDataKey{AlertName: alert.name, AlertNamespace: alert.namespace, AlertLevel: alert.level, JobType: jobType}
+			alertObservations[key] = append(alertObservations[key], seconds)
+		}
+	}
+
+	ret := &LocalHistoricalData{JobRuns: len(runs)}
+	for key, observations := range disruptionObservations {
+		sort.Float64s(observations)
+		ret.Disruptions = append(ret.Disruptions, DisruptionStatisticalData{
+			DataKey: key,
+			P50:     percentileCont(observations, 0.50),
+			P75:     percentileCont(observations, 0.75),
+			P95:     percentileCont(observations, 0.95),
+			P99:     percentileCont(observations, 0.99),
+			JobRuns: int64(len(observations)),
+		})
+	}
+	for key, observations := range alertObservations {
+		sort.Float64s(observations)
+		ret.Alerts = append(ret.Alerts, AlertStatisticalData{
+			AlertDataKey: key,
+			Name:         key.AlertName,
+			P50:          percentileCont(observations, 0.50),
+			P75:          percentileCont(observations, 0.75),
+			P95:          percentileCont(observations, 0.95),
+			P99:          percentileCont(observations, 0.99),
+			JobRuns:      int64(len(observations)),
+		})
+	}
+
+	sort.Slice(ret.Disruptions, func(i, j int) bool {
+		return dataKeyString(ret.Disruptions[i].DataKey) < dataKeyString(ret.Disruptions[j].DataKey)
+	})
+	sort.Slice(ret.Alerts, func(i, j int) bool {
+		return alertDataKeyString(ret.Alerts[i].AlertDataKey) < alertDataKeyString(ret.Alerts[j].AlertDataKey)
+	})
+	return ret
+}
+
+// percentileCont matches BigQuery's PERCENTILE_CONT by linearly interpolating between the closest ranks.
+// sortedValues must be sorted and non-empty.
+func percentileCont(sortedValues []float64, percentile float64) float64 {
+	if len(sortedValues) == 1 {
+		return sortedValues[0]
+	}
+	rank := percentile * float64(len(sortedValues)-1)
+	lower := int(rank)
+	if lower >= len(sortedValues)-1 {
+		return sortedValues[len(sortedValues)-1]
+	}
+	fraction := rank - float64(lower)
+	return sortedValues[lower] + fraction*(sortedValues[lower+1]-sortedValues[lower])
+}
+
+func dataKeyString(key DataKey) string {
+	return strings.Join([]string{key.BackendName, jobTypeString(key.JobType)}, "|")
+}
+
+func alertDataKeyString(key AlertDataKey) string {
+	return strings.Join([]string{key.AlertName, key.AlertNamespace, key.AlertLevel, jobTypeString(key.JobType)}, "|")
+}
+
+func jobTypeString(jobType platformidentification.JobType) string {
+	return strings.Join([]string{jobType.Release, jobType.FromRelease, jobType.Platform, jobType.Architecture, jobType.Network, jobType.Topology}, "|")
+}
+
+// encodedPercentiles matches the query_results.json format, which stores the percentiles as strings.
+type encodedPercentiles struct {
+	P95     string
+	P99     string
+	P75     string
+	P50     string
+	JobRuns int64
+}
+
+func newEncodedPercentiles(p50, p75, p95, p99 float64, jobRuns int64) encodedPercentiles {
+	format := func(seconds float64) string {
+		return strconv.FormatFloat(seconds, 'f', 3, 64)
+	}
+	return encodedPercentiles{
+		P95:     format(p95),
+		P99:     format(p99),
+		P75:     format(p75),
+		P50:     format(p50),
+		JobRuns: jobRuns,
+	}
+}
+
+// DisruptionToJSON encodes disruption data in the query_results.json format read by NewDisruptionMatcher.
+func DisruptionToJSON(data []DisruptionStatisticalData) ([]byte, error) {
+	type encodedDisruption struct {
+		DataKey            `json:",inline"`
+		encodedPercentiles `json:",inline"`
+	}
+	encoded := []encodedDisruption{}
+	for _, curr := range data {
+		encoded = append(encoded, encodedDisruption{
+			DataKey:            curr.DataKey,
+			encodedPercentiles: newEncodedPercentiles(curr.P50, curr.P75, curr.P95, curr.P99, curr.JobRuns),
+		})
+	}
+	return json.MarshalIndent(encoded, "", "  ")
+}
+
+// AlertsToJSON encodes alert data in the query_results.json format read by NewAlertMatcher.
+func AlertsToJSON(data []AlertStatisticalData) ([]byte, error) {
+	type encodedAlert struct {
+		AlertDataKey       `json:",inline"`
+		encodedPercentiles `json:",inline"`
+	}
+	encoded := []encodedAlert{}
+	for _, curr := range data {
+		encoded = append(encoded, encodedAlert{
+			AlertDataKey:       curr.AlertDataKey,
+			encodedPercentiles: newEncodedPercentiles(curr.P50, curr.P75, curr.P95, curr.P99, curr.JobRuns),
+		})
+	}
+	return json.MarshalIndent(encoded, "", "  ")
+}
@@ -0,0 +1,80 @@
+package historicaldata
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildFromJobArtifacts(t *testing.T) {
+	artifactDir := t.TempDir()
+
+	writeFile := func(jobRun, filename, content string) {
+		dir := filepath.Join(artifactDir, jobRun, "artifacts", "junit")
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, filename), []byte(content), 0644))
+	}
+	clusterData := func(platform string) string {
+		return fmt.Sprintf(`{"Release": "4.16", "FromRelease": "4.15", "Platform": %q, "Architecture": "amd64", "Network": "ovn", "Topology": "ha", "MasterNodesUpdated": "Y"}`, platform)
+	}
+
+	for i := 0; i < 5; i++ {
+		jobRun := fmt.Sprintf("aws-%d", i)
+		writeFile(jobRun, "cluster-data_20240101-000000.json", clusterData("aws"))
+		writeFile(jobRun, "backend-disruption_20240101-000000.json", fmt.Sprintf(`{"BackendDisruptions": {"kube-api-new-connections": {"BackendName": "kube-api-new-connections", "DisruptedDuration": "%ds"}}}`, i))
+		// a second phase of the same run is added to the first.
+		writeFile(jobRun, "backend-disruption_20240101-010000.json", `{"BackendDisruptions": {"kube-api-new-connections": {"BackendName": "kube-api-new-connections", "DisruptedDuration": "1s"}}}`)
+		writeFile(jobRun, "alerts_20240101-000000.json", fmt.Sprintf(`{"Alerts": [{"Name": "KubePodNotReady", "Namespace": "openshift-etcd", "Level": "Warning", "Duration": "%dm"}]}`, i))
+	}
+	writeFile("gcp-0", "cluster-data_20240101-000000.json", clusterData("gcp"))
+	writeFile("gcp-0", "backend-disruption_20240101-000000.json", `{"BackendDisruptions": {"kube-api-new-connections": {"BackendName": "kube-api-new-connections", "DisruptedDuration": "10s"}}}`)
+	// a directory without cluster data is not a job run.
+	writeFile("unknown", "backend-disruption_20240101-000000.json", `{"BackendDisruptions": {"kube-api-new-connections": {"BackendName": "kube-api-new-connections", "DisruptedDuration": "100s"}}}`)
+
+	actual, err := BuildFromJobArtifacts(artifactDir)
+	require.NoError(t, err)
+	assert.Equal(t, 6, actual.JobRuns)
+	require.Len(t, actual.Disruptions, 2)
+	require.Len(t, actual.Alerts, 1)
+
+	awsJobType := platformidentification.JobType{Release: "4.16", FromRelease: "4.15", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "ha"}
+	awsDisruption := actual.Disruptions[0]
+	assert.Equal(t, DataKey{BackendName: "kube-api-new-connections", JobType: awsJobType}, awsDisruption.DataKey)
+	assert.Equal(t, int64(5), awsDisruption.JobRuns)
+	assert.InDelta(t, 3.0, awsDisruption.P50, 0.001)
+	assert.InDelta(t, 4.8, awsDisruption.P95, 0.001)
+	assert.InDelta(t, 4.96, awsDisruption.P99, 0.001)
+
+	gcpDisruption := actual.Disruptions[1]
+	assert.Equal(t, "gcp", gcpDisruption.Platform)
+	assert.InDelta(t, 10.0, gcpDisruption.P99, 0.001)
+
+	alert := actual.Alerts[0]
+	assert.Equal(t, AlertDataKey{AlertName: "KubePodNotReady", AlertNamespace: "openshift-etcd", AlertLevel: "warning", JobType: awsJobType}, alert.AlertDataKey)
+	assert.InDelta(t, 120.0, alert.P50, 0.001)
+
+	// the output must be readable by the matchers.
+	disruptionJSON, err := DisruptionToJSON(actual.Disruptions)
+	require.NoError(t, err)
+	disruptionMatcher, err := NewDisruptionMatcher(disruptionJSON)
+	require.NoError(t, err)
+	assert.InDelta(t, 4.96, disruptionMatcher.HistoricalData[awsDisruption.DataKey].P99, 0.001)
+
+	alertJSON, err := AlertsToJSON(actual.Alerts)
+	require.NoError(t, err)
+	alertMatcher, err := NewAlertMatcher(alertJSON)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), alertMatcher.HistoricalData[alert.AlertDataKey].JobRuns)
+}
+
+func TestPercentileCont(t *testing.T) {
+	assert.Equal(t, 7.0, percentileCont([]float64{7}, 0.99))
+	assert.Equal(t, 2.5, percentileCont([]float64{1, 2, 3, 4}, 0.5))
+	assert.Equal(t, 4.0, percentileCont([]float64{1, 2, 3, 4}, 1))
+	assert.InDelta(t, 3.85, percentileCont([]float64{1, 2, 3, 4}, 0.95), 0.0001)
+}
@@ -9,14 +9,6 @@ import (
 	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 )
 
-// nextBestGuessers is the order in which to attempt to lookup other alternative matches that are close to this job type.
-var nextBestGuessers = []NextBestKey{
-	// The only guesser we try not is falling back to previous release. Otherwise if we don't have enough data, we don't
-	// run the test. This was implemented after finding that we fail every attempt at a fallback.
-	// Continuing with previous release helps us in the transition between major releases, so we kept this fallback.
-	PreviousReleaseUpgrade,
-}
-
 // NextBestKey returns the next best key in the query_results.json generated from BigQuery and a bool indicating whether this guesser has an opinion.
 // If the bool is false, the key should not be used.
 // Returning true doesn't mean the key exists, it just means that the key is worth trying.
@@ -0,0 +1,216 @@
+package historicaldata
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DataType identifies which historical data set a Source is asked for.
+type DataType string
+
+const (
+	DisruptionDataType DataType = "disruptions"
+	AlertDataType      DataType = "alerts"
+)
+
+// Source provides historical data in the query_results.json format.  This lets downstream distributions supply
+// their own thresholds instead of the data embedded in the binary.
+type Source interface {
+	// Read returns the historical data of the given type.  It returns nil without an error if the source has
+	// no data of this type, in which case the embedded data is used.
+	Read(dataType DataType) ([]byte, error)
+	String() string
+}
+
+var (
+	sourceLock    sync.Mutex
+	currentSource Source = embeddedSource{}
+)
+
+// SetSource replaces the source used for historical data.  It must be called before the historical data is
+// first read, since the matchers are only built once.
+func SetSource(source Source) {
+	sourceLock.Lock()
+	defer sourceLock.Unlock()
+	currentSource = source
+}
+
+// CurrentSource returns the source used for historical data.  It defaults to the embedded data.
+func CurrentSource() Source {
+	sourceLock.Lock()
+	defer sourceLock.Unlock()
+	return currentSource
+}
+
+// NewSourceFromString builds a source from a flag value:
+//
+//	"" or "embedded"    the data compiled into the binary
+//	http(s)://host/path  <url>/disruptions.json and <url>/alerts.json
+//	a directory          <dir>/disruptions.json and <dir>/alerts.json
+//	a file               a JSON object with optional "disruptions" and "alerts" lists
+func NewSourceFromString(location string) (Source, error) {
+	switch {
+	case len(location) == 0 || location == "embedded":
+		return embeddedSource{}, nil
+	case strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://"):
+		return NewHTTPSource(location), nil
+	default:
+		return NewFileSource(location)
+	}
+}
+
+type embeddedSource struct{}
+
+func (embeddedSource) Read(DataType) ([]byte, error) {
+	return nil, nil
+}
+
+func (embeddedSource) String() string {
+	return "embedded"
+}
+
+type fileSource struct {
+	path  string
+	isDir bool
+}
+
+// NewFileSource reads historical data from a directory containing disruptions.json and alerts.json, or from a
+// single file containing a JSON object with "disruptions" and "alerts" lists.
+func NewFileSource(path string) (Source, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("historical data source %q: %w", path, err)
+	}
+	return &fileSource{path: path, isDir: info.IsDir()}, nil
+}
+
+func (s *fileSource) Read(dataType DataType) ([]byte, error) {
+	if s.isDir {
+		data, err := os.ReadFile(filepath.Join(s.path, string(dataType)+".json"))
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return data, err
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	combined := map[DataType]json.RawMessage{}
+	if err := json.Unmarshal(data, &combined); err != nil {
+		return nil, fmt.Errorf("failed to decode %q: %w", s.path, err)
+	}
+	return combined[dataType], nil
+}
+
+func (s *fileSource) String() string {
+	return s.path
+}
+
+type httpSource struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPSource reads historical data from <baseURL>/disruptions.json and <baseURL>/alerts.json.  A 404 means
+// the endpoint has no data of that type.
+func NewHTTPSource(baseURL string) Source {
+	return &httpSource{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: time.Minute},
+	}
+}
+
+func (s *httpSource) Read(dataType DataType) ([]byte, error) {
+	url := s.baseURL + "/" + string(dataType) + ".json"
+	resp, err := s.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return io.ReadAll(resp.Body)
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unexpected status reading %q: %s", url, resp.Status)
+	}
+}
+
+func (s *httpSource) String() string {
+	return s.baseURL
+}
+
+// ReadFromCurrentSource returns the data of the given type from the current source, or embedded if the source
+// has none.
+func ReadFromCurrentSource(dataType DataType, embedded []byte) ([]byte, error) {
+	source := CurrentSource()
+	data, err := source.Read(dataType)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading %s historical data from %v: %w", dataType, source, err)
+	}
+	if data == nil {
+		return embedded, nil
+	}
+	return data, nil
+}
+
+type loadedSource struct {
+	location string
+	data     map[DataType][]byte
+}
+
+// LoadSource reads every data type from the source at location up front, so an unreachable or malformed source
+// fails immediately instead of when the historical data is first needed during the run.
+func LoadSource(location string) (Source, error) {
+	source, err := NewSourceFromString(location)
+	if err != nil {
+		return nil, err
+	}
+	ret := &loadedSource{location: source.String(), data: map[DataType][]byte{}}
+	for _, dataType := range []DataType{DisruptionDataType, AlertDataType} {
+		data, err := source.Read(dataType)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading %s historical data from %v: %w", dataType, source, err)
+		}
+		if data == nil {
+			continue
+		}
+		if err := validate(dataType, data); err != nil {
+			return nil, fmt.Errorf("invalid %s historical data from %v: %w", dataType, source, err)
+		}
+		ret.data[dataType] = data
+	}
+	return ret, nil
+}
+
+func validate(dataType DataType, data []byte) error {
+	switch dataType {
+	case DisruptionDataType:
+		_, err := NewDisruptionMatcher(data)
+		return err
+	case AlertDataType:
+		_, err := NewAlertMatcher(data)
+		return err
+	default:
+		return fmt.Errorf("unknown data type %q", dataType)
+	}
+}
+
+func (s *loadedSource) Read(dataType DataType) ([]byte, error) {
+	return s.data[dataType], nil
+}
+
+func (s *loadedSource) String() string {
+	return s.location
+}
@@ -0,0 +1,97 @@
+package historicaldata
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testDisruptionData = `[{"BackendName": "kube-api-new-connections", "Release": "4.16", "FromRelease": "", "Platform": "aws", "Architecture": "amd64", "Network": "ovn", "Topology": "ha", "P95": "1.000", "P99": "2.000", "JobRuns": 200}]`
+	testAlertData      = `[{"AlertName": "KubePodNotReady", "AlertNamespace": "openshift-etcd", "AlertLevel": "warning", "Release": "4.16", "FromRelease": "", "Platform": "aws", "Architecture": "amd64", "Network": "ovn", "Topology": "ha", "P95": "1.000", "P99": "2.000", "JobRuns": 200}]`
+)
+
+func TestLoadSource(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "disruptions.json"), []byte(testDisruptionData), 0644))
+
+	combinedFile := filepath.Join(t.TempDir(), "historical-data.json")
+	require.NoError(t, os.WriteFile(combinedFile, []byte(`{"alerts": `+testAlertData+`}`), 0644))
+
+	invalidFile := filepath.Join(t.TempDir(), "invalid.json")
+	require.NoError(t, os.WriteFile(invalidFile, []byte(`{"disruptions": [{"P95": "not a number"}]}`), 0644))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/thresholds/disruptions.json":
+			w.Write([]byte(testDisruptionData))
+		case "/thresholds/alerts.json":
+			w.Write([]byte(testAlertData))
+		case "/broken/disruptions.json":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		location       string
+		wantDisruption bool
+		wantAlert      bool
+		expectedErr    bool
+	}{
+		{name: "embedded", location: "embedded"},
+		{name: "empty is embedded", location: ""},
+		{name: "directory", location: dir, wantDisruption: true},
+		{name: "combined file", location: combinedFile, wantAlert: true},
+		{name: "invalid file", location: invalidFile, expectedErr: true},
+		{name: "missing file", location: filepath.Join(dir, "missing"), expectedErr: true},
+		{name: "http", location: server.URL + "/thresholds/", wantDisruption: true, wantAlert: true},
+		{name: "http not found", location: server.URL + "/empty"},
+		{name: "http error", location: server.URL + "/broken", expectedErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := LoadSource(tt.location)
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			disruptions, err := source.Read(DisruptionDataType)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDisruption, disruptions != nil, "disruptions")
+
+			alerts, err := source.Read(AlertDataType)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAlert, alerts != nil, "alerts")
+		})
+	}
+}
+
+func TestReadFromCurrentSource(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "alerts.json"), []byte(testAlertData), 0644))
+	source, err := LoadSource(dir)
+	require.NoError(t, err)
+
+	SetSource(source)
+	defer SetSource(embeddedSource{})
+
+	embedded := []byte("[]")
+	disruptions, err := ReadFromCurrentSource(DisruptionDataType, embedded)
+	require.NoError(t, err)
+	assert.Equal(t, embedded, disruptions)
+
+	alerts, err := ReadFromCurrentSource(AlertDataType, embedded)
+	require.NoError(t, err)
+	assert.Equal(t, testAlertData, string(alerts))
+}
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	v1 "github.com/openshift/api/config/v1"
 	configclient "github.com/openshift/client-go/config/clientset/versioned"
 	operatorv1client "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
+	"github.com/openshift/origin/pkg/exceptions"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -54,9 +57,9 @@ type SimplePathologicalEventMatcher struct {
 	// messageReasonRegex checks the HumanMessage on a structured interval Message.
 	messageHumanRegex *regexp.Regexp
 
-	// jira is a link to a jira (or legacy Bugzilla). If set it implies we consider this event a problem but there's
-	// been a bug filed.
-	jira string
+	// exception links a jira (or legacy Bugzilla) with an owner and expiry. If set it implies we consider this event
+	// a problem but there's been a bug filed. Expired exceptions still allow the event, but are reported as a flake.
+	exception *exceptions.Exception
 
 	// repeatThresholdOverride allows a matcher to allow more than our default number of repeats.
 	// Less will not work as the matcher will not be invoked if we're over our threshold.
@@ -76,6 +79,10 @@ func (ade *SimplePathologicalEventMatcher) Name() string {
 	return ade.name
 }
 
+func (ade *SimplePathologicalEventMatcher) Exception() *exceptions.Exception {
+	return ade.exception
+}
+
 func (ade *SimplePathologicalEventMatcher) Matches(i monitorapi.Interval) bool {
 	l := i.StructuredLocator
 	msg := i.StructuredMessage
@@ -126,8 +133,23 @@ func (ade *SimplePathologicalEventMatcher) Allows(i monitorapi.Interval, topolog
 	return true
 }
 
+// exceptionMatcher is implemented by matchers that allow a known problem until its bug is fixed.
+type exceptionMatcher interface {
+	Exception() *exceptions.Exception
+}
+
 type AllowedPathologicalEventRegistry struct {
 	matchers map[string]EventMatcher
+
+	// exceptionRegistry tracks the matchers with an exception, and which of them allowed an event.
+	exceptionRegistry *exceptions.Registry
+}
+
+func newAllowedPathologicalEventRegistry() *AllowedPathologicalEventRegistry {
+	return &AllowedPathologicalEventRegistry{
+		matchers:          map[string]EventMatcher{},
+		exceptionRegistry: exceptions.NewRegistry("pathological event"),
+	}
 }
 
 func (r *AllowedPathologicalEventRegistry) AddPathologicalEventMatcher(eventMatcher EventMatcher) error {
@@ -137,6 +159,11 @@ func (r *AllowedPathologicalEventRegistry) AddPathologicalEventMatcher(eventMatc
 	if eventMatcher.Name() == "" {
 		return fmt.Errorf("must specify a name for pathological event matchers")
 	}
+	if withException, ok := eventMatcher.(exceptionMatcher); ok && withException.Exception() != nil {
+		if err := r.exceptionRegistry.Add(eventMatcher.Name(), *withException.Exception()); err != nil {
+			return err
+		}
+	}
 	r.matchers[eventMatcher.Name()] = eventMatcher
 	return nil
 }
@@ -176,12 +203,18 @@ func (r *AllowedPathologicalEventRegistry) AllowedByAny(
 		allowed := m.Allows(i, topology)
 		if allowed {
 			logrus.WithField("message", msg).WithField("locator", l).Infof("duplicated event allowed by %s", k)
+			r.exceptionRegistry.Matched(k)
 			return allowed, m
 		}
 	}
 	return false, nil
 }
 
+// ExceptionJUnits reports the matchers with an exception that expired or did not allow any event.
+func (r *AllowedPathologicalEventRegistry) ExceptionJUnits(now time.Time) []*junitapi.JUnitTestCase {
+	return r.exceptionRegistry.JUnits(now)
+}
+
 func (r *AllowedPathologicalEventRegistry) GetMatcherByName(name string) (EventMatcher, error) {
 
 	matcher, ok := r.matchers[name]
@@ -194,7 +227,7 @@ func (r *AllowedPathologicalEventRegistry) GetMatcherByName(name string) (EventM
 // AllowedPathologicalEvents is the list of all allowed duplicate events on all jobs. Upgrade has an additional
 // list which is combined with this one.
 func NewUniversalPathologicalEventMatchers(kubeConfig *rest.Config, finalIntervals monitorapi.Intervals) *AllowedPathologicalEventRegistry {
-	registry := &AllowedPathologicalEventRegistry{matchers: map[string]EventMatcher{}}
+	registry := newAllowedPathologicalEventRegistry()
 
 	// [sig-apps] StatefulSet Basic StatefulSet functionality [StatefulSetBasic] should not deadlock when a pod's predecessor fails [Suite:openshift/conformance/parallel] [Suite:k8s]
 	// PauseNewPods intentionally causes readiness probe to fail.
@@ -402,7 +435,11 @@ func NewUniversalPathologicalEventMatchers(kubeConfig *rest.Config, finalInterva
 		messageHumanRegex:  regexp.MustCompile(`user.openshift.io.v1.*503`),
 		// TODO: Jira long closed as stale, and this problem occurs well outside single node now.
 		// A new bug should probably be filed.
-		jira: "https://bugzilla.redhat.com/show_bug.cgi?id=2017435",
+		exception: &exceptions.Exception{
+			Owner:   "sig-auth",
+			Bug:     "https://bugzilla.redhat.com/show_bug.cgi?id=2017435",
+			Expires: "2027-01-31",
+		},
 	})
 
 	registry.AddPathologicalEventMatcherOrDie(&SimplePathologicalEventMatcher{
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 	"github.com/sirupsen/logrus"
@@ -40,6 +41,7 @@ func TestDuplicatedEventForUpgrade(events monitorapi.Intervals, kubeClientConfig
 	tests := []*junitapi.JUnitTestCase{}
 	tests = append(tests, evaluator.testDuplicatedCoreNamespaceEvents(events, kubeClientConfig)...)
 	tests = append(tests, evaluator.testDuplicatedE2ENamespaceEvents(events, kubeClientConfig)...)
+	tests = append(tests, registry.ExceptionJUnits(time.Now())...)
 	return tests
 }
 
@@ -62,6 +64,7 @@ func TestDuplicatedEventForStableSystem(events monitorapi.Intervals, clientConfi
 	tests := []*junitapi.JUnitTestCase{}
 	tests = append(tests, evaluator.testDuplicatedCoreNamespaceEvents(events, clientConfig)...)
 	tests = append(tests, evaluator.testDuplicatedE2ENamespaceEvents(events, clientConfig)...)
+	tests = append(tests, registry.ExceptionJUnits(time.Now())...)
 	return tests
 }
 
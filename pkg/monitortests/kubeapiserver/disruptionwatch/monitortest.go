@@ -0,0 +1,112 @@
+package disruptionwatch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift/origin/pkg/disruption/backend"
+	disruptionci "github.com/openshift/origin/pkg/disruption/ci"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// watchDisruption keeps long running watches open against the apiservers.  The polling samplers never see a watch
+// that breaks or silently stalls, which is the disruption that operators actually feel.  The intervals use the
+// same disruption locators as the polling samplers, so they end up in backend-disruption.json.
+type watchDisruption struct {
+	samplers []disruptionci.Sampler
+
+	notSupportedReason error
+}
+
+func NewWatchDisruption() monitortestframework.MonitorTest {
+	return &watchDisruption{}
+}
+
+// watchedBackends are the collections that are watched on each apiserver, the field selector keeps the kube-api
+// watch quiet so that it is kept alive by bookmarks.
+var watchedBackends = map[disruptionci.ServerNameType]string{
+	disruptionci.KubeAPIServer:      "/api/v1/namespaces?fieldSelector=metadata.name%3Ddefault",
+	disruptionci.OpenShiftAPIServer: "/apis/image.openshift.io/v1/namespaces/default/imagestreams",
+}
+
+func (w *watchDisruption) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	kubeClient, err := kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	_, err = kubeClient.CoreV1().Namespaces().Get(ctx, "openshift-apiserver", metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{
+			Reason: "namespace openshift-apiserver not present",
+		}
+		return w.notSupportedReason
+	}
+	if err != nil {
+		return err
+	}
+
+	factory := disruptionci.NewDisruptionTestFactory(adminRESTConfig)
+	for _, targetServer := range []disruptionci.ServerNameType{disruptionci.KubeAPIServer, disruptionci.OpenShiftAPIServer} {
+		for _, connectionType := range []monitorapi.BackendConnectionType{monitorapi.NewConnectionType, monitorapi.ReusedConnectionType} {
+			sampler, err := factory.New(disruptionci.TestConfiguration{
+				TestDescriptor: disruptionci.TestDescriptor{
+					TargetServer:     targetServer,
+					LoadBalancerType: backend.ExternalLoadBalancerType,
+					ConnectionType:   connectionType,
+					Protocol:         backend.ProtocolWatch,
+				},
+				Path:           watchedBackends[targetServer],
+				Timeout:        15 * time.Second,
+				SampleInterval: time.Second,
+			})
+			if err != nil {
+				return fmt.Errorf("unable to create the %s watch sampler: %w", targetServer, err)
+			}
+			w.samplers = append(w.samplers, sampler)
+		}
+	}
+
+	for _, sampler := range w.samplers {
+		if err := sampler.StartEndpointMonitoring(ctx, recorder, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *watchDisruption) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+	// the samplers record straight into the recorder, they only need to be stopped.
+	for _, sampler := range w.samplers {
+		sampler.Stop()
+	}
+	return nil, nil, nil
+}
+
+func (w *watchDisruption) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *watchDisruption) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	// there is no historical data for the watch backends yet, the
+	// intervals are collected so that it can be gathered.
+	return nil, w.notSupportedReason
+}
+
+func (w *watchDisruption) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *watchDisruption) Cleanup(ctx context.Context) error {
+	return w.notSupportedReason
+}
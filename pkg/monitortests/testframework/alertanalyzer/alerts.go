@@ -21,6 +21,9 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+// alertQueryStep is the resolution of the ALERTS range queries.  Samples closer than 5s are part of the same interval.
+const alertQueryStep = 2 * time.Second
+
 func fetchEventIntervalsForAllAlerts(ctx context.Context, restConfig *rest.Config, startTime time.Time) ([]monitorapi.Interval, error) {
 	kubeClient, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
@@ -69,12 +72,18 @@ func fetchEventIntervalsForAllAlerts(ctx context.Context, restConfig *rest.Confi
 		return nil, fmt.Errorf("Thanos queriers not connected to all Prometheus sidecars: %w", err)
 	}
 
+	return queryEventIntervalsForAllAlerts(ctx, prometheusClient, startTime, time.Now())
+}
+
+// queryEventIntervalsForAllAlerts builds the pending and firing alert intervals from the ALERTS series between
+// startTime and endTime.  It works against any prometheus query API, not only the in-cluster thanos.
+func queryEventIntervalsForAllAlerts(ctx context.Context, prometheusClient prometheusv1.API, startTime, endTime time.Time) ([]monitorapi.Interval, error) {
 	timeRange := prometheusv1.Range{
 		Start: startTime,
-		End:   time.Now(),
-		Step:  2 * time.Second,
+		End:   endTime,
+		Step:  alertQueryStep,
 	}
-	alerts, warningsForQuery, err := prometheusClient.QueryRange(ctx, `ALERTS{alertstate="firing"}`, timeRange)
+	firingAlerts, warningsForQuery, err := prometheusClient.QueryRange(ctx, `ALERTS{alertstate="firing"}`, timeRange)
 	if err != nil {
 		return nil, err
 	}
@@ -82,19 +91,24 @@ func fetchEventIntervalsForAllAlerts(ctx context.Context, restConfig *rest.Confi
 		fmt.Printf("#### warnings \n\t%v\n", strings.Join(warningsForQuery, "\n\t"))
 	}
 
-	firingAlerts, err := createEventIntervalsForAlerts(ctx, alerts, startTime)
+	pendingAlerts, warningsForQuery, err := prometheusClient.QueryRange(ctx, `ALERTS{alertstate="pending"}`, timeRange)
 	if err != nil {
 		return nil, err
 	}
+	if len(warningsForQuery) > 0 {
+		fmt.Printf("#### warnings \n\t%v\n", strings.Join(warningsForQuery, "\n\t"))
+	}
 
-	alerts, warningsForQuery, err = prometheusClient.QueryRange(ctx, `ALERTS{alertstate="pending"}`, timeRange)
+	return eventIntervalsForAlerts(ctx, firingAlerts, pendingAlerts, startTime)
+}
+
+// eventIntervalsForAlerts builds intervals from the results of range queries for firing and pending ALERTS.
+func eventIntervalsForAlerts(ctx context.Context, firing, pending prometheustypes.Value, startTime time.Time) ([]monitorapi.Interval, error) {
+	firingAlerts, err := createEventIntervalsForAlerts(ctx, firing, startTime)
 	if err != nil {
 		return nil, err
 	}
-	if len(warningsForQuery) > 0 {
-		fmt.Printf("#### warnings \n\t%v\n", strings.Join(warningsForQuery, "\n\t"))
-	}
-	pendingAlerts, err := createEventIntervalsForAlerts(ctx, alerts, startTime)
+	pendingAlerts, err := createEventIntervalsForAlerts(ctx, pending, startTime)
 	if err != nil {
 		return nil, err
 	}
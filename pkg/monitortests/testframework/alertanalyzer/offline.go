@@ -0,0 +1,300 @@
+package alertanalyzer
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	prometheusapi "github.com/prometheus/client_golang/api"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// FetchEventIntervalsFromSource rebuilds the pending and firing alert intervals between startTime and endTime
+// without a cluster.  The source is either:
+//  1. an http(s) URL of a prometheus remote-read endpoint, for instance http://localhost:9090/api/v1/read, or
+//  2. a prometheus TSDB directory, or a tar or tar.gz of one like the one collected by must-gather.  A copy is served
+//     by prometheusBinary on localhost and queried the same way the in-cluster thanos is.  The tests image does not
+//     ship prometheus, it must be installed where the artifacts are processed, see CheckAlertSource.
+func FetchEventIntervalsFromSource(ctx context.Context, source, prometheusBinary string, startTime, endTime time.Time) ([]monitorapi.Interval, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return fetchEventIntervalsFromRemoteRead(ctx, http.DefaultClient, source, startTime, endTime)
+	}
+	return fetchEventIntervalsFromTSDB(ctx, prometheusBinary, source, startTime, endTime)
+}
+
+// CheckAlertSource returns an error if source is a TSDB and prometheusBinary cannot be found, so that a missing
+// prometheus is reported before anything is read or extracted.
+func CheckAlertSource(source, prometheusBinary string) error {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return nil
+	}
+	if _, err := exec.LookPath(prometheusBinary); err != nil {
+		return fmt.Errorf("reading alerts from the TSDB %q needs a prometheus executable, which is not part of the tests image: "+
+			"install prometheus on the PATH or set --prometheus-binary: %w", source, err)
+	}
+	return nil
+}
+
+// ReplaceAlertIntervalsFromSource replaces the alert intervals in intervals with the ones rebuilt from source, see
+// FetchEventIntervalsFromSource, over the time range the intervals cover.
+func ReplaceAlertIntervalsFromSource(ctx context.Context, intervals monitorapi.Intervals, source, prometheusBinary string) (monitorapi.Intervals, error) {
+	var startTime, endTime time.Time
+	for _, interval := range intervals {
+		if !interval.From.IsZero() && (startTime.IsZero() || interval.From.Before(startTime)) {
+			startTime = interval.From
+		}
+		if interval.To.After(endTime) {
+			endTime = interval.To
+		}
+		if interval.From.After(endTime) {
+			endTime = interval.From
+		}
+	}
+	if startTime.IsZero() || endTime.IsZero() {
+		return nil, fmt.Errorf("unable to determine the time range to read alerts for from %d intervals", len(intervals))
+	}
+
+	alertIntervals, err := FetchEventIntervalsFromSource(ctx, source, prometheusBinary, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read alerts from %q: %w", source, err)
+	}
+	logrus.Infof("read %d alert intervals from %s between %s and %s", len(alertIntervals), source, startTime, endTime)
+	return ReplaceAlertIntervals(intervals, alertIntervals), nil
+}
+
+// ReplaceAlertIntervals returns intervals with every alert interval replaced by alertIntervals.
+func ReplaceAlertIntervals(intervals monitorapi.Intervals, alertIntervals []monitorapi.Interval) monitorapi.Intervals {
+	ret := intervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Source != monitorapi.SourceAlert
+	})
+	ret = append(ret, alertIntervals...)
+	sort.Sort(ret)
+	return ret
+}
+
+func fetchEventIntervalsFromTSDB(ctx context.Context, prometheusBinary, source string, startTime, endTime time.Time) ([]monitorapi.Interval, error) {
+	if err := CheckAlertSource(source, prometheusBinary); err != nil {
+		return nil, err
+	}
+	workDir, err := os.MkdirTemp("", "alert-tsdb-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(workDir)
+
+	// prometheus replays and compacts the head on start, so it always runs on a copy.
+	dataDir := filepath.Join(workDir, "data")
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		err = copyTSDB(source, dataDir)
+	} else {
+		err = extractTSDB(source, dataDir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to prepare the TSDB from %q: %w", source, err)
+	}
+
+	address, stop, err := startLocalPrometheus(ctx, prometheusBinary, workDir, dataDir)
+	if err != nil {
+		return nil, err
+	}
+	defer stop()
+
+	client, err := prometheusapi.NewClient(prometheusapi.Config{Address: address})
+	if err != nil {
+		return nil, err
+	}
+	return queryEventIntervalsForAllAlerts(ctx, prometheusv1.NewAPI(client), startTime, endTime)
+}
+
+// startLocalPrometheus serves dataDir on a free localhost port and waits for it to be ready.  Nothing is scraped.
+func startLocalPrometheus(ctx context.Context, prometheusBinary, workDir, dataDir string) (string, func(), error) {
+	configFile := filepath.Join(workDir, "prometheus.yml")
+	if err := os.WriteFile(configFile, []byte("global: {}\n"), 0644); err != nil {
+		return "", nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, err
+	}
+	listenAddress := listener.Addr().String()
+	listener.Close()
+
+	logFile, err := os.Create(filepath.Join(workDir, "prometheus.log"))
+	if err != nil {
+		return "", nil, err
+	}
+	cmd := exec.CommandContext(ctx, prometheusBinary,
+		"--config.file="+configFile,
+		"--storage.tsdb.path="+dataDir,
+		// the data is usually older than the default retention.
+		"--storage.tsdb.retention.time=100y",
+		"--web.listen-address="+listenAddress,
+	)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		logFile.Close()
+		return "", nil, fmt.Errorf("unable to start %s: %w", prometheusBinary, err)
+	}
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+	stop := func() {
+		cmd.Process.Kill()
+		<-exited
+		logFile.Close()
+	}
+
+	address := "http://" + listenAddress
+	logrus.WithField("address", address).WithField("tsdb", dataDir).Info("waiting for local prometheus to load the TSDB")
+	err = wait.PollImmediateWithContext(ctx, time.Second, 10*time.Minute, func(ctx context.Context) (bool, error) {
+		select {
+		case err := <-exited:
+			exited <- err
+			return false, fmt.Errorf("prometheus exited before it was ready: %v", err)
+		default:
+		}
+		resp, err := http.Get(address + "/-/ready")
+		if err != nil {
+			return false, nil
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK, nil
+	})
+	if err != nil {
+		stop()
+		logs, _ := os.ReadFile(logFile.Name())
+		return "", nil, fmt.Errorf("local prometheus did not become ready: %w\n%s", err, string(logs))
+	}
+	return address, stop, nil
+}
+
+func copyTSDB(sourceDir, targetDir string) error {
+	return filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(sourceDir, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(targetDir, relativePath)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		// the lock belongs to the prometheus that wrote the TSDB.
+		if relativePath == "lock" || !info.Mode().IsRegular() {
+			return nil
+		}
+		source, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer source.Close()
+		return writeFile(target, source)
+	})
+}
+
+// extractTSDB extracts a tar or tar.gz of a TSDB.  When the archive holds a single top level directory containing the
+// TSDB, for instance prometheus/, its contents are extracted to targetDir.
+func extractTSDB(archive, targetDir string) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	buffered := bufio.NewReader(file)
+	var reader io.Reader = buffered
+	if magic, err := buffered.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	extractDir := targetDir + ".extract"
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.Clean(header.Name)
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("invalid path %q in %s", header.Name, archive)
+		}
+		target := filepath.Join(extractDir, name)
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if filepath.Base(name) == "lock" {
+				continue
+			}
+			if err := writeFile(target, tarReader); err != nil {
+				return err
+			}
+		}
+	}
+
+	return os.Rename(tsdbRoot(extractDir), targetDir)
+}
+
+// tsdbRoot descends through directories that only hold a single directory until it finds the TSDB, which holds the
+// wal directory or block directories.
+func tsdbRoot(dir string) string {
+	for {
+		entries, err := os.ReadDir(dir)
+		if err != nil || len(entries) != 1 || !entries[0].IsDir() || entries[0].Name() == "wal" {
+			return dir
+		}
+		if _, err := os.Stat(filepath.Join(dir, entries[0].Name(), "meta.json")); err == nil {
+			// a single block
+			return dir
+		}
+		dir = filepath.Join(dir, entries[0].Name())
+	}
+}
+
+func writeFile(target string, source io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, source); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
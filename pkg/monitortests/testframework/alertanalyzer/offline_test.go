@@ -0,0 +1,139 @@
+package alertanalyzer
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_tsdbRoot(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    []string
+		expected string
+	}{
+		{
+			name:     "wal at the top",
+			files:    []string{"wal/00000000"},
+			expected: "",
+		},
+		{
+			name:     "blocks and wal at the top",
+			files:    []string{"wal/00000000", "01HBLOCK/meta.json"},
+			expected: "",
+		},
+		{
+			name:     "single block at the top",
+			files:    []string{"01HBLOCK/meta.json", "01HBLOCK/index"},
+			expected: "",
+		},
+		{
+			name:     "nested in a directory",
+			files:    []string{"prometheus/wal/00000000", "prometheus/01HBLOCK/meta.json"},
+			expected: "prometheus",
+		},
+		{
+			name:     "nested in several directories",
+			files:    []string{"must-gather/monitoring/prometheus/wal/00000000"},
+			expected: "must-gather/monitoring/prometheus",
+		},
+		{
+			name:     "single block nested in a directory",
+			files:    []string{"prometheus/01HBLOCK/meta.json"},
+			expected: "prometheus",
+		},
+		{
+			name:     "empty",
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, file := range tt.files {
+				path := filepath.Join(dir, filepath.FromSlash(file))
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+				require.NoError(t, os.WriteFile(path, []byte("data"), 0644))
+			}
+			assert.Equal(t, filepath.Join(dir, filepath.FromSlash(tt.expected)), tsdbRoot(dir))
+		})
+	}
+}
+
+func Test_extractTSDB(t *testing.T) {
+	files := map[string]string{
+		"prometheus/wal/00000000":         "wal",
+		"prometheus/01HBLOCK/meta.json":   "{}",
+		"prometheus/01HBLOCK/chunks/0001": "chunks",
+		// the lock belongs to the prometheus that wrote the TSDB.
+		"prometheus/lock": "",
+	}
+
+	for _, compressed := range []bool{false, true} {
+		name := "tar"
+		if compressed {
+			name = "tar.gz"
+		}
+		t.Run(name, func(t *testing.T) {
+			archive := filepath.Join(t.TempDir(), "prometheus."+name)
+			writeTestArchive(t, archive, compressed, files)
+
+			targetDir := filepath.Join(t.TempDir(), "data")
+			require.NoError(t, extractTSDB(archive, targetDir))
+
+			for _, file := range []string{"wal/00000000", "01HBLOCK/meta.json", "01HBLOCK/chunks/0001"} {
+				data, err := os.ReadFile(filepath.Join(targetDir, filepath.FromSlash(file)))
+				require.NoError(t, err)
+				assert.Equal(t, files["prometheus/"+file], string(data))
+			}
+			_, err := os.Stat(filepath.Join(targetDir, "lock"))
+			assert.True(t, os.IsNotExist(err), "expected the lock to be skipped")
+		})
+	}
+
+	t.Run("path outside the target", func(t *testing.T) {
+		archive := filepath.Join(t.TempDir(), "prometheus.tar")
+		writeTestArchive(t, archive, false, map[string]string{"../wal/00000000": "wal"})
+		assert.ErrorContains(t, extractTSDB(archive, filepath.Join(t.TempDir(), "data")), "invalid path")
+	})
+}
+
+func writeTestArchive(t *testing.T, path string, compressed bool, files map[string]string) {
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var writer io.Writer = file
+	if compressed {
+		gzipWriter := gzip.NewWriter(file)
+		defer gzipWriter.Close()
+		writer = gzipWriter
+	}
+	tarWriter := tar.NewWriter(writer)
+	defer tarWriter.Close()
+	for name, content := range files {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0644,
+			Size:     int64(len(content)),
+		}))
+		_, err := tarWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+}
+
+func TestCheckAlertSource(t *testing.T) {
+	assert.NoError(t, CheckAlertSource("http://localhost:9090/api/v1/read", "missing-prometheus-binary"))
+	assert.ErrorContains(t, CheckAlertSource(t.TempDir(), "missing-prometheus-binary"), "needs a prometheus executable")
+	// any executable will do, like the test binary.
+	executable, err := os.Executable()
+	require.NoError(t, err)
+	assert.NoError(t, CheckAlertSource(t.TempDir(), executable))
+}
@@ -0,0 +1,169 @@
+package alertanalyzer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	prometheustypes "github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// The prometheus remote-read protocol is a snappy compressed protobuf request and response, see prompb/remote.proto.
+
+const (
+	// lookbackDelta is how far back a prometheus range query looks for the latest sample at each step.
+	lookbackDelta = 5 * time.Minute
+
+	// staleNaNBits is the value prometheus writes when a series ends, for instance when an alert resolves.
+	staleNaNBits = 0x7ff0000000000002
+)
+
+type remoteReadSample struct {
+	timestamp int64
+	value     float64
+}
+
+type remoteReadSeries struct {
+	metric  prometheustypes.Metric
+	samples []remoteReadSample
+}
+
+// fetchEventIntervalsFromRemoteRead builds the pending and firing alert intervals from the raw ALERTS samples served
+// by a remote-read endpoint.  The samples are evaluated the way a range query is, so the intervals match the ones
+// built from thanos during the run.
+func fetchEventIntervalsFromRemoteRead(ctx context.Context, client *http.Client, url string, startTime, endTime time.Time) ([]monitorapi.Interval, error) {
+	firingSeries, err := remoteRead(ctx, client, url, map[string]string{"__name__": "ALERTS", "alertstate": "firing"}, startTime.Add(-lookbackDelta), endTime)
+	if err != nil {
+		return nil, err
+	}
+	pendingSeries, err := remoteRead(ctx, client, url, map[string]string{"__name__": "ALERTS", "alertstate": "pending"}, startTime.Add(-lookbackDelta), endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return eventIntervalsForAlerts(ctx,
+		evaluateRange(firingSeries, startTime, endTime, alertQueryStep),
+		evaluateRange(pendingSeries, startTime, endTime, alertQueryStep),
+		startTime)
+}
+
+// evaluateRange returns the matrix a range query for the series would, each step has the latest sample within the
+// lookback delta unless that sample marks the series stale.
+func evaluateRange(series []remoteReadSeries, startTime, endTime time.Time, step time.Duration) prometheustypes.Matrix {
+	ret := prometheustypes.Matrix{}
+	for _, curr := range series {
+		sort.Slice(curr.samples, func(i, j int) bool { return curr.samples[i].timestamp < curr.samples[j].timestamp })
+
+		stream := &prometheustypes.SampleStream{Metric: curr.metric}
+		next := 0
+		var latest *remoteReadSample
+		for t := startTime; !t.After(endTime); t = t.Add(step) {
+			stepMillis := t.UnixMilli()
+			for next < len(curr.samples) && curr.samples[next].timestamp <= stepMillis {
+				latest = &curr.samples[next]
+				next++
+			}
+			if latest == nil || latest.timestamp <= stepMillis-lookbackDelta.Milliseconds() {
+				continue
+			}
+			if math.Float64bits(latest.value) == staleNaNBits {
+				continue
+			}
+			stream.Values = append(stream.Values, prometheustypes.SamplePair{
+				Timestamp: prometheustypes.TimeFromUnixNano(t.UnixNano()),
+				Value:     prometheustypes.SampleValue(latest.value),
+			})
+		}
+		if len(stream.Values) > 0 {
+			ret = append(ret, stream)
+		}
+	}
+	return ret
+}
+
+func remoteRead(ctx context.Context, client *http.Client, url string, matchers map[string]string, startTime, endTime time.Time) ([]remoteReadSeries, error) {
+	data, err := proto.Marshal(newReadRequest(matchers, startTime, endTime))
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(snappy.Encode(nil, data)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Read-Version", "0.1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	compressed, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("remote read from %s failed with %s: %s", url, resp.Status, string(compressed))
+	}
+
+	data, err = snappy.Decode(nil, compressed)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decompress remote read response from %s: %w", url, err)
+	}
+	response := &prompb.ReadResponse{}
+	if err := proto.Unmarshal(data, response); err != nil {
+		return nil, fmt.Errorf("unable to decode remote read response from %s: %w", url, err)
+	}
+	return seriesFromReadResponse(response), nil
+}
+
+// newReadRequest is a ReadRequest with one query for the equality matchers.  The response type is left at the
+// default, SAMPLES, which every remote-read server supports.
+func newReadRequest(matchers map[string]string, startTime, endTime time.Time) *prompb.ReadRequest {
+	names := []string{}
+	for name := range matchers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	query := &prompb.Query{
+		StartTimestampMs: startTime.UnixMilli(),
+		EndTimestampMs:   endTime.UnixMilli(),
+	}
+	for _, name := range names {
+		query.Matchers = append(query.Matchers, &prompb.LabelMatcher{
+			Type:  prompb.LabelMatcher_EQ,
+			Name:  name,
+			Value: matchers[name],
+		})
+	}
+	return &prompb.ReadRequest{Queries: []*prompb.Query{query}}
+}
+
+// seriesFromReadResponse returns the series in every QueryResult of the response.
+func seriesFromReadResponse(response *prompb.ReadResponse) []remoteReadSeries {
+	ret := []remoteReadSeries{}
+	for _, result := range response.Results {
+		for _, timeSeries := range result.Timeseries {
+			series := remoteReadSeries{metric: prometheustypes.Metric{}}
+			for _, label := range timeSeries.Labels {
+				series.metric[prometheustypes.LabelName(label.Name)] = prometheustypes.LabelValue(label.Value)
+			}
+			for _, sample := range timeSeries.Samples {
+				series.samples = append(series.samples, remoteReadSample{timestamp: sample.Timestamp, value: sample.Value})
+			}
+			ret = append(ret, series)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,138 @@
+package alertanalyzer
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	prometheustypes "github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_evaluateRange(t *testing.T) {
+	start := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	at := func(offset time.Duration) int64 { return start.Add(offset).UnixMilli() }
+	series := []remoteReadSeries{
+		{
+			metric: prometheustypes.Metric{"alertname": "Resolved"},
+			// the rule is evaluated every 30s, and the series is marked stale when the alert resolves.
+			samples: []remoteReadSample{
+				{timestamp: at(30 * time.Second), value: 1},
+				{timestamp: at(0), value: 1},
+				{timestamp: at(60 * time.Second), value: math.Float64frombits(staleNaNBits)},
+			},
+		},
+		{
+			metric: prometheustypes.Metric{"alertname": "Vanished"},
+			// without a stale marker the last sample is used until the lookback delta passes.
+			samples: []remoteReadSample{
+				{timestamp: at(-time.Minute), value: 1},
+			},
+		},
+		{
+			metric:  prometheustypes.Metric{"alertname": "TooOld"},
+			samples: []remoteReadSample{{timestamp: at(-10 * time.Minute), value: 1}},
+		},
+	}
+
+	matrix := evaluateRange(series, start, start.Add(10*time.Minute), 10*time.Second)
+	require.Len(t, matrix, 2)
+
+	assert.Equal(t, prometheustypes.LabelValue("Resolved"), matrix[0].Metric["alertname"])
+	require.Len(t, matrix[0].Values, 6)
+	assert.Equal(t, start, matrix[0].Values[0].Timestamp.Time().UTC())
+	assert.Equal(t, start.Add(50*time.Second), matrix[0].Values[5].Timestamp.Time().UTC())
+
+	assert.Equal(t, prometheustypes.LabelValue("Vanished"), matrix[1].Metric["alertname"])
+	require.Len(t, matrix[1].Values, 24)
+	assert.Equal(t, start.Add(230*time.Second), matrix[1].Values[23].Timestamp.Time().UTC())
+}
+
+func Test_fetchEventIntervalsFromRemoteRead(t *testing.T) {
+	start := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	at := func(offset time.Duration) int64 { return start.Add(offset).UnixMilli() }
+
+	series := map[string][]remoteReadSeries{
+		"firing": {{
+			metric: prometheustypes.Metric{"__name__": "ALERTS", "alertname": "KubePodNotReady", "alertstate": "firing", "namespace": "openshift-etcd", "severity": "warning"},
+			samples: []remoteReadSample{
+				{timestamp: at(2 * time.Minute), value: 1},
+				{timestamp: at(150 * time.Second), value: 1},
+				{timestamp: at(3 * time.Minute), value: math.Float64frombits(staleNaNBits)},
+			},
+		}},
+		"pending": {{
+			metric: prometheustypes.Metric{"__name__": "ALERTS", "alertname": "etcdMembersDown", "alertstate": "pending", "namespace": "openshift-etcd", "severity": "critical"},
+			samples: []remoteReadSample{
+				{timestamp: at(0), value: 1},
+				{timestamp: at(30 * time.Second), value: 1},
+				{timestamp: at(60 * time.Second), value: 1},
+				{timestamp: at(90 * time.Second), value: 1},
+				{timestamp: at(2 * time.Minute), value: math.Float64frombits(staleNaNBits)},
+			},
+		}},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		compressed, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		data, err := snappy.Decode(nil, compressed)
+		require.NoError(t, err)
+		request := &prompb.ReadRequest{}
+		require.NoError(t, proto.Unmarshal(data, request))
+		require.Len(t, request.Queries, 1)
+		state := ""
+		for _, matcher := range request.Queries[0].Matchers {
+			assert.Equal(t, prompb.LabelMatcher_EQ, matcher.Type)
+			if matcher.Name == "alertstate" {
+				state = matcher.Value
+			}
+		}
+		data, err = proto.Marshal(newReadResponse(series[state]))
+		require.NoError(t, err)
+		w.Write(snappy.Encode(nil, data))
+	}))
+	defer server.Close()
+
+	intervals, err := fetchEventIntervalsFromRemoteRead(context.Background(), server.Client(), server.URL, start, start.Add(10*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, intervals, 2)
+
+	firing, pending := intervals[0], intervals[1]
+	assert.Equal(t, monitorapi.SourceAlert, firing.Source)
+	assert.Equal(t, monitorapi.Warning, firing.Level)
+	assert.Equal(t, "KubePodNotReady", firing.StructuredLocator.Keys[monitorapi.LocatorAlertKey])
+	assert.Equal(t, "firing", firing.StructuredMessage.Annotations[monitorapi.AnnotationAlertState])
+	assert.Equal(t, start.Add(2*time.Minute), firing.From.UTC())
+	assert.Equal(t, start.Add(178*time.Second), firing.To.UTC())
+
+	assert.Equal(t, "etcdMembersDown", pending.StructuredLocator.Keys[monitorapi.LocatorAlertKey])
+	assert.Equal(t, monitorapi.Info, pending.Level)
+	assert.Equal(t, start, pending.From.UTC())
+	assert.Equal(t, start.Add(118*time.Second), pending.To.UTC())
+}
+
+// newReadResponse is the server side of seriesFromReadResponse.
+func newReadResponse(series []remoteReadSeries) *prompb.ReadResponse {
+	result := &prompb.QueryResult{}
+	for _, curr := range series {
+		timeSeries := &prompb.TimeSeries{}
+		for name, value := range curr.metric {
+			timeSeries.Labels = append(timeSeries.Labels, prompb.Label{Name: string(name), Value: string(value)})
+		}
+		for _, sample := range curr.samples {
+			timeSeries.Samples = append(timeSeries.Samples, prompb.Sample{Timestamp: sample.timestamp, Value: sample.value})
+		}
+		result.Timeseries = append(result.Timeseries, timeSeries)
+	}
+	return &prompb.ReadResponse{Results: []*prompb.QueryResult{result}}
+}
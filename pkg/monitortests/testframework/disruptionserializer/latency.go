@@ -0,0 +1,139 @@
+package disruptionserializer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/dataloader"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type BackendLatencyList struct {
+	// BackendLatencies is keyed by name to make the consumption easier
+	BackendLatencies map[string]*BackendLatency
+}
+
+type BackendLatency struct {
+	// Name ensure self-identification, it includes the connection type
+	Name string
+	// BackendName is the name of backend.  It is the same across all connection types.
+	BackendName string
+	// ConnectionType is New or Reused
+	ConnectionType string
+
+	// DegradedDuration is the total duration the backend answered
+	// slower than the degraded latency threshold of its sampler.
+	DegradedDuration metav1.Duration
+	DegradedMessages []string
+
+	// Minutes holds the latency percentiles of each minute, in order.
+	Minutes []LatencyMinute
+}
+
+type LatencyMinute struct {
+	Minute  time.Time
+	Samples int64
+	P50Ms   int64
+	P90Ms   int64
+	P99Ms   int64
+}
+
+func writeLatencyData(filename string, latency *BackendLatencyList) error {
+	jsonContent, err := json.MarshalIndent(latency, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, jsonContent, 0644)
+}
+
+func computeLatencyData(eventIntervals monitorapi.Intervals) *BackendLatencyList {
+	ret := &BackendLatencyList{
+		BackendLatencies: map[string]*BackendLatency{},
+	}
+
+	latencyIntervals := eventIntervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceDisruptionLatency
+	})
+	for _, eventInterval := range latencyIntervals {
+		backendDisruptionName := monitorapi.BackendDisruptionNameFromLocator(eventInterval.StructuredLocator)
+		bl, ok := ret.BackendLatencies[backendDisruptionName]
+		if !ok {
+			bl = &BackendLatency{
+				Name:           backendDisruptionName,
+				BackendName:    backendDisruptionName,
+				ConnectionType: strings.Title(eventInterval.StructuredLocator.Keys[monitorapi.LocatorConnectionKey]),
+			}
+			ret.BackendLatencies[backendDisruptionName] = bl
+		}
+
+		annotations := eventInterval.StructuredMessage.Annotations
+		switch eventInterval.StructuredMessage.Reason {
+		case monitorapi.DisruptionLatencySummaryReason:
+			bl.Minutes = append(bl.Minutes, LatencyMinute{
+				Minute:  eventInterval.From.UTC(),
+				Samples: annotationInt(annotations, monitorapi.AnnotationCount),
+				P50Ms:   annotationInt(annotations, monitorapi.AnnotationLatencyP50),
+				P90Ms:   annotationInt(annotations, monitorapi.AnnotationLatencyP90),
+				P99Ms:   annotationInt(annotations, monitorapi.AnnotationLatencyP99),
+			})
+		case monitorapi.DisruptionLatencyDegradedReason:
+			bl.DegradedDuration.Duration += eventInterval.To.Sub(eventInterval.From)
+			bl.DegradedMessages = append(bl.DegradedMessages, eventInterval.StructuredMessage.HumanMessage)
+		}
+	}
+
+	for _, bl := range ret.BackendLatencies {
+		sort.Slice(bl.Minutes, func(i, j int) bool { return bl.Minutes[i].Minute.Before(bl.Minutes[j].Minute) })
+	}
+	return ret
+}
+
+// latencyDataFile flattens the per minute latency of each backend
+// into the rows of the backend_latency table.
+func latencyDataFile(latency *BackendLatencyList) dataloader.DataFile {
+	names := []string{}
+	for name := range latency.BackendLatencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := make([]map[string]string, 0)
+	for _, name := range names {
+		bl := latency.BackendLatencies[name]
+		for _, minute := range bl.Minutes {
+			rows = append(rows, map[string]string{
+				"BackendName":    bl.BackendName,
+				"ConnectionType": bl.ConnectionType,
+				"Minute":         minute.Minute.Format(time.RFC3339),
+				"Samples":        strconv.FormatInt(minute.Samples, 10),
+				"P50Ms":          strconv.FormatInt(minute.P50Ms, 10),
+				"P90Ms":          strconv.FormatInt(minute.P90Ms, 10),
+				"P99Ms":          strconv.FormatInt(minute.P99Ms, 10),
+			})
+		}
+	}
+
+	return dataloader.DataFile{
+		TableName: "backend_latency",
+		Schema: map[string]dataloader.DataType{
+			"BackendName":    dataloader.DataTypeString,
+			"ConnectionType": dataloader.DataTypeString,
+			"Minute":         dataloader.DataTypeTimestamp,
+			"Samples":        dataloader.DataTypeInteger,
+			"P50Ms":          dataloader.DataTypeInteger,
+			"P90Ms":          dataloader.DataTypeInteger,
+			"P99Ms":          dataloader.DataTypeInteger,
+		},
+		Rows: rows,
+	}
+}
+
+func annotationInt(annotations map[monitorapi.AnnotationKey]string, key monitorapi.AnnotationKey) int64 {
+	value, _ := strconv.ParseInt(annotations[key], 10, 64)
+	return value
+}
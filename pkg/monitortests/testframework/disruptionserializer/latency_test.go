@@ -0,0 +1,63 @@
+package disruptionserializer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestComputeLatencyData(t *testing.T) {
+	start := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	locator := monitorapi.NewLocator().LocateDisruptionCheck("kube-api-new-connections", "kube-api", monitorapi.NewConnectionType)
+	summary := func(minute time.Time, count, p50, p90, p99 string) monitorapi.Interval {
+		return monitorapi.NewInterval(monitorapi.SourceDisruptionLatency, monitorapi.Info).Locator(locator).
+			Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionLatencySummaryReason).
+				WithAnnotation(monitorapi.AnnotationCount, count).
+				WithAnnotation(monitorapi.AnnotationLatencyP50, p50).
+				WithAnnotation(monitorapi.AnnotationLatencyP90, p90).
+				WithAnnotation(monitorapi.AnnotationLatencyP99, p99)).
+			Build(minute, minute.Add(time.Minute))
+	}
+	degraded := func(from, to time.Time) monitorapi.Interval {
+		return monitorapi.NewInterval(monitorapi.SourceDisruptionLatency, monitorapi.Warning).Locator(locator).
+			Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionLatencyDegradedReason).
+				WithAnnotation(monitorapi.AnnotationLatencyThreshold, "2000").
+				HumanMessage("slow")).
+			Build(from, to)
+	}
+
+	intervals := monitorapi.Intervals{
+		summary(start.Add(time.Minute), "58", "30", "90", "2500"),
+		summary(start, "60", "20", "40", "80"),
+		degraded(start.Add(70*time.Second), start.Add(80*time.Second)),
+		degraded(start.Add(90*time.Second), start.Add(95*time.Second)),
+	}
+
+	latency := computeLatencyData(intervals)
+	if !assert.Contains(t, latency.BackendLatencies, "kube-api-new-connections") {
+		return
+	}
+	bl := latency.BackendLatencies["kube-api-new-connections"]
+	assert.Equal(t, "New", bl.ConnectionType)
+	assert.Equal(t, metav1.Duration{Duration: 15 * time.Second}, bl.DegradedDuration)
+	assert.Equal(t, []string{"slow", "slow"}, bl.DegradedMessages)
+	assert.Equal(t, []LatencyMinute{
+		{Minute: start, Samples: 60, P50Ms: 20, P90Ms: 40, P99Ms: 80},
+		{Minute: start.Add(time.Minute), Samples: 58, P50Ms: 30, P90Ms: 90, P99Ms: 2500},
+	}, bl.Minutes)
+
+	dataFile := latencyDataFile(latency)
+	assert.Equal(t, "backend_latency", dataFile.TableName)
+	assert.Equal(t, []map[string]string{
+		{"BackendName": "kube-api-new-connections", "ConnectionType": "New", "Minute": "2023-01-01T10:00:00Z", "Samples": "60", "P50Ms": "20", "P90Ms": "40", "P99Ms": "80"},
+		{"BackendName": "kube-api-new-connections", "ConnectionType": "New", "Minute": "2023-01-01T10:01:00Z", "Samples": "58", "P50Ms": "30", "P90Ms": "90", "P99Ms": "2500"},
+	}, dataFile.Rows)
+
+	// the latency intervals are not disruption
+	assert.Empty(t, computeDisruptionData(intervals).BackendDisruptions)
+}
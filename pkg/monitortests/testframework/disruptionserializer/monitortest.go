@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/openshift/origin/pkg/dataloader"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"github.com/openshift/origin/pkg/monitortestframework"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
@@ -41,7 +42,15 @@ func (*disruptionSummarySerializer) EvaluateTestsFromConstructedIntervals(ctx co
 
 func (*disruptionSummarySerializer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
 	backendDisruption := computeDisruptionData(finalIntervals)
-	return writeDisruptionData(filepath.Join(storageDir, fmt.Sprintf("backend-disruption%s.json", timeSuffix)), backendDisruption)
+	if err := writeDisruptionData(filepath.Join(storageDir, fmt.Sprintf("backend-disruption%s.json", timeSuffix)), backendDisruption); err != nil {
+		return err
+	}
+
+	backendLatency := computeLatencyData(finalIntervals)
+	if err := writeLatencyData(filepath.Join(storageDir, fmt.Sprintf("backend-latency%s.json", timeSuffix)), backendLatency); err != nil {
+		return err
+	}
+	return dataloader.WriteDataFile(filepath.Join(storageDir, fmt.Sprintf("backend-latency%s-%s", timeSuffix, dataloader.AutoDataLoaderSuffix)), latencyDataFile(backendLatency))
 }
 
 func (*disruptionSummarySerializer) Cleanup(ctx context.Context) error {
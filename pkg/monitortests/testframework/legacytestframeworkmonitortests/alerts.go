@@ -6,7 +6,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/openshift/origin/pkg/alerts"
+	"github.com/openshift/origin/pkg/exceptions"
 	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/alertpolicy"
 	"github.com/openshift/origin/pkg/monitortestlibrary/allowedalerts"
 	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
 	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
@@ -31,6 +34,7 @@ type AllowedAlertsFunc func(featureSet configv1.FeatureSet) (allowedFiringWithBu
 
 func testAlerts(events monitorapi.Intervals,
 	allowancesFunc AllowedAlertsFunc,
+	phase alertpolicy.Phase,
 	jobType *platformidentification.JobType,
 	clusterStability *monitortestframework.ClusterStabilityDuringTest,
 	restConfig *rest.Config,
@@ -73,7 +77,7 @@ func testAlerts(events monitorapi.Intervals,
 		}
 	}
 
-	ret := RunAlertTests(jobType, clusterStability, allowancesFunc, featureSet, etcdAllowance, events, recordedResource)
+	ret := RunAlertTests(jobType, clusterStability, allowancesFunc, phase, featureSet, etcdAllowance, events, recordedResource)
 	return ret
 }
 
@@ -82,6 +86,7 @@ func testAlerts(events monitorapi.Intervals,
 func RunAlertTests(jobType *platformidentification.JobType,
 	clusterStability *monitortestframework.ClusterStabilityDuringTest,
 	allowancesFunc AllowedAlertsFunc,
+	phase alertpolicy.Phase,
 	featureSet configv1.FeatureSet,
 	etcdAllowance allowedalerts.AlertTestAllowanceCalculator,
 	events monitorapi.Intervals,
@@ -111,7 +116,7 @@ func RunAlertTests(jobType *platformidentification.JobType,
 	firingIntervals := events.Filter(monitorapi.AlertFiring())
 
 	// Run the backstop catch all for all other alerts:
-	ret = append(ret, runBackstopTest(allowancesFunc, featureSet, pendingIntervals, firingIntervals, alertTests)...)
+	ret = append(ret, runBackstopTest(allowancesFunc, phase, jobType, featureSet, pendingIntervals, firingIntervals, alertTests)...)
 
 	// TODO: Run a test to ensure no new alerts fired:
 	ret = append(ret, runNoNewAlertsFiringTest(allowedalerts.GetHistoricalData(), firingIntervals)...)
@@ -123,6 +128,8 @@ func RunAlertTests(jobType *platformidentification.JobType,
 // and look for any pending/firing intervals that are not within sufficient range.
 func runBackstopTest(
 	allowancesFunc AllowedAlertsFunc,
+	phase alertpolicy.Phase,
+	jobType *platformidentification.JobType,
 	featureSet configv1.FeatureSet,
 	pendingIntervals monitorapi.Intervals,
 	firingIntervals monitorapi.Intervals,
@@ -165,6 +172,14 @@ func runBackstopTest(
 		}
 	}
 
+	exceptionRegistry := exceptions.NewRegistry("allowed alert")
+	registerAlertExceptions(exceptionRegistry, "firing", firingAlertsWithBugs)
+	registerAlertExceptions(exceptionRegistry, "pending", pendingAlertsWithBugs)
+	// expired policy entries no longer allow the alert, register them so the expiry is reported.
+	expiredFiringAlerts, expiredPendingAlerts := alerts.ExpiredPolicyConditions(alertpolicy.CurrentPolicy(), phase, jobType, featureSet, time.Now())
+	registerAlertExceptions(exceptionRegistry, "firing", expiredFiringAlerts)
+	registerAlertExceptions(exceptionRegistry, "pending", expiredPendingAlerts)
+
 	knownViolations := sets.NewString()
 	unexpectedViolations := sets.NewString()
 	unexpectedViolationsAsFlakes := sets.NewString()
@@ -184,6 +199,7 @@ func runBackstopTest(
 			continue
 		}
 		if cause := firingAlertsWithBugs.MatchesInterval(firing); cause != nil {
+			exceptionRegistry.Matched(alertExceptionName("firing", *cause))
 			knownViolations.Insert(fmt.Sprintf("%s result=allow bug=%s", violation, cause.Text))
 		} else {
 			unexpectedViolations.Insert(fmt.Sprintf("%s result=reject", violation))
@@ -203,6 +219,7 @@ func runBackstopTest(
 			continue
 		}
 		if cause := pendingAlertsWithBugs.MatchesInterval(pending); cause != nil {
+			exceptionRegistry.Matched(alertExceptionName("pending", *cause))
 			knownViolations.Insert(fmt.Sprintf("%s result=allow bug=%s", violation, cause.Text))
 		} else {
 			// treat pending errors as a flake right now because we are still trying to determine the scope
@@ -234,9 +251,29 @@ func runBackstopTest(
 			SystemOut: output,
 		})
 	}
+	ret = append(ret, exceptionRegistry.JUnits(time.Now())...)
 	return ret
 }
 
+// registerAlertExceptions adds the exceptions of the conditions allowed with a bug to the registry.
+func registerAlertExceptions(registry *exceptions.Registry, state string, conditionsWithBugs helper.MetricConditions) {
+	for _, condition := range conditionsWithBugs {
+		if condition.Exception == nil {
+			continue
+		}
+		if err := registry.Add(alertExceptionName(state, condition), *condition.Exception); err != nil {
+			logrus.WithError(err).Warn("ignoring invalid alert exception")
+		}
+	}
+}
+
+func alertExceptionName(state string, condition helper.MetricCondition) string {
+	if len(condition.AlertNamespace) == 0 {
+		return fmt.Sprintf("%s alert %s", state, condition.AlertName)
+	}
+	return fmt.Sprintf("%s alert %s in %s", state, condition.AlertName, condition.AlertNamespace)
+}
+
 func isSkippedAlert(alertName string) bool {
 	// Some alerts we always skip over in CI:
 	for _, a := range allowedalerts.AllowedAlertNames {
@@ -0,0 +1,61 @@
+package resourcewatchcollector
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/resourcewatch/storage"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// DisplayedGroups are the configuration changes plotted on the timeline, the changes to the other
+// resources, like pods, are too many to display.
+var DisplayedGroups = []string{"config.openshift.io", "operator.openshift.io"}
+
+// IntervalsFromHistory converts the changes recorded by run-resourcewatch between since and until into intervals.
+// Only the changes to the resources of groups are read, or every change if groups is empty.  The history holds
+// every watched object, so reading only the groups that are needed avoids decoding the objects of the others.
+func IntervalsFromHistory(repositoryPath string, since, until time.Time, groups []string) (monitorapi.Intervals, error) {
+	changes, err := storage.History(repositoryPath, storage.HistoryQuery{Since: since, Until: until, Groups: groups, WithObjects: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the resourcewatch history in %s: %w", repositoryPath, err)
+	}
+
+	ret := monitorapi.Intervals{}
+	for _, change := range changes {
+		ret = append(ret, intervalFromChange(change))
+	}
+	return ret, nil
+}
+
+func intervalFromChange(change storage.Change) monitorapi.Interval {
+	message := monitorapi.NewMessage().
+		WithAnnotation(monitorapi.AnnotationModifiers, strings.Join(change.Modifiers, ","))
+
+	switch change.Operation {
+	case storage.OperationAdded:
+		message = message.Reason(monitorapi.ResourceAddedReason).
+			HumanMessagef("added by %s", strings.Join(change.Modifiers, ", "))
+	case storage.OperationRemoved:
+		message = message.Reason(monitorapi.ResourceDeletedReason).
+			HumanMessagef("removed by %s", strings.Join(change.Modifiers, ", "))
+	default:
+		fieldDiff, err := storage.FieldDiff(change.Before, change.After)
+		if err != nil {
+			fieldDiff = fmt.Sprintf("unknown: %v", err)
+		}
+		message = message.Reason(monitorapi.ResourceModifiedReason).
+			WithAnnotation(monitorapi.AnnotationFieldDiff, fieldDiff).
+			HumanMessagef("modified by %s", strings.Join(change.Modifiers, ", "))
+	}
+
+	interval := monitorapi.NewInterval(monitorapi.SourceResourceWatch, monitorapi.Info).
+		Locator(monitorapi.NewLocator().LocateResource(change.Object.Group, change.Object.Resource, change.Object.Namespace, change.Object.Name)).
+		Message(message)
+	if sets.NewString(DisplayedGroups...).Has(change.Object.Group) {
+		interval = interval.Display()
+	}
+	return interval.Build(change.Time, change.Time)
+}
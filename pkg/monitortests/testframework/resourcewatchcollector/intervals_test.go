@@ -0,0 +1,111 @@
+package resourcewatchcollector
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestIntervalsFromHistory(t *testing.T) {
+	repositoryPath := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repositoryPath
+		cmd.Env = append(os.Environ(), "GIT_COMMITTER_NAME=resourcewatch", "GIT_COMMITTER_EMAIL=ci-monitor@openshift.io")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, output)
+		}
+	}
+	write := func(path, content string) {
+		fullPath := filepath.Join(repositoryPath, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	operatorConfig := "cluster-scoped-resources/operator.openshift.io/kubeapiservers/cluster.yaml"
+	pod := "namespaces/openshift-etcd/core/pods/etcd-0.yaml"
+	start := time.Now().Add(-time.Second)
+	git("init", "-q")
+	write(operatorConfig, "apiVersion: operator.openshift.io/v1\nkind: KubeAPIServer\nmetadata:\n  name: cluster\n  resourceVersion: \"1\"\nspec:\n  logLevel: Normal\n")
+	git("add", operatorConfig)
+	git("commit", "-q", "--author=cluster-kube-apiserver-operator <ci-monitor@openshift.io>", "-m", "added kubeapiservers.operator.openshift.io/cluster")
+	write(pod, "apiVersion: v1\nkind: Pod\nmetadata:\n  name: etcd-0\n  namespace: openshift-etcd\n  resourceVersion: \"2\"\n")
+	git("add", pod)
+	git("commit", "-q", "--author=kubelet <ci-monitor@openshift.io>", "-m", "added pods/etcd-0 -n openshift-etcd")
+	write(operatorConfig, "apiVersion: operator.openshift.io/v1\nkind: KubeAPIServer\nmetadata:\n  name: cluster\n  resourceVersion: \"3\"\nspec:\n  logLevel: Debug\n")
+	git("add", operatorConfig)
+	git("commit", "-q", "--author=kubectl-edit AND cluster-kube-apiserver-operator <ci-monitor@openshift.io>", "-m", "modifed kubeapiservers.operator.openshift.io/cluster")
+	git("rm", "-q", pod)
+	git("commit", "-q", "--author=unobserved-deletion <ci-monitor@openshift.io>", "-m", "removed pods/etcd-0 -n openshift-etcd")
+
+	intervals, err := IntervalsFromHistory(repositoryPath, start, time.Time{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, intervals, 4) {
+		return
+	}
+
+	operatorConfigLocator := monitorapi.Locator{
+		Type: monitorapi.LocatorTypeResource,
+		Keys: map[monitorapi.LocatorKey]string{
+			monitorapi.LocatorGroupKey:    "operator.openshift.io",
+			monitorapi.LocatorResourceKey: "kubeapiservers",
+			monitorapi.LocatorNameKey:     "cluster",
+		},
+	}
+	podLocator := monitorapi.Locator{
+		Type: monitorapi.LocatorTypeResource,
+		Keys: map[monitorapi.LocatorKey]string{
+			monitorapi.LocatorResourceKey:  "pods",
+			monitorapi.LocatorNamespaceKey: "openshift-etcd",
+			monitorapi.LocatorNameKey:      "etcd-0",
+		},
+	}
+
+	added := intervals[0]
+	assert.Equal(t, monitorapi.SourceResourceWatch, added.Source)
+	assert.Equal(t, operatorConfigLocator, added.StructuredLocator)
+	assert.Equal(t, monitorapi.ResourceAddedReason, added.StructuredMessage.Reason)
+	assert.Equal(t, "added by cluster-kube-apiserver-operator", added.StructuredMessage.HumanMessage)
+	assert.True(t, added.Display, "configuration changes are displayed")
+	assert.Equal(t, added.From, added.To)
+
+	assert.Equal(t, podLocator, intervals[1].StructuredLocator)
+	assert.False(t, intervals[1].Display, "pod changes are not displayed")
+
+	modified := intervals[2]
+	assert.Equal(t, monitorapi.ResourceModifiedReason, modified.StructuredMessage.Reason)
+	assert.Equal(t, "~.spec.logLevel", modified.StructuredMessage.Annotations[monitorapi.AnnotationFieldDiff])
+	assert.Equal(t, "kubectl-edit,cluster-kube-apiserver-operator", modified.StructuredMessage.Annotations[monitorapi.AnnotationModifiers])
+
+	removed := intervals[3]
+	assert.Equal(t, monitorapi.ResourceDeletedReason, removed.StructuredMessage.Reason)
+	assert.Equal(t, "removed by unobserved-deletion", removed.StructuredMessage.HumanMessage)
+
+	// the pod changes are not even read when only the displayed groups are asked for.
+	intervals, err = IntervalsFromHistory(repositoryPath, start, time.Time{}, DisplayedGroups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, intervals, 2) {
+		assert.Equal(t, operatorConfigLocator, intervals[0].StructuredLocator)
+		assert.Equal(t, operatorConfigLocator, intervals[1].StructuredLocator)
+	}
+
+	intervals, err = IntervalsFromHistory(repositoryPath, time.Time{}, start, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, intervals)
+}
@@ -0,0 +1,60 @@
+package resourcewatchcollector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// RepositoryPathEnvVar names the git repository written by run-resourcewatch.  It is only readable
+// when resourcewatch runs next to the tests, in CI it runs in its own pod, use the
+// `openshift-tests resourcewatch intervals` command on the gathered repository instead.
+const RepositoryPathEnvVar = "RESOURCEWATCH_REPOSITORY_PATH"
+
+type resourceWatchCollector struct {
+}
+
+func NewResourceWatchCollector() monitortestframework.MonitorTest {
+	return &resourceWatchCollector{}
+}
+
+func (w *resourceWatchCollector) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (w *resourceWatchCollector) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	repositoryPath := os.Getenv(RepositoryPathEnvVar)
+	if len(repositoryPath) == 0 {
+		return nil, nil, nil
+	}
+	if _, err := os.Stat(filepath.Join(repositoryPath, ".git")); err != nil {
+		klog.Warningf("Skipping the resourcewatch history, %s is not a git repository: %v", repositoryPath, err)
+		return nil, nil, nil
+	}
+
+	intervals, err := IntervalsFromHistory(repositoryPath, beginning, end, DisplayedGroups)
+	return intervals, nil, err
+}
+
+func (*resourceWatchCollector) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+func (*resourceWatchCollector) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return nil, nil
+}
+
+func (*resourceWatchCollector) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (*resourceWatchCollector) Cleanup(ctx context.Context) error {
+	return nil
+}
@@ -15,12 +15,16 @@ import (
 )
 
 type timelineSerializer struct {
+	// junits are linked from the timeline viewer.
+	junits []*junitapi.JUnitTestCase
 }
 
 func NewTimelineSerializer() monitortestframework.MonitorTest {
 	return &timelineSerializer{}
 }
 
+var _ monitortestframework.JUnitObserver = &timelineSerializer{}
+
 func (w *timelineSerializer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
 	return nil
 }
@@ -37,7 +41,11 @@ func (*timelineSerializer) EvaluateTestsFromConstructedIntervals(ctx context.Con
 	return nil, nil
 }
 
-func (*timelineSerializer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+func (w *timelineSerializer) ObserveJUnits(junits []*junitapi.JUnitTestCase) {
+	w.junits = junits
+}
+
+func (w *timelineSerializer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
 	errs := []error{}
 	var err error
 
@@ -74,6 +82,11 @@ func (*timelineSerializer) WriteContentToStorage(ctx context.Context, storageDir
 	if err != nil {
 		errs = append(errs, err)
 	}
+	// the viewer does its own filtering, so it gets every interval.
+	err = WriteTimelineViewer(storageDir, timeSuffix, finalIntervals, w.junits, finalResourceState)
+	if err != nil {
+		errs = append(errs, err)
+	}
 
 	return utilerrors.NewAggregate(errs)
 }
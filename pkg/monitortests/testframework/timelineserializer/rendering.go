@@ -91,6 +91,10 @@ func BelongsInSpyglass(eventInterval monitorapi.Interval) bool {
 	if eventInterval.Source == monitorapi.SourcePodState {
 		return false
 	}
+	// only the configuration changes recorded by resourcewatch are displayed
+	if eventInterval.Source == monitorapi.SourceResourceWatch && !eventInterval.Display {
+		return false
+	}
 	// Pathologically repeating kube events:
 	if eventInterval.Source == monitorapi.SourceKubeEvent {
 		if eventInterval.StructuredMessage.Annotations[monitorapi.AnnotationPathological] != "true" {
@@ -0,0 +1,265 @@
+package timelineserializer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"github.com/openshift/origin/test/extended/testdata"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// timelineViewerMaxResourceBytes caps the size of the tracked resources embedded in the viewer.  Once it is reached,
+// intervals still link their resource by name, but its object is left out.
+const timelineViewerMaxResourceBytes = 20 * 1024 * 1024
+
+// timelineViewerData is embedded in e2echart/timeline-viewer.html.  Intervals reference junits and resources by index.
+type timelineViewerData struct {
+	Title     string                   `json:"title"`
+	Intervals []timelineViewerInterval `json:"intervals"`
+	JUnits    []timelineViewerJUnit    `json:"junits"`
+	Resources []timelineViewerResource `json:"resources"`
+}
+
+type timelineViewerInterval struct {
+	Level   string             `json:"level"`
+	Source  string             `json:"source"`
+	Display bool               `json:"display,omitempty"`
+	Locator monitorapi.Locator `json:"locator"`
+	Message monitorapi.Message `json:"message"`
+	From    time.Time          `json:"from"`
+	// To is omitted for intervals that never ended.
+	To *time.Time `json:"to,omitempty"`
+
+	JUnits   []int `json:"junits,omitempty"`
+	Resource *int  `json:"resource,omitempty"`
+}
+
+type timelineViewerJUnit struct {
+	Name   string `json:"name"`
+	Output string `json:"output"`
+	// Flake is set when the same test also passed.
+	Flake bool `json:"flake,omitempty"`
+}
+
+type timelineViewerResource struct {
+	Type      string                 `json:"type"`
+	Namespace string                 `json:"namespace"`
+	Name      string                 `json:"name"`
+	Object    map[string]interface{} `json:"object,omitempty"`
+	// Omitted is set when Object was left out because of timelineViewerMaxResourceBytes.
+	Omitted bool `json:"omitted,omitempty"`
+}
+
+// WriteTimelineViewer writes e2e-timeline-viewer<timeSuffix>.html, see RenderTimelineViewer.
+func WriteTimelineViewer(artifactDir, timeSuffix string, intervals monitorapi.Intervals, junits []*junitapi.JUnitTestCase, resources monitorapi.ResourcesMap) error {
+	viewerHTML, err := RenderTimelineViewer(fmt.Sprintf("Timeline%s", timeSuffix), intervals, junits, resources)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(artifactDir, fmt.Sprintf("e2e-timeline-viewer%s.html", timeSuffix)), viewerHTML, 0644)
+}
+
+// RenderTimelineViewer returns a single html page that needs nothing but a browser to filter, zoom, and search every
+// interval.  Each interval links to the junit failures that mention it and to the tracked resource it is about.
+func RenderTimelineViewer(title string, intervals monitorapi.Intervals, junits []*junitapi.JUnitTestCase, resources monitorapi.ResourcesMap) ([]byte, error) {
+	data, err := newTimelineViewerData(title, intervals, junits, resources)
+	if err != nil {
+		return nil, err
+	}
+	// json escapes <, >, and & so the data cannot close the script element it is embedded in.
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	viewerHTML := testdata.MustAsset("e2echart/timeline-viewer.html")
+	viewerHTML = bytes.ReplaceAll(viewerHTML, []byte("TIMELINE_VIEWER_TITLE_GOES_HERE"), []byte(html.EscapeString(title)))
+	viewerHTML = bytes.ReplaceAll(viewerHTML, []byte("TIMELINE_VIEWER_DATA_GOES_HERE"), dataJSON)
+	return viewerHTML, nil
+}
+
+func newTimelineViewerData(title string, intervals monitorapi.Intervals, junits []*junitapi.JUnitTestCase, resources monitorapi.ResourcesMap) (*timelineViewerData, error) {
+	ret := &timelineViewerData{
+		Title:     title,
+		Intervals: []timelineViewerInterval{},
+		JUnits:    []timelineViewerJUnit{},
+		Resources: []timelineViewerResource{},
+	}
+
+	passedTests := sets.NewString()
+	for _, junit := range junits {
+		if junit.FailureOutput == nil && junit.SkipMessage == nil {
+			passedTests.Insert(junit.Name)
+		}
+	}
+	junitsByName := map[string][]int{}
+	for _, junit := range junits {
+		if junit.FailureOutput == nil {
+			continue
+		}
+		output := junit.FailureOutput.Output
+		if len(junit.FailureOutput.Message) > 0 && !strings.Contains(output, junit.FailureOutput.Message) {
+			output = strings.TrimSpace(junit.FailureOutput.Message + "\n\n" + output)
+		}
+		junitsByName[junit.Name] = append(junitsByName[junit.Name], len(ret.JUnits))
+		ret.JUnits = append(ret.JUnits, timelineViewerJUnit{
+			Name:   junit.Name,
+			Output: output,
+			Flake:  passedTests.Has(junit.Name),
+		})
+	}
+
+	junitsByLocator := indexJUnitsByLocator(ret.JUnits, intervals)
+	junitsFor := func(interval monitorapi.Interval) []int {
+		if testName, ok := interval.StructuredLocator.Keys[monitorapi.LocatorE2ETestKey]; ok {
+			return junitsByName[testName]
+		}
+		return junitsByLocator[interval.StructuredLocator.OldLocator()]
+	}
+
+	resourceIndexes := map[string]int{}
+	resourceBytes := 0
+	resourceFor := func(interval monitorapi.Interval) (*int, error) {
+		resourceType, key, ok := resourceKeyFor(interval.StructuredLocator, resources)
+		if !ok {
+			return nil, nil
+		}
+		indexKey := fmt.Sprintf("%s/%s/%s/%s", resourceType, key.Namespace, key.Name, key.UID)
+		if index, ok := resourceIndexes[indexKey]; ok {
+			return &index, nil
+		}
+		object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(resources[resourceType][key])
+		if err != nil {
+			return nil, err
+		}
+		// managed fields make up most of a resource and are rarely what triage needs.
+		unstructured.RemoveNestedField(object, "metadata", "managedFields")
+		resource := timelineViewerResource{
+			Type:      resourceType,
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Object:    object,
+		}
+		objectJSON, err := json.Marshal(object)
+		if err != nil {
+			return nil, err
+		}
+		if resourceBytes+len(objectJSON) > timelineViewerMaxResourceBytes {
+			resource.Object, resource.Omitted = nil, true
+		} else {
+			resourceBytes += len(objectJSON)
+		}
+		index := len(ret.Resources)
+		resourceIndexes[indexKey] = index
+		ret.Resources = append(ret.Resources, resource)
+		return &index, nil
+	}
+
+	for _, interval := range intervals {
+		viewerInterval := timelineViewerInterval{
+			Level:   interval.Level.String(),
+			Source:  string(interval.Source),
+			Display: interval.Display,
+			Locator: interval.StructuredLocator,
+			Message: interval.StructuredMessage,
+			From:    interval.From,
+			JUnits:  junitsFor(interval),
+		}
+		if !interval.To.IsZero() {
+			to := interval.To
+			viewerInterval.To = &to
+		}
+		resource, err := resourceFor(interval)
+		if err != nil {
+			return nil, err
+		}
+		viewerInterval.Resource = resource
+		ret.Intervals = append(ret.Intervals, viewerInterval)
+	}
+	sort.SliceStable(ret.Intervals, func(i, j int) bool {
+		return ret.Intervals[i].From.Before(ret.Intervals[j].From)
+	})
+
+	return ret, nil
+}
+
+// indexJUnitsByLocator maps the locators of intervals to the junits whose output mentions them.  Locators are
+// written as space separated key/value words, so every run of up to as many words as the longest locator has keys is
+// indexed in a single pass over the output, rather than searching every output for each locator.
+func indexJUnitsByLocator(junits []timelineViewerJUnit, intervals monitorapi.Intervals) map[string][]int {
+	maxLocatorKeys := 0
+	for _, interval := range intervals {
+		if len(interval.StructuredLocator.Keys) > maxLocatorKeys {
+			maxLocatorKeys = len(interval.StructuredLocator.Keys)
+		}
+	}
+
+	ret := map[string][]int{}
+	for i, junit := range junits {
+		indexed := sets.NewString()
+		for _, line := range strings.Split(junit.Output, "\n") {
+			words := strings.Fields(line)
+			for start := range words {
+				for end := start; end < len(words) && end-start < maxLocatorKeys && strings.Contains(words[end], "/"); end++ {
+					locator := strings.Join(words[start:end+1], " ")
+					if indexed.Has(locator) {
+						continue
+					}
+					indexed.Insert(locator)
+					ret[locator] = append(ret[locator], i)
+				}
+			}
+		}
+	}
+	return ret
+}
+
+// resourceKeyFor finds the tracked resource a locator is about.  The locator must name the resource using the singular
+// of its resource type, for instance pod for pods, along with the namespace and uid when the resource has them.
+func resourceKeyFor(locator monitorapi.Locator, resources monitorapi.ResourcesMap) (string, monitorapi.InstanceKey, bool) {
+	for _, resourceType := range sets.StringKeySet(resources).List() {
+		name, ok := locator.Keys[monitorapi.LocatorKey(strings.TrimSuffix(resourceType, "s"))]
+		if !ok {
+			continue
+		}
+		key := monitorapi.InstanceKey{
+			Namespace: locator.Keys[monitorapi.LocatorNamespaceKey],
+			Name:      name,
+			UID:       locator.Keys[monitorapi.LocatorUIDKey],
+		}
+		if _, ok := resources[resourceType][key]; ok {
+			return resourceType, key, true
+		}
+		if len(key.UID) > 0 {
+			continue
+		}
+		// without a uid, use the instance when the name is not ambiguous.
+		var found *monitorapi.InstanceKey
+		for instanceKey := range resources[resourceType] {
+			if instanceKey.Namespace != key.Namespace || instanceKey.Name != key.Name {
+				continue
+			}
+			if found != nil {
+				found = nil
+				break
+			}
+			currKey := instanceKey
+			found = &currKey
+		}
+		if found != nil {
+			return resourceType, *found, true
+		}
+	}
+	return "", monitorapi.InstanceKey{}, false
+}
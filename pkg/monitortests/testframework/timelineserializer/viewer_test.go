@@ -0,0 +1,148 @@
+package timelineserializer
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTimelineViewerLinks(t *testing.T) {
+	start := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	podLocator := monitorapi.Locator{
+		Type: monitorapi.LocatorTypePod,
+		Keys: map[monitorapi.LocatorKey]string{
+			monitorapi.LocatorNamespaceKey: "openshift-etcd",
+			monitorapi.LocatorPodKey:       "etcd-master-0",
+			monitorapi.LocatorUIDKey:       "uid-1",
+		},
+	}
+	testLocator := monitorapi.Locator{
+		Type: monitorapi.LocatorTypeE2ETest,
+		Keys: map[monitorapi.LocatorKey]string{monitorapi.LocatorE2ETestKey: "[sig-etcd] should be healthy"},
+	}
+	intervals := monitorapi.Intervals{
+		{
+			Condition: monitorapi.Condition{Level: monitorapi.Info, StructuredLocator: testLocator},
+			Source:    monitorapi.SourceE2ETest,
+			From:      start,
+			To:        start.Add(time.Minute),
+		},
+		{
+			Condition: monitorapi.Condition{Level: monitorapi.Warning, StructuredLocator: podLocator},
+			Source:    monitorapi.SourcePodState,
+			From:      start.Add(-time.Minute),
+		},
+	}
+	junits := []*junitapi.JUnitTestCase{
+		{Name: "[sig-etcd] should be healthy", FailureOutput: &junitapi.FailureOutput{Output: "timed out"}},
+		{Name: "[sig-etcd] should be healthy"},
+		{Name: "events should not repeat pathologically", FailureOutput: &junitapi.FailureOutput{Output: "1 events happened too frequently\n\n" + podLocator.OldLocator() + " - reason/BackOff"}},
+		{Name: "passing test"},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace:     "openshift-etcd",
+		Name:          "etcd-master-0",
+		UID:           "uid-1",
+		ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubelet"}},
+	}}
+	resources := monitorapi.ResourcesMap{
+		"pods": monitorapi.InstanceMap{
+			monitorapi.InstanceKey{Namespace: "openshift-etcd", Name: "etcd-master-0", UID: "uid-1"}: pod,
+		},
+	}
+
+	data, err := newTimelineViewerData("test", intervals, junits, resources)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(data.JUnits) != 2 {
+		t.Fatalf("expected only the 2 failures, got %#v", data.JUnits)
+	}
+	if !data.JUnits[0].Flake || data.JUnits[1].Flake {
+		t.Errorf("expected only the first failure to be a flake: %#v", data.JUnits)
+	}
+
+	// sorted by from, so the pod interval is first.
+	podInterval, testInterval := data.Intervals[0], data.Intervals[1]
+	if podInterval.To != nil {
+		t.Errorf("expected the pod interval to have no end, got %v", podInterval.To)
+	}
+	if !reflect.DeepEqual(podInterval.JUnits, []int{1}) {
+		t.Errorf("expected the pod interval to link the pathological failure, got %v", podInterval.JUnits)
+	}
+	if !reflect.DeepEqual(testInterval.JUnits, []int{0}) {
+		t.Errorf("expected the test interval to link its own failure, got %v", testInterval.JUnits)
+	}
+	if testInterval.Resource != nil {
+		t.Errorf("expected no resource for the test interval, got %v", *testInterval.Resource)
+	}
+	if podInterval.Resource == nil || *podInterval.Resource != 0 {
+		t.Fatalf("expected the pod interval to link the pod, got %v", podInterval.Resource)
+	}
+	metadata := data.Resources[0].Object["metadata"].(map[string]interface{})
+	if _, ok := metadata["managedFields"]; ok {
+		t.Errorf("expected managedFields to be removed")
+	}
+}
+
+func TestIndexJUnitsByLocator(t *testing.T) {
+	podLocator := func(name string) monitorapi.Locator {
+		return monitorapi.Locator{Keys: map[monitorapi.LocatorKey]string{monitorapi.LocatorNamespaceKey: "ns", monitorapi.LocatorPodKey: name}}
+	}
+	intervals := monitorapi.Intervals{
+		{Condition: monitorapi.Condition{StructuredLocator: podLocator("etcd")}},
+		{Condition: monitorapi.Condition{StructuredLocator: podLocator("etcd-guard")}},
+	}
+	junits := []timelineViewerJUnit{
+		{Output: "2 events happened too frequently\n\n" + podLocator("etcd").OldLocator() + " - reason/BackOff\n" + podLocator("etcd").OldLocator() + " - reason/Unhealthy"},
+		{Output: "pod " + podLocator("etcd-guard").OldLocator() + " was not ready"},
+	}
+
+	index := indexJUnitsByLocator(junits, intervals)
+	if got := index[podLocator("etcd").OldLocator()]; !reflect.DeepEqual(got, []int{0}) {
+		t.Errorf("expected only the first junit to mention etcd once, got %v", got)
+	}
+	if got := index[podLocator("etcd-guard").OldLocator()]; !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("expected only the second junit to mention etcd-guard, got %v", got)
+	}
+}
+
+func TestResourceKeyForWithoutUID(t *testing.T) {
+	resources := monitorapi.ResourcesMap{
+		"pods": monitorapi.InstanceMap{
+			monitorapi.InstanceKey{Namespace: "ns", Name: "unique", UID: "uid-1"}:    &corev1.Pod{},
+			monitorapi.InstanceKey{Namespace: "ns", Name: "recreated", UID: "uid-2"}: &corev1.Pod{},
+			monitorapi.InstanceKey{Namespace: "ns", Name: "recreated", UID: "uid-3"}: &corev1.Pod{},
+		},
+	}
+	locatorFor := func(name string) monitorapi.Locator {
+		return monitorapi.Locator{Keys: map[monitorapi.LocatorKey]string{monitorapi.LocatorNamespaceKey: "ns", monitorapi.LocatorPodKey: name}}
+	}
+
+	if _, key, ok := resourceKeyFor(locatorFor("unique"), resources); !ok || key.UID != "uid-1" {
+		t.Errorf("expected the unique pod to be found, got %v %v", key, ok)
+	}
+	if _, key, ok := resourceKeyFor(locatorFor("recreated"), resources); ok {
+		t.Errorf("expected an ambiguous pod not to be found, got %v", key)
+	}
+}
+
+func TestRenderTimelineViewer(t *testing.T) {
+	html, err := RenderTimelineViewer("<run>", nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(html, []byte("GOES_HERE")) {
+		t.Errorf("expected every placeholder to be replaced")
+	}
+	if !bytes.Contains(html, []byte("<title>&lt;run&gt;</title>")) {
+		t.Errorf("expected an escaped title")
+	}
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortests/testframework/resourcewatchcollector"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+// IntervalsFlags converts the history recorded by run-resourcewatch into monitor intervals.
+type IntervalsFlags struct {
+	RepositoryPath string
+	Since          string
+	Until          string
+	Groups         []string
+	OutputFile     string
+
+	genericclioptions.IOStreams
+}
+
+func NewIntervalsFlags(streams genericclioptions.IOStreams) *IntervalsFlags {
+	return &IntervalsFlags{
+		RepositoryPath: NewQueryFlags(streams).RepositoryPath,
+		Groups:         resourcewatchcollector.DisplayedGroups,
+		IOStreams:      streams,
+	}
+}
+
+func NewIntervalsCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	f := NewIntervalsFlags(streams)
+	cmd := &cobra.Command{
+		Use:   "intervals",
+		Short: "Convert the changes recorded by run-resourcewatch into monitor intervals",
+		Long: templates.LongDesc(`
+			Writes an interval for each add, modify, and delete committed to the git repository
+			written by run-resourcewatch, in the format read by the timeline command, so the
+			configuration changes can be plotted next to disruption and alerts.  Only the
+			configuration groups are converted unless --group says otherwise.
+		`),
+		Example: templates.Examples(`
+			# Plot the configuration changes of a job run with its other intervals
+			openshift-tests resourcewatch intervals --repository resource-watch-repo --output-file resourcewatch-intervals.json
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return f.Run()
+		},
+	}
+
+	f.BindOptions(cmd.Flags())
+
+	return cmd
+}
+
+func (f *IntervalsFlags) BindOptions(flags *pflag.FlagSet) {
+	flags.StringVar(&f.RepositoryPath, "repository", f.RepositoryPath, "the git repository written by run-resourcewatch")
+	flags.StringVar(&f.Since, "since", f.Since, "only convert the changes at or after this time, in RFC3339")
+	flags.StringVar(&f.Until, "until", f.Until, "only convert the changes at or before this time, in RFC3339")
+	flags.StringSliceVar(&f.Groups, "group", f.Groups, "only convert the changes to the resources of these API groups, every group if empty")
+	flags.StringVar(&f.OutputFile, "output-file", f.OutputFile, "the file to write the intervals to, the standard output if empty")
+}
+
+func (f *IntervalsFlags) Run() error {
+	var since, until time.Time
+	var err error
+	if len(f.Since) > 0 {
+		if since, err = time.Parse(time.RFC3339, f.Since); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if len(f.Until) > 0 {
+		if until, err = time.Parse(time.RFC3339, f.Until); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+
+	intervals, err := resourcewatchcollector.IntervalsFromHistory(f.RepositoryPath, since, until, f.Groups)
+	if err != nil {
+		return err
+	}
+	if len(f.OutputFile) > 0 {
+		return monitorserialization.EventsToFile(f.OutputFile, intervals)
+	}
+	content, err := monitorserialization.IntervalsToJSON(intervals)
+	if err != nil {
+		return err
+	}
+	_, err = f.Out.Write(content)
+	return err
+}
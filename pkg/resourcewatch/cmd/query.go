@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/openshift/origin/pkg/resourcewatch/storage"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+// QueryFlags answers questions about the history recorded by run-resourcewatch.
+type QueryFlags struct {
+	RepositoryPath string
+	Since          string
+	Until          string
+	Namespace      string
+	FieldPath      string
+	ObjectsOnly    bool
+	Output         string
+
+	genericclioptions.IOStreams
+}
+
+func NewQueryFlags(streams genericclioptions.IOStreams) *QueryFlags {
+	repositoryPath := "/repository"
+	if repositoryPathEnv := os.Getenv("REPOSITORY_PATH"); len(repositoryPathEnv) > 0 {
+		repositoryPath = repositoryPathEnv
+	}
+	return &QueryFlags{
+		RepositoryPath: repositoryPath,
+		IOStreams:      streams,
+	}
+}
+
+func NewQueryCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	f := NewQueryFlags(streams)
+	cmd := &cobra.Command{
+		Use:   "query [RESOURCE[/NAME]]",
+		Short: "Query the changes recorded by run-resourcewatch",
+		Long: templates.LongDesc(`
+			Lists the changes committed to the git repository written by run-resourcewatch,
+			with who we believe made them.  By default /repository will be used, specify
+			REPOSITORY_PATH env var or --repository to override.
+		`),
+		Example: templates.Examples(`
+			# Every change to the kube-apiserver clusteroperator between two times
+			openshift-tests resourcewatch query clusteroperators/kube-apiserver --since 2023-01-01T10:00:00Z --until 2023-01-01T11:00:00Z
+
+			# Who modified the log level of the kube-apiserver operator
+			openshift-tests resourcewatch query kubeapiservers.operator.openshift.io/cluster --field .spec.logLevel
+
+			# All objects touched during an interval
+			openshift-tests resourcewatch query --since 2023-01-01T10:00:00Z --until 2023-01-01T10:05:00Z --objects
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query, err := f.ToQuery(args)
+			if err != nil {
+				return err
+			}
+			return f.Run(query)
+		},
+	}
+
+	f.BindOptions(cmd.Flags())
+
+	return cmd
+}
+
+func (f *QueryFlags) BindOptions(flags *pflag.FlagSet) {
+	flags.StringVar(&f.RepositoryPath, "repository", f.RepositoryPath, "the git repository written by run-resourcewatch")
+	flags.StringVar(&f.Since, "since", f.Since, "only list the changes at or after this time, in RFC3339")
+	flags.StringVar(&f.Until, "until", f.Until, "only list the changes at or before this time, in RFC3339")
+	flags.StringVarP(&f.Namespace, "namespace", "n", f.Namespace, "only list the changes to objects in this namespace")
+	flags.StringVar(&f.FieldPath, "field", f.FieldPath, "only list the changes to this field, like .spec.logLevel")
+	flags.BoolVar(&f.ObjectsOnly, "objects", f.ObjectsOnly, "list the objects that changed instead of the changes")
+	flags.StringVarP(&f.Output, "output", "o", f.Output, "output format, empty for a table or json")
+}
+
+func (f *QueryFlags) ToQuery(args []string) (storage.HistoryQuery, error) {
+	query := storage.HistoryQuery{
+		Namespace: f.Namespace,
+		FieldPath: f.FieldPath,
+	}
+	if len(args) > 0 {
+		resource, name, _ := strings.Cut(args[0], "/")
+		if len(resource) == 0 {
+			return query, fmt.Errorf("a resource is required, like clusteroperators/kube-apiserver")
+		}
+		query.Resource = resource
+		query.Name = name
+	}
+
+	var err error
+	if len(f.Since) > 0 {
+		if query.Since, err = time.Parse(time.RFC3339, f.Since); err != nil {
+			return query, fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if len(f.Until) > 0 {
+		if query.Until, err = time.Parse(time.RFC3339, f.Until); err != nil {
+			return query, fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	if !query.Since.IsZero() && !query.Until.IsZero() && query.Until.Before(query.Since) {
+		return query, fmt.Errorf("--until must not be before --since")
+	}
+
+	switch f.Output {
+	case "", "json":
+	default:
+		return query, fmt.Errorf("unsupported output format %q", f.Output)
+	}
+	return query, nil
+}
+
+func (f *QueryFlags) Run(query storage.HistoryQuery) error {
+	changes, err := storage.History(f.RepositoryPath, query)
+	if err != nil {
+		return err
+	}
+
+	if f.ObjectsOnly {
+		objects := storage.TouchedObjects(changes)
+		if f.Output == "json" {
+			return f.writeJSON(objects)
+		}
+		for _, object := range objects {
+			fmt.Fprintln(f.Out, object.String())
+		}
+		return nil
+	}
+
+	if f.Output == "json" {
+		return f.writeJSON(changes)
+	}
+	w := tabwriter.NewWriter(f.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tOPERATION\tOBJECT\tMODIFIERS\tCOMMIT")
+	for _, change := range changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			change.Time.UTC().Format(time.RFC3339), change.Operation, change.Object.String(), strings.Join(change.Modifiers, ","), change.Commit[:8])
+	}
+	return w.Flush()
+}
+
+func (f *QueryFlags) writeJSON(obj interface{}) error {
+	encoder := json.NewEncoder(f.Out)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(obj)
+}
@@ -3,9 +3,23 @@ package cmd
 import (
 	"github.com/openshift/origin/pkg/resourcewatch/operator"
 	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/kubectl/pkg/util/templates"
 )
 
+func NewResourceWatchCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "resourcewatch",
+		Long:          "Collecting place for commands used to read the resource history recorded by run-resourcewatch.",
+		SilenceErrors: true,
+	}
+	cmd.AddCommand(
+		NewQueryCommand(streams),
+		NewIntervalsCommand(streams),
+	)
+	return cmd
+}
+
 func NewRunResourceWatchCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "run-resourcewatch",
@@ -16,6 +30,16 @@ func NewRunResourceWatchCommand() *cobra.Command {
 			see precisely how a resource changed over time.
 			By default /repository will be used, specify REPOSITORY_PATH env var to
 			override.
+			The resources watched by default can be extended with a configuration file
+			named by the RESOURCEWATCH_CONFIG env var, for instance to record the CRs of
+			an operator and redact the data of its Secrets:
+			  resources:
+			  - group: "*.example.com"
+			  - group: ""
+			    version: v1
+			    resource: secrets
+			    labelSelector: app=example
+			    redactFields: [.data, .stringData]
 			Sample invocation against an external cluster:
 			  $ REPOSITORY_PATH="/tmp/resource-watch-repo" openshift-tests run-resourcewatch --kubeconfig /path/to/kubeconfig --namespace default
 		`),
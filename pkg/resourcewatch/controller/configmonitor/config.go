@@ -0,0 +1,174 @@
+package configmonitor
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+// WatchConfigEnvVar names the file with the resources to watch in addition to, or instead of, the defaults.
+const WatchConfigEnvVar = "RESOURCEWATCH_CONFIG"
+
+// WatchConfiguration selects the resources recorded in the git repository.
+type WatchConfiguration struct {
+	// ReplaceDefaults drops the resources watched by default, only the listed ones are watched.
+	ReplaceDefaults bool `json:"replaceDefaults,omitempty"`
+
+	// Resources are the rules selecting the watched resources, the first rule matching a resource applies.
+	Resources []WatchRule `json:"resources"`
+}
+
+// WatchRule selects one resource, or with wildcards every resource of the matching API groups, including the
+// resources of the CRDs installed while we are watching.
+type WatchRule struct {
+	// Group is the API group, empty for the core group.  It may be a wildcard like *.example.com.
+	Group string `json:"group"`
+
+	// Version defaults to the preferred version of the group, or to the storage version of a CRD.
+	Version string `json:"version,omitempty"`
+
+	// Resource is the plural resource name.  Empty or * selects every resource of the group.
+	Resource string `json:"resource,omitempty"`
+
+	// LabelSelector, if set, only records the objects with matching labels.  An object whose labels stop matching is
+	// removed from the repository, the removal is authored by no-longer-selected so it is not mistaken for a deletion.
+	LabelSelector string `json:"labelSelector,omitempty"`
+
+	// RedactFields, like .data, are replaced before the objects are committed.  The keys of maps are kept.  The .data
+	// and .stringData of core secrets are always redacted.
+	RedactFields []string `json:"redactFields,omitempty"`
+}
+
+// secretFields are redacted from the core secrets whatever the rule says, the history ends up in published artifacts.
+var secretFields = []string{".data", ".stringData"}
+
+// NewWatchConfiguration returns the configuration watching exactly the given resources.
+func NewWatchConfiguration(resources ...schema.GroupVersionResource) *WatchConfiguration {
+	config := &WatchConfiguration{}
+	for _, resource := range resources {
+		config.Resources = append(config.Resources, WatchRule{Group: resource.Group, Version: resource.Version, Resource: resource.Resource})
+	}
+	return config
+}
+
+// LoadWatchConfiguration reads the configuration, in yaml or json, from the given file.
+func LoadWatchConfiguration(path string) (*WatchConfiguration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the resourcewatch configuration: %w", err)
+	}
+	config := &WatchConfiguration{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse the resourcewatch configuration %s: %w", path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid resourcewatch configuration %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// WatchConfigurationFromEnv returns the defaults merged with the configuration named by WatchConfigEnvVar, if any.
+func WatchConfigurationFromEnv(defaults *WatchConfiguration) (*WatchConfiguration, error) {
+	path := os.Getenv(WatchConfigEnvVar)
+	if len(path) == 0 {
+		return defaults, nil
+	}
+	config, err := LoadWatchConfiguration(path)
+	if err != nil {
+		return nil, err
+	}
+	if config.ReplaceDefaults {
+		return config, nil
+	}
+	// the listed rules come first so that they can redact or filter the default resources.
+	return &WatchConfiguration{
+		Resources: append(append([]WatchRule{}, config.Resources...), defaults.Resources...),
+	}, nil
+}
+
+func (c *WatchConfiguration) Validate() error {
+	if len(c.Resources) == 0 {
+		return fmt.Errorf("at least one resource is required")
+	}
+	for i, rule := range c.Resources {
+		if _, err := path.Match(rule.Group, ""); err != nil {
+			return fmt.Errorf("resources[%d]: invalid group %q: %w", i, rule.Group, err)
+		}
+		if _, err := path.Match(rule.Resource, ""); err != nil {
+			return fmt.Errorf("resources[%d]: invalid resource %q: %w", i, rule.Resource, err)
+		}
+		if strings.Contains(rule.Resource, "/") {
+			return fmt.Errorf("resources[%d]: subresources like %q cannot be watched", i, rule.Resource)
+		}
+		if _, err := labels.Parse(rule.LabelSelector); err != nil {
+			return fmt.Errorf("resources[%d]: invalid labelSelector: %w", i, err)
+		}
+		for _, fieldPath := range rule.RedactFields {
+			if !strings.HasPrefix(fieldPath, ".") || len(fieldPath) == 1 || strings.Contains(fieldPath, "..") {
+				return fmt.Errorf("resources[%d]: invalid redactFields %q, expected a path like .data", i, fieldPath)
+			}
+			if fieldPath == ".metadata" || strings.HasPrefix(fieldPath, ".metadata.name") || strings.HasPrefix(fieldPath, ".metadata.namespace") ||
+				strings.HasPrefix(fieldPath, ".metadata.resourceVersion") || strings.HasPrefix(fieldPath, ".metadata.managedFields") {
+				return fmt.Errorf("resources[%d]: %q is needed to record the history and cannot be redacted", i, fieldPath)
+			}
+		}
+	}
+	return nil
+}
+
+// explicit returns true if the rule names a single resource, which can be watched without discovery.
+func (r WatchRule) explicit() bool {
+	return len(r.Version) > 0 && len(r.Resource) > 0 && !hasWildcard(r.Group) && !hasWildcard(r.Resource)
+}
+
+// matches returns true if the rule selects the resource.  preferredVersion is the version used when the
+// rule does not have one.
+func (r WatchRule) matches(gvr schema.GroupVersionResource, preferredVersion string) bool {
+	if matched, _ := path.Match(r.Group, gvr.Group); !matched {
+		return false
+	}
+	if len(r.Resource) > 0 {
+		if matched, _ := path.Match(r.Resource, gvr.Resource); !matched {
+			return false
+		}
+	}
+	if len(r.Version) > 0 {
+		return r.Version == gvr.Version
+	}
+	return gvr.Version == preferredVersion
+}
+
+// redactFields returns the fields of the resource that are redacted, the rule's and the secretFields of core secrets.
+func (r WatchRule) redactFields(gr schema.GroupResource) []string {
+	fieldPaths := append([]string{}, r.RedactFields...)
+	if gr != (schema.GroupResource{Resource: "secrets"}) {
+		return fieldPaths
+	}
+	redacted := sets.NewString(fieldPaths...)
+	for _, fieldPath := range secretFields {
+		if !redacted.Has(fieldPath) {
+			fieldPaths = append(fieldPaths, fieldPath)
+		}
+	}
+	return fieldPaths
+}
+
+// matchingRule returns the first rule selecting the resource.
+func (c *WatchConfiguration) matchingRule(gvr schema.GroupVersionResource, preferredVersion string) (WatchRule, bool) {
+	for _, rule := range c.Resources {
+		if rule.matches(gvr, preferredVersion) {
+			return rule, true
+		}
+	}
+	return WatchRule{}, false
+}
+
+func hasWildcard(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
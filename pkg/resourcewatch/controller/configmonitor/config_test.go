@@ -0,0 +1,255 @@
+package configmonitor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestLoadWatchConfiguration(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "wildcards, selectors and redaction",
+			config: `
+resources:
+- group: "*.example.com"
+- group: ""
+  version: v1
+  resource: secrets
+  labelSelector: app=example
+  redactFields: [.data, .stringData]
+`,
+		},
+		{
+			name:    "no resources",
+			config:  `resources: []`,
+			wantErr: "at least one resource is required",
+		},
+		{
+			name: "invalid group pattern",
+			config: `
+resources:
+- group: "[example.com"
+`,
+			wantErr: `invalid group "[example.com"`,
+		},
+		{
+			name: "subresource",
+			config: `
+resources:
+- group: ""
+  resource: pods/log
+`,
+			wantErr: "subresources",
+		},
+		{
+			name: "invalid label selector",
+			config: `
+resources:
+- group: ""
+  resource: pods
+  labelSelector: "app in (a"
+`,
+			wantErr: "invalid labelSelector",
+		},
+		{
+			name: "invalid redacted field",
+			config: `
+resources:
+- group: ""
+  resource: secrets
+  redactFields: [data]
+`,
+			wantErr: "expected a path like .data",
+		},
+		{
+			name: "redacted resourceVersion",
+			config: `
+resources:
+- group: ""
+  resource: secrets
+  redactFields: [.metadata.resourceVersion]
+`,
+			wantErr: "cannot be redacted",
+		},
+		{
+			name: "unknown field",
+			config: `
+resources:
+- group: ""
+  kind: Secret
+`,
+			wantErr: "unknown field",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(test.config), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadWatchConfiguration(path)
+			switch {
+			case len(test.wantErr) == 0 && err != nil:
+				t.Errorf("expected no error, but got: %v", err)
+			case len(test.wantErr) > 0 && (err == nil || !strings.Contains(err.Error(), test.wantErr)):
+				t.Errorf("expected an error with %q, but got: %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWatchConfigurationFromEnv(t *testing.T) {
+	pods := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	defaults := NewWatchConfiguration(pods)
+
+	config, err := WatchConfigurationFromEnv(defaults)
+	if err != nil || config != defaults {
+		t.Fatalf("expected the defaults, but got %v: %v", config, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("resources:\n- group: \"\"\n  resource: pods\n  labelSelector: app=example\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(WatchConfigEnvVar, path)
+	config, err = WatchConfigurationFromEnv(defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Resources) != 2 {
+		t.Fatalf("expected the listed resources and the defaults, but got %v", config.Resources)
+	}
+	// the listed rule comes first, so it filters the default pods
+	if rule, ok := config.matchingRule(pods, pods.Version); !ok || rule.LabelSelector != "app=example" {
+		t.Errorf("expected the listed rule to apply to pods, but got %v", rule)
+	}
+
+	if err := os.WriteFile(path, []byte("replaceDefaults: true\nresources:\n- group: example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err = WatchConfigurationFromEnv(defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Resources) != 1 || config.Resources[0].Group != "example.com" {
+		t.Errorf("expected the defaults to be replaced, but got %v", config.Resources)
+	}
+}
+
+func TestWatchRuleMatches(t *testing.T) {
+	widgets := schema.GroupVersionResource{Group: "apps.example.com", Version: "v1", Resource: "widgets"}
+	tests := []struct {
+		name             string
+		rule             WatchRule
+		gvr              schema.GroupVersionResource
+		preferredVersion string
+		explicit         bool
+		want             bool
+	}{
+		{name: "explicit", rule: WatchRule{Group: "apps.example.com", Version: "v1", Resource: "widgets"}, gvr: widgets, preferredVersion: "v2", explicit: true, want: true},
+		{name: "other version", rule: WatchRule{Group: "apps.example.com", Version: "v2", Resource: "widgets"}, gvr: widgets, explicit: true},
+		{name: "preferred version", rule: WatchRule{Group: "apps.example.com", Resource: "widgets"}, gvr: widgets, preferredVersion: "v1", want: true},
+		{name: "not the preferred version", rule: WatchRule{Group: "apps.example.com", Resource: "widgets"}, gvr: widgets, preferredVersion: "v2"},
+		{name: "group wildcard", rule: WatchRule{Group: "*.example.com"}, gvr: widgets, preferredVersion: "v1", want: true},
+		{name: "resource wildcard", rule: WatchRule{Group: "apps.example.com", Resource: "*"}, gvr: widgets, preferredVersion: "v1", want: true},
+		{name: "other group", rule: WatchRule{Group: "*.example.org"}, gvr: widgets, preferredVersion: "v1"},
+		{name: "core group", rule: WatchRule{Group: "", Resource: "pods"}, gvr: schema.GroupVersionResource{Version: "v1", Resource: "pods"}, preferredVersion: "v1", want: true},
+		{name: "core group is not a wildcard", rule: WatchRule{Group: ""}, gvr: widgets, preferredVersion: "v1"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.rule.explicit(); got != test.explicit {
+				t.Errorf("expected explicit %v, but got %v", test.explicit, got)
+			}
+			if got := test.rule.matches(test.gvr, test.preferredVersion); got != test.want {
+				t.Errorf("expected %v, but got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestDiscoveredResources(t *testing.T) {
+	groups := []*metav1.APIGroup{
+		{Name: "", PreferredVersion: metav1.GroupVersionForDiscovery{Version: "v1"}},
+		{Name: "apps.example.com", PreferredVersion: metav1.GroupVersionForDiscovery{Version: "v2"}},
+	}
+	resources := []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "pods", Verbs: []string{"list", "watch", "get"}},
+				{Name: "pods/log", Verbs: []string{"get"}},
+				{Name: "bindings", Verbs: []string{"create"}},
+			},
+		},
+		{
+			GroupVersion: "apps.example.com/v1",
+			APIResources: []metav1.APIResource{{Name: "widgets", Verbs: []string{"list", "watch"}}},
+		},
+		{
+			GroupVersion: "apps.example.com/v2",
+			APIResources: []metav1.APIResource{{Name: "widgets", Verbs: []string{"list", "watch"}}},
+		},
+	}
+	want := []candidateResource{
+		{gvr: schema.GroupVersionResource{Version: "v1", Resource: "pods"}, preferredVersion: "v1"},
+		{gvr: schema.GroupVersionResource{Group: "apps.example.com", Version: "v1", Resource: "widgets"}, preferredVersion: "v2"},
+		{gvr: schema.GroupVersionResource{Group: "apps.example.com", Version: "v2", Resource: "widgets"}, preferredVersion: "v2"},
+	}
+	if got := discoveredResources(groups, resources); !reflect.DeepEqual(want, got) {
+		t.Errorf("expected %v, but got %v", want, got)
+	}
+}
+
+func TestCRDResources(t *testing.T) {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "apps.example.com",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets"},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: false},
+				{Name: "v1beta1", Served: true},
+				{Name: "v1", Served: true, Storage: true},
+			},
+		},
+	}
+	want := []candidateResource{
+		{gvr: schema.GroupVersionResource{Group: "apps.example.com", Version: "v1beta1", Resource: "widgets"}, preferredVersion: "v1"},
+		{gvr: schema.GroupVersionResource{Group: "apps.example.com", Version: "v1", Resource: "widgets"}, preferredVersion: "v1"},
+	}
+	if got := crdResources(crd); !reflect.DeepEqual(want, got) {
+		t.Errorf("expected %v, but got %v", want, got)
+	}
+}
+
+func TestWatchRuleRedactFields(t *testing.T) {
+	tests := []struct {
+		name string
+		rule WatchRule
+		gr   schema.GroupResource
+		want []string
+	}{
+		{name: "secrets without redactFields", rule: WatchRule{Group: "", Resource: "secrets"}, gr: schema.GroupResource{Resource: "secrets"}, want: []string{".data", ".stringData"}},
+		{name: "secrets with redactFields", rule: WatchRule{Group: "", RedactFields: []string{".data", ".metadata.annotations"}}, gr: schema.GroupResource{Resource: "secrets"}, want: []string{".data", ".metadata.annotations", ".stringData"}},
+		{name: "configmaps", rule: WatchRule{Group: "", Resource: "configmaps"}, gr: schema.GroupResource{Resource: "configmaps"}, want: []string{}},
+		{name: "secrets of another group", rule: WatchRule{Group: "example.com"}, gr: schema.GroupResource{Group: "example.com", Resource: "secrets"}, want: []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.rule.redactFields(test.gr); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %v, but got %v", test.want, got)
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package configmonitor
 
 import (
+	"context"
+
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/klog/v2"
 
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/cache"
 )
@@ -14,6 +17,10 @@ type resourceObserverEventHandler interface {
 	OnDelete(gvr schema.GroupVersionResource, obj interface{})
 }
 
+type unobservedDeletionReconciler interface {
+	ReconcileDeletions(gvr schema.GroupVersionResource, listLiveObjects func() []*unstructured.Unstructured) error
+}
+
 // this is an unusual controller. it really wants an pure watch stream, but that change is too big to reason about at
 // the moment.  For the moment we'll allow it have synchronous handling of informer notifications.  This has severe consequences
 // for cache correctness and latency, but it keeps me from having rip out more logic than I want to.
@@ -27,21 +34,55 @@ func WireResourceInformersToGitRepo(
 	for i := range resourcesToWatch {
 		resourceToWatch := resourcesToWatch[i]
 		// we got mapping, lets run the dynamicInformer for the config and install GIT storageHandler event handlers
-		dynamicInformer := dynamicInformerFactory.ForResource(resourceToWatch).Informer()
-
-		dynamicInformer.AddEventHandler(
-			cache.ResourceEventHandlerFuncs{
-				AddFunc: func(obj interface{}) {
-					gitStorage.OnAdd(resourceToWatch, obj)
-				},
-				UpdateFunc: func(oldObj, newObj interface{}) {
-					gitStorage.OnUpdate(resourceToWatch, oldObj, newObj)
-				},
-				DeleteFunc: func(obj interface{}) {
-					gitStorage.OnDelete(resourceToWatch, obj)
-				},
+		wireResourceInformerToGitRepo(dynamicInformerFactory.ForResource(resourceToWatch).Informer(), gitStorage, resourceToWatch)
+	}
+}
+
+func wireResourceInformerToGitRepo(
+	dynamicInformer cache.SharedIndexInformer,
+	gitStorage resourceObserverEventHandler,
+	resourceToWatch schema.GroupVersionResource,
+) {
+	dynamicInformer.AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				gitStorage.OnAdd(resourceToWatch, obj)
 			},
-		)
-		klog.Infof("Added event handler for resource %s", resourceToWatch.String())
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				gitStorage.OnUpdate(resourceToWatch, oldObj, newObj)
+			},
+			DeleteFunc: func(obj interface{}) {
+				gitStorage.OnDelete(resourceToWatch, obj)
+			},
+		},
+	)
+	klog.Infof("Added event handler for resource %s", resourceToWatch.String())
+}
+
+// reconcileUnobservedDeletions waits for the informer to sync and then removes the objects that were deleted
+// while we were not watching, for instance while resourcewatch was restarting.
+func reconcileUnobservedDeletions(
+	ctx context.Context,
+	dynamicInformer cache.SharedIndexInformer,
+	gitStorage unobservedDeletionReconciler,
+	resourceToWatch schema.GroupVersionResource,
+) {
+	if !cache.WaitForCacheSync(ctx.Done(), dynamicInformer.HasSynced) {
+		return
+	}
+	// the store is listed by the storage once it holds its lock, so no object can be committed in between.
+	listLiveObjects := func() []*unstructured.Unstructured {
+		liveObjects := []*unstructured.Unstructured{}
+		for _, obj := range dynamicInformer.GetStore().List() {
+			if objUnstructured, ok := obj.(*unstructured.Unstructured); ok {
+				liveObjects = append(liveObjects, objUnstructured)
+			}
+		}
+		return liveObjects
+	}
+	if err := gitStorage.ReconcileDeletions(resourceToWatch, listLiveObjects); err != nil {
+		klog.Errorf("Failed to reconcile unobserved deletions of %s: %v", resourceToWatch.String(), err)
+		return
 	}
+	klog.Infof("Reconciled unobserved deletions of %s", resourceToWatch.String())
 }
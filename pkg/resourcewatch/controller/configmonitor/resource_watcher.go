@@ -0,0 +1,195 @@
+package configmonitor
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+type gitStorage interface {
+	resourceObserverEventHandler
+	unobservedDeletionReconciler
+	RedactFields(gr schema.GroupResource, fieldPaths []string)
+	SelectLabels(gr schema.GroupResource, selector labels.Selector)
+}
+
+var crdResource = apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions")
+
+// ResourceWatcher records the resources selected by a WatchConfiguration in the git storage.  The explicit
+// resources are watched right away, the wildcards are resolved with discovery and again whenever a CRD is
+// installed.
+type ResourceWatcher struct {
+	dynamicClient   dynamic.Interface
+	discoveryClient discovery.DiscoveryInterface
+	gitStorage      gitStorage
+	config          *WatchConfiguration
+
+	lock sync.Mutex
+	// watched holds the group resources with an informer, the first version selected wins since the
+	// storage does not keep the version in the file names.
+	watched map[schema.GroupResource]schema.GroupVersionResource
+}
+
+func NewResourceWatcher(
+	dynamicClient dynamic.Interface,
+	discoveryClient discovery.DiscoveryInterface,
+	gitStorage gitStorage,
+	config *WatchConfiguration,
+) *ResourceWatcher {
+	return &ResourceWatcher{
+		dynamicClient:   dynamicClient,
+		discoveryClient: discoveryClient,
+		gitStorage:      gitStorage,
+		config:          config,
+		watched:         map[schema.GroupResource]schema.GroupVersionResource{},
+	}
+}
+
+// Start starts the informers and returns, they run until the context is done.
+func (w *ResourceWatcher) Start(ctx context.Context) {
+	wildcards := false
+	for _, rule := range w.config.Resources {
+		if !rule.explicit() {
+			wildcards = true
+			continue
+		}
+		w.watch(ctx, schema.GroupVersionResource{Group: rule.Group, Version: rule.Version, Resource: rule.Resource}, rule.Version)
+	}
+	if !wildcards {
+		return
+	}
+
+	// discovery finds the built-in and aggregated resources, failures of single groups are not fatal.
+	groups, resources, err := discovery.ServerGroupsAndResources(w.discoveryClient)
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		klog.Errorf("Failed to discover the resources to watch, only the CRDs installed from now on will be watched: %v", err)
+	} else if err != nil {
+		klog.Warningf("Failed to discover some of the resources to watch: %v", err)
+	}
+	for _, candidate := range discoveredResources(groups, resources) {
+		w.watch(ctx, candidate.gvr, candidate.preferredVersion)
+	}
+
+	// the CRDs installed later, for instance by the tests, are watched once they appear.
+	crdInformer := dynamicinformer.NewFilteredDynamicInformer(w.dynamicClient, crdResource, "", 0, cache.Indexers{}, nil).Informer()
+	crdInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			w.watchCRD(ctx, obj)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			w.watchCRD(ctx, obj)
+		},
+	})
+	go crdInformer.Run(ctx.Done())
+}
+
+func (w *ResourceWatcher) watchCRD(ctx context.Context, obj interface{}) {
+	objUnstructured, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(objUnstructured.Object, crd); err != nil {
+		klog.Errorf("Failed to decode CRD %s: %v", objUnstructured.GetName(), err)
+		return
+	}
+	for _, candidate := range crdResources(crd) {
+		w.watch(ctx, candidate.gvr, candidate.preferredVersion)
+	}
+}
+
+// watch starts an informer for the resource if a rule selects it and it is not watched yet.
+func (w *ResourceWatcher) watch(ctx context.Context, gvr schema.GroupVersionResource, preferredVersion string) {
+	rule, ok := w.config.matchingRule(gvr, preferredVersion)
+	if !ok {
+		return
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if _, ok := w.watched[gvr.GroupResource()]; ok {
+		return
+	}
+	w.watched[gvr.GroupResource()] = gvr
+
+	w.gitStorage.RedactFields(gvr.GroupResource(), rule.redactFields(gvr.GroupResource()))
+	var tweakListOptions dynamicinformer.TweakListOptionsFunc
+	if len(rule.LabelSelector) > 0 {
+		// the configuration was validated, the selector parses.
+		selector, _ := labels.Parse(rule.LabelSelector)
+		w.gitStorage.SelectLabels(gvr.GroupResource(), selector)
+		tweakListOptions = func(options *metav1.ListOptions) {
+			options.LabelSelector = rule.LabelSelector
+		}
+	}
+	informer := dynamicinformer.NewFilteredDynamicInformer(w.dynamicClient, gvr, "", 0, cache.Indexers{}, tweakListOptions).Informer()
+	wireResourceInformerToGitRepo(informer, w.gitStorage, gvr)
+	go informer.Run(ctx.Done())
+	go reconcileUnobservedDeletions(ctx, informer, w.gitStorage, gvr)
+}
+
+type candidateResource struct {
+	gvr              schema.GroupVersionResource
+	preferredVersion string
+}
+
+// discoveredResources returns every version of the resources that can be watched.
+func discoveredResources(groups []*metav1.APIGroup, resources []*metav1.APIResourceList) []candidateResource {
+	preferredVersions := map[string]string{}
+	for _, group := range groups {
+		preferredVersions[group.Name] = group.PreferredVersion.Version
+	}
+
+	ret := []candidateResource{}
+	for _, resourceList := range resources {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			if !sets.NewString(resource.Verbs...).HasAll("list", "watch") {
+				continue
+			}
+			// subresources have a / in their name
+			if len(resource.Name) == 0 || strings.Contains(resource.Name, "/") {
+				continue
+			}
+			ret = append(ret, candidateResource{gvr: gv.WithResource(resource.Name), preferredVersion: preferredVersions[gv.Group]})
+		}
+	}
+	return ret
+}
+
+// crdResources returns the served versions of the CRD, the storage version is used when a rule has no version.
+func crdResources(crd *apiextensionsv1.CustomResourceDefinition) []candidateResource {
+	storageVersion := ""
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			storageVersion = version.Name
+		}
+	}
+	ret := []candidateResource{}
+	for _, version := range crd.Spec.Versions {
+		if !version.Served {
+			continue
+		}
+		ret = append(ret, candidateResource{
+			gvr:              schema.GroupVersionResource{Group: crd.Spec.Group, Version: version.Name, Resource: crd.Spec.Names.Plural},
+			preferredVersion: storageVersion,
+		})
+	}
+	return ret
+}
@@ -12,13 +12,16 @@ import (
 	"github.com/openshift/origin/pkg/resourcewatch/storage"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/klog/v2"
 )
 
-// this doesn't appear to handle restarts cleanly.  To do so it would need to compare the resource version that it is applying
-// to the resource version present and it would need to handle unobserved deletions properly.  both are possible, neither is easy.
+// RunResourceWatch commits every change to the watched resources to the git repository.  It can be restarted
+// against an existing repository: the objects replayed by the informers are compared by resourceVersion with the
+// committed ones, and the objects deleted while we were not watching are removed once the informers have synced.
+// The resources below are watched by default, the file named by RESOURCEWATCH_CONFIG can add more, including
+// every resource of an API group and the CRDs installed while we are watching.
 func RunResourceWatch() error {
 	ctx, cancelFn := context.WithCancel(context.Background())
 	defer cancelFn()
@@ -61,8 +64,6 @@ func RunResourceWatch() error {
 		return err
 	}
 
-	dynamicInformer := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0)
-
 	resourcesToWatch := []schema.GroupVersionResource{
 		configResource("apiservers"),
 		configResource("authentications"),
@@ -115,13 +116,19 @@ func RunResourceWatch() error {
 		coreResource("serviceaccounts"),
 	}
 
-	configmonitor.WireResourceInformersToGitRepo(
-		dynamicInformer,
-		gitStorage,
-		resourcesToWatch,
-	)
+	watchConfig, err := configmonitor.WatchConfigurationFromEnv(configmonitor.NewWatchConfiguration(resourcesToWatch...))
+	if err != nil {
+		klog.Errorf("Failed to load the resources to watch with error %v", err)
+		return err
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(kubeConfig)
+	if err != nil {
+		klog.Errorf("Failed to create discovery client with error %v", err)
+		return err
+	}
 
-	dynamicInformer.Start(ctx.Done())
+	configmonitor.NewResourceWatcher(dynamicClient, discoveryClient, gitStorage, watchConfig).Start(ctx)
 
 	klog.Infof("Started all informers")
 
@@ -3,6 +3,7 @@ package storage
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -10,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/managedfields"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
 	"sigs.k8s.io/structured-merge-diff/v4/typed"
 )
 
@@ -68,3 +70,41 @@ func objectGVKNN(obj runtime.Object) string {
 
 	return fmt.Sprintf("%v/%v; %v", namespace, name, obj.GetObjectKind().GroupVersionKind())
 }
+
+// maxFieldDiffPaths bounds the length of FieldDiff, for instance when a whole spec is replaced.
+const maxFieldDiffPaths = 10
+
+// FieldDiff returns a compact description of the fields changed between the objects, like
+// "~.spec.logLevel +.metadata.labels.foo -.status.message", where ~ is modified, + added, and - removed.
+// The resourceVersion and the managed fields, which change every time, are left out.
+func FieldDiff(oldObj, obj *unstructured.Unstructured) (string, error) {
+	if oldObj == nil || obj == nil {
+		return "", nil
+	}
+	comparison, err := modifiedFields(oldObj, obj)
+	if err != nil {
+		return "", err
+	}
+
+	paths := []string{}
+	for _, changed := range []struct {
+		prefix string
+		set    *fieldpath.Set
+	}{
+		{prefix: "~", set: comparison.Modified},
+		{prefix: "+", set: comparison.Added},
+		{prefix: "-", set: comparison.Removed},
+	} {
+		changed.set.Leaves().Iterate(func(path fieldpath.Path) {
+			pathString := path.String()
+			if pathString == ".metadata.resourceVersion" || strings.HasPrefix(pathString, ".metadata.managedFields") {
+				return
+			}
+			paths = append(paths, changed.prefix+pathString)
+		})
+	}
+	if len(paths) > maxFieldDiffPaths {
+		paths = append(paths[:maxFieldDiffPaths], fmt.Sprintf("and %d more", len(paths)-maxFieldDiffPaths))
+	}
+	return strings.Join(paths, " "), nil
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestFieldDiff(t *testing.T) {
+	object := func(resourceVersion string, spec map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "operator.openshift.io/v1",
+			"kind":       "KubeAPIServer",
+			"metadata":   map[string]interface{}{"name": "cluster", "resourceVersion": resourceVersion},
+			"spec":       spec,
+		}}
+	}
+	manySpec := map[string]interface{}{}
+	for i := 0; i < 12; i++ {
+		manySpec[fmt.Sprintf("field%02d", i)] = "value"
+	}
+
+	tests := []struct {
+		name   string
+		oldObj *unstructured.Unstructured
+		obj    *unstructured.Unstructured
+		want   string
+	}{
+		{
+			name: "added",
+			obj:  object("1", map[string]interface{}{"logLevel": "Normal"}),
+		},
+		{
+			name:   "modified, added, and removed",
+			oldObj: object("1", map[string]interface{}{"logLevel": "Normal", "managementState": "Managed"}),
+			obj:    object("2", map[string]interface{}{"logLevel": "Debug", "forceRedeploymentReason": "now"}),
+			want:   "~.spec.logLevel +.spec.forceRedeploymentReason -.spec.managementState",
+		},
+		{
+			name:   "resourceVersion only",
+			oldObj: object("1", map[string]interface{}{"logLevel": "Normal"}),
+			obj:    object("2", map[string]interface{}{"logLevel": "Normal"}),
+		},
+		{
+			name:   "too many fields",
+			oldObj: object("1", map[string]interface{}{}),
+			obj:    object("2", manySpec),
+			want:   "+.spec.field00 +.spec.field01 +.spec.field02 +.spec.field03 +.spec.field04 +.spec.field05 +.spec.field06 +.spec.field07 +.spec.field08 +.spec.field09 and 2 more",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := FieldDiff(test.oldObj, test.obj)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("expected %q, but got %q", test.want, got)
+			}
+		})
+	}
+}
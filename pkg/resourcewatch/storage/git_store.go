@@ -16,8 +16,10 @@ import (
 	"gopkg.in/src-d/go-git.v4"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
@@ -62,10 +64,26 @@ type GitStorage struct {
 
 	currentlyRecording workingSet
 
+	// redactedFields are removed from the objects before they are committed.
+	redactedFields map[schema.GroupResource][][]string
+	// labelSelectors filter the objects of the resources that are only watched in part, see SelectLabels.
+	labelSelectors map[schema.GroupResource]labels.Selector
+
 	// Writing to Git repository must be synced otherwise Git will freak out
 	sync.Mutex
 }
 
+const (
+	// unobservedDeletionAuthor is the author of the removals found when reconciling after a restart.
+	unobservedDeletionAuthor = "unobserved-deletion"
+	// noLongerSelectedAuthor is the author of the removals of objects that still exist, but whose labels stopped
+	// matching the label selector of their resource.
+	noLongerSelectedAuthor = "no-longer-selected"
+	// unobservedRemovalAuthor is the author of the removals found when reconciling a resource with a label
+	// selector, the object was either deleted or stopped matching while we were not watching.
+	unobservedRemovalAuthor = "unobserved-deletion-or-no-longer-selected"
+)
+
 type gitOperation int
 
 const (
@@ -118,29 +136,33 @@ func (s *GitStorage) handle(gvr schema.GroupVersionResource, oldObj, obj *unstru
 	s.Lock()
 	defer s.Unlock()
 
+	// the watch reports an object whose labels stopped matching as deleted, with the labels it has now.  The
+	// labels must be checked before they could be redacted.
+	author := "unknown"
+	if selector, ok := s.labelSelectors[gvr.GroupResource()]; ok && delete && !selector.Matches(labels.Set(obj.GetLabels())) {
+		author = noLongerSelectedAuthor
+	}
+
+	redactedFields := s.redactedFields[gvr.GroupResource()]
+	oldObj, obj = redact(oldObj, redactedFields), redact(obj, redactedFields)
+
 	filePath, content, err := decodeUnstructuredObject(gvr, obj)
 	if err != nil {
 		klog.Warningf("Decoding %q failed: %v", filePath, err)
 		return
 	}
-	resourceName := ""
-	if len(gvr.Group) == 0 {
-		resourceName = gvr.Resource
-	} else {
-		resourceName = gvr.Resource + "." + gvr.Group
-	}
-	ocCommand := ""
-	if len(obj.GetNamespace()) == 0 {
-		ocCommand = fmt.Sprintf("%s/%s", resourceName, obj.GetName())
-	} else {
-		ocCommand = fmt.Sprintf("%s/%s -n %s", resourceName, obj.GetName(), obj.GetNamespace())
-	}
+	ocCommand := ResourceFile{Group: gvr.Group, Resource: gvr.Resource, Namespace: obj.GetNamespace(), Name: obj.GetName()}.ocCommand()
 
 	if delete {
+		// after a restart we may be told about the deletion of an object we already removed.
+		if _, err := os.Lstat(filepath.Join(s.path, filePath)); os.IsNotExist(err) {
+			klog.Infof("Skipping commitRemove for %s, it is not in the repository", filePath)
+			return
+		}
 		klog.Infof("Calling commitRemove for %s", filePath)
 		// ignore error, we've already reported and we're not doing anything else.
 		pollErr := wait.PollImmediate(1*time.Second, 15*time.Second, func() (bool, error) {
-			if err := s.commitRemove(filePath, "unknown", ocCommand); err != nil {
+			if err := s.commitRemove(filePath, author, ocCommand); err != nil {
 				klog.Error(err)
 				return false, nil
 			}
@@ -154,6 +176,22 @@ func (s *GitStorage) handle(gvr schema.GroupVersionResource, oldObj, obj *unstru
 		return
 	}
 
+	// after a restart the informers replay every object as an add.  Compare against what we already
+	// committed so that unchanged objects are skipped and changed objects are recorded as modifications.
+	storedObj, err := s.read(filePath)
+	if err != nil {
+		klog.Warningf("Reading stored content failed %q: %v", filePath, err)
+	}
+	if storedObj != nil {
+		if storedObj.GetResourceVersion() == obj.GetResourceVersion() {
+			klog.V(4).Infof("Skipping %s, resourceVersion %s is already committed", filePath, obj.GetResourceVersion())
+			return
+		}
+		if oldObj == nil {
+			oldObj = storedObj
+		}
+	}
+
 	klog.Infof("Calling write for %s", filePath)
 	operation, err := s.write(filePath, content)
 	if err != nil {
@@ -306,6 +344,54 @@ func decodeUnstructuredObject(gvr schema.GroupVersionResource, objUnstructured *
 	return filename, objectYAML, err
 }
 
+// ResourceFile identifies the object stored in a file of the repository.
+type ResourceFile struct {
+	Group     string
+	Resource  string
+	Namespace string
+	Name      string
+}
+
+// String returns the object as resource.group/name [-n namespace], the way it appears in the commit messages.
+func (r ResourceFile) String() string {
+	return r.ocCommand()
+}
+
+func (r ResourceFile) ocCommand() string {
+	resourceName := r.Resource
+	if len(r.Group) != 0 {
+		resourceName = r.Resource + "." + r.Group
+	}
+	if len(r.Namespace) == 0 {
+		return fmt.Sprintf("%s/%s", resourceName, r.Name)
+	}
+	return fmt.Sprintf("%s/%s -n %s", resourceName, r.Name, r.Namespace)
+}
+
+// parseResourceFilename is the reverse of resourceFilename.
+func parseResourceFilename(path string) (ResourceFile, bool) {
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	if !strings.HasSuffix(path, ".yaml") {
+		return ResourceFile{}, false
+	}
+	ret := ResourceFile{}
+	switch {
+	case len(parts) == 4 && parts[0] == "cluster-scoped-resources":
+		parts = parts[1:]
+	case len(parts) == 5 && parts[0] == "namespaces":
+		ret.Namespace = parts[1]
+		parts = parts[2:]
+	default:
+		return ResourceFile{}, false
+	}
+	if parts[0] != "core" {
+		ret.Group = parts[0]
+	}
+	ret.Resource = parts[1]
+	ret.Name = strings.TrimSuffix(parts[2], ".yaml")
+	return ret, true
+}
+
 // resourceFilename extracts the filename out from the group version kind
 func resourceFilename(gvr schema.GroupVersionResource, namespace, name string) string {
 	groupStr := ""
@@ -404,6 +490,90 @@ func (s *GitStorage) commitRemove(path, author, ocCommand string) error {
 	return nil
 }
 
+// read returns the object committed at the given path, or nil if there is none.
+func (s *GitStorage) read(name string) (*unstructured.Unstructured, error) {
+	content, err := os.ReadFile(filepath.Join(s.path, name))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(content, &obj.Object); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// SelectLabels tells the storage that only the objects of the resource matching the selector are watched.  The
+// removals of objects that stop matching are then authored by no-longer-selected rather than recorded as deletions.
+// It must be called before the informer of the resource is started.
+func (s *GitStorage) SelectLabels(gr schema.GroupResource, selector labels.Selector) {
+	if selector == nil || selector.Empty() {
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+
+	if s.labelSelectors == nil {
+		s.labelSelectors = map[schema.GroupResource]labels.Selector{}
+	}
+	s.labelSelectors[gr] = selector
+}
+
+// ReconcileDeletions removes the objects of the given resource that are in the repository but not in the live
+// objects.  It is called once the informer has synced after a restart, so that deletions which happened while we
+// were not watching are recorded.  listLiveObjects is called while holding the lock that every commit takes, so an
+// object added after it lists the live objects cannot be committed before the stored objects are read and removed.
+// With a label selector, the live objects cannot tell a deletion from an object that stopped matching, so those
+// removals are authored by unobserved-deletion-or-no-longer-selected.
+func (s *GitStorage) ReconcileDeletions(gvr schema.GroupVersionResource, listLiveObjects func() []*unstructured.Unstructured) error {
+	s.Lock()
+	defer s.Unlock()
+
+	author := unobservedDeletionAuthor
+	if _, ok := s.labelSelectors[gvr.GroupResource()]; ok {
+		author = unobservedRemovalAuthor
+	}
+
+	live := sets.NewString()
+	for _, obj := range listLiveObjects() {
+		live.Insert(resourceFilename(gvr, obj.GetNamespace(), obj.GetName()))
+	}
+
+	clusterScoped := resourceFilename(gvr, "", "*")
+	namespaced := resourceFilename(gvr, "*", "*")
+	var stored []string
+	for _, pattern := range []string{clusterScoped, namespaced} {
+		matches, err := filepath.Glob(filepath.Join(s.path, pattern))
+		if err != nil {
+			return err
+		}
+		stored = append(stored, matches...)
+	}
+
+	var errs []error
+	for _, fullPath := range stored {
+		filePath, err := filepath.Rel(s.path, fullPath)
+		if err != nil {
+			return err
+		}
+		if live.Has(filePath) {
+			continue
+		}
+		info, ok := parseResourceFilename(filePath)
+		if !ok {
+			continue
+		}
+		klog.Infof("Calling commitRemove for unobserved deletion of %s", filePath)
+		if err := s.commitRemove(filePath, author, info.ocCommand()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
 // write handle writing the content into git repository
 func (s *GitStorage) write(name string, content []byte) (gitOperation, error) {
 	fullPath := filepath.Join(s.path, name)
@@ -0,0 +1,264 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/plumbing/storer"
+	"gopkg.in/src-d/go-git.v4/utils/merkletrie"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+type Operation string
+
+const (
+	OperationAdded    Operation = "added"
+	OperationModified Operation = "modified"
+	OperationRemoved  Operation = "removed"
+)
+
+// Change is a single commit to a single object of the repository.
+type Change struct {
+	Commit    string
+	Time      time.Time
+	Operation Operation
+	// Modifiers are the field managers we guessed made the change, they come from the commit author.
+	Modifiers []string
+	Path      string
+	Object    ResourceFile
+
+	// Before and After are the object before and after the change, they are only loaded with HistoryQuery.WithObjects.
+	// Before is nil for an addition, After is nil for a removal.
+	Before *unstructured.Unstructured `json:"-"`
+	After  *unstructured.Unstructured `json:"-"`
+}
+
+// HistoryQuery selects the changes returned by History.  Empty fields match everything.
+type HistoryQuery struct {
+	Since time.Time
+	Until time.Time
+
+	// Resource is either the plural resource name, like clusteroperators, or resource.group.
+	Resource  string
+	Namespace string
+	Name      string
+	// Groups only matches the resources of these API groups, the core group is empty.
+	Groups []string
+
+	// FieldPath, like .spec.logLevel, only matches the changes that add, modify, or remove this field.
+	FieldPath string
+
+	// WithObjects loads the objects before and after each change.
+	WithObjects bool
+}
+
+// History returns the changes matching the query in the git repository written by GitStorage, oldest first.
+func History(repositoryPath string, query HistoryQuery) ([]Change, error) {
+	repo, err := git.PlainOpen(repositoryPath)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := repo.Log(&git.LogOptions{Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return nil, err
+	}
+	defer commits.Close()
+
+	var fieldPath []string
+	if len(query.FieldPath) > 0 {
+		fieldPath = strings.Split(strings.TrimPrefix(query.FieldPath, "."), ".")
+	}
+
+	// commits are visited newest first, several commits can share the same second.
+	newestFirst := [][]Change{}
+	err = commits.ForEach(func(commit *object.Commit) error {
+		when := commit.Committer.When
+		if !query.Until.IsZero() && when.After(query.Until) {
+			return nil
+		}
+		if !query.Since.IsZero() && when.Before(query.Since) {
+			// everything left is older.
+			return storer.ErrStop
+		}
+
+		diff, err := commitChanges(commit)
+		if err != nil {
+			return fmt.Errorf("failed to read the changes of commit %s: %w", commit.Hash, err)
+		}
+		changes := []Change{}
+		for _, change := range diff {
+			path := change.To.Name
+			if len(path) == 0 {
+				path = change.From.Name
+			}
+			resourceFile, ok := parseResourceFilename(path)
+			if !ok || !query.matches(resourceFile) {
+				continue
+			}
+			action, err := change.Action()
+			if err != nil {
+				return err
+			}
+			if len(fieldPath) > 0 {
+				modified, err := fieldModified(change, fieldPath)
+				if err != nil {
+					return fmt.Errorf("failed to compare %s in commit %s: %w", path, commit.Hash, err)
+				}
+				if !modified {
+					continue
+				}
+			}
+			result := Change{
+				Commit:    commit.Hash.String(),
+				Time:      when,
+				Operation: operationFor(action),
+				Modifiers: strings.Split(commit.Author.Name, " AND "),
+				Path:      path,
+				Object:    resourceFile,
+			}
+			if query.WithObjects {
+				if result.Before, result.After, err = changeObjects(change); err != nil {
+					return fmt.Errorf("failed to read %s in commit %s: %w", path, commit.Hash, err)
+				}
+			}
+			changes = append(changes, result)
+		}
+		newestFirst = append(newestFirst, changes)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []Change{}
+	for i := len(newestFirst) - 1; i >= 0; i-- {
+		ret = append(ret, newestFirst[i]...)
+	}
+	return ret, nil
+}
+
+// TouchedObjects returns every object changed at least once, in the order of their first change.
+func TouchedObjects(changes []Change) []ResourceFile {
+	seen := map[ResourceFile]bool{}
+	ret := []ResourceFile{}
+	for _, change := range changes {
+		if seen[change.Object] {
+			continue
+		}
+		seen[change.Object] = true
+		ret = append(ret, change.Object)
+	}
+	return ret
+}
+
+func (q HistoryQuery) matches(resourceFile ResourceFile) bool {
+	if len(q.Resource) > 0 && q.Resource != resourceFile.Resource && q.Resource != resourceFile.Resource+"."+resourceFile.Group {
+		return false
+	}
+	if len(q.Namespace) > 0 && q.Namespace != resourceFile.Namespace {
+		return false
+	}
+	if len(q.Name) > 0 && q.Name != resourceFile.Name {
+		return false
+	}
+	if len(q.Groups) > 0 && !sets.NewString(q.Groups...).Has(resourceFile.Group) {
+		return false
+	}
+	return true
+}
+
+// commitChanges diffs the commit against its parent, the first commit is diffed against an empty tree.
+func commitChanges(commit *object.Commit) (object.Changes, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	var parentTree *object.Tree
+	if commit.NumParents() > 0 {
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil, err
+		}
+	}
+	return object.DiffTree(parentTree, tree)
+}
+
+func operationFor(action merkletrie.Action) Operation {
+	switch action {
+	case merkletrie.Insert:
+		return OperationAdded
+	case merkletrie.Delete:
+		return OperationRemoved
+	default:
+		return OperationModified
+	}
+}
+
+// fieldModified returns true if the field differs between both sides of the change.
+func fieldModified(change *object.Change, fieldPath []string) (bool, error) {
+	from, to, err := changeObjects(change)
+	if err != nil {
+		return false, err
+	}
+	fromValue, fromFound, err := objectField(from, fieldPath)
+	if err != nil {
+		return false, err
+	}
+	toValue, toFound, err := objectField(to, fieldPath)
+	if err != nil {
+		return false, err
+	}
+	if fromFound != toFound {
+		return true, nil
+	}
+	return !equality.Semantic.DeepEqual(fromValue, toValue), nil
+}
+
+func objectField(obj *unstructured.Unstructured, fieldPath []string) (interface{}, bool, error) {
+	if obj == nil {
+		return nil, false, nil
+	}
+	return unstructured.NestedFieldNoCopy(obj.Object, fieldPath...)
+}
+
+// changeObjects decodes both sides of the change, a missing side is nil.
+func changeObjects(change *object.Change) (*unstructured.Unstructured, *unstructured.Unstructured, error) {
+	from, to, err := change.Files()
+	if err != nil {
+		return nil, nil, err
+	}
+	before, err := fileObject(from)
+	if err != nil {
+		return nil, nil, err
+	}
+	after, err := fileObject(to)
+	if err != nil {
+		return nil, nil, err
+	}
+	return before, after, nil
+}
+
+func fileObject(file *object.File) (*unstructured.Unstructured, error) {
+	if file == nil {
+		return nil, nil
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal([]byte(content), &obj.Object); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
@@ -0,0 +1,284 @@
+package storage
+
+import (
+	"os/exec"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestHistory(t *testing.T) {
+	t.Setenv("GIT_COMMITTER_NAME", "resourcewatch")
+	t.Setenv("GIT_COMMITTER_EMAIL", "ci-monitor@openshift.io")
+
+	s, err := NewGitStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	clusterOperators := schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "clusteroperators"}
+	kubeAPIServers := schema.GroupVersionResource{Group: "operator.openshift.io", Version: "v1", Resource: "kubeapiservers"}
+	pods := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+	start := time.Now().Add(-time.Second)
+	kubeAPIServerOperator := newObject("clusteroperators", "", "kube-apiserver", "1", "")
+	s.handle(clusterOperators, nil, kubeAPIServerOperator, false)
+	operatorConfig := newObject("kubeapiservers", "", "cluster", "1", "Normal")
+	s.handle(kubeAPIServers, nil, operatorConfig, false)
+	pod := newObject("pods", "openshift-etcd", "etcd-0", "1", "")
+	s.handle(pods, nil, pod, false)
+
+	updatedOperatorConfig := newObject("kubeapiservers", "", "cluster", "2", "Debug")
+	s.handle(kubeAPIServers, operatorConfig, updatedOperatorConfig, false)
+
+	// a restart replays every object as an add: unchanged objects are skipped, changed objects are modifications.
+	s.handle(clusterOperators, nil, kubeAPIServerOperator, false)
+	s.handle(kubeAPIServers, nil, newObject("kubeapiservers", "", "cluster", "3", "Trace"), false)
+	if count := commitCount(t, s.path); count != 5 {
+		t.Fatalf("expected 5 commits, but got %d", count)
+	}
+
+	// the pod was deleted while we were not watching
+	if err := s.ReconcileDeletions(pods, liveObjects()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ReconcileDeletions(clusterOperators, liveObjects(kubeAPIServerOperator)); err != nil {
+		t.Fatal(err)
+	}
+	// the deletion is observed once we are watching again
+	s.handle(pods, nil, pod, true)
+	if count := commitCount(t, s.path); count != 6 {
+		t.Fatalf("expected 6 commits, but got %d", count)
+	}
+
+	tests := []struct {
+		name    string
+		query   HistoryQuery
+		want    []string
+		objects []string
+	}{
+		{
+			name:    "everything",
+			query:   HistoryQuery{Since: start},
+			want:    []string{"added clusteroperators.config.openshift.io/kube-apiserver", "added kubeapiservers.operator.openshift.io/cluster", "added pods/etcd-0 -n openshift-etcd", "modified kubeapiservers.operator.openshift.io/cluster", "modified kubeapiservers.operator.openshift.io/cluster", "removed pods/etcd-0 -n openshift-etcd"},
+			objects: []string{"clusteroperators.config.openshift.io/kube-apiserver", "kubeapiservers.operator.openshift.io/cluster", "pods/etcd-0 -n openshift-etcd"},
+		},
+		{
+			name:  "one object",
+			query: HistoryQuery{Resource: "clusteroperators", Name: "kube-apiserver"},
+			want:  []string{"added clusteroperators.config.openshift.io/kube-apiserver"},
+		},
+		{
+			name:  "one namespace",
+			query: HistoryQuery{Resource: "pods", Namespace: "openshift-etcd"},
+			want:  []string{"added pods/etcd-0 -n openshift-etcd", "removed pods/etcd-0 -n openshift-etcd"},
+		},
+		{
+			name:  "one field",
+			query: HistoryQuery{Resource: "kubeapiservers.operator.openshift.io", FieldPath: ".spec.logLevel"},
+			want:  []string{"added kubeapiservers.operator.openshift.io/cluster", "modified kubeapiservers.operator.openshift.io/cluster", "modified kubeapiservers.operator.openshift.io/cluster"},
+		},
+		{
+			name:  "core group",
+			query: HistoryQuery{Groups: []string{""}},
+			want:  []string{"added pods/etcd-0 -n openshift-etcd", "removed pods/etcd-0 -n openshift-etcd"},
+		},
+		{
+			name:  "out of the interval",
+			query: HistoryQuery{Until: start},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			changes, err := History(s.path, test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, change := range changes {
+				got = append(got, string(change.Operation)+" "+change.Object.String())
+			}
+			if test.want == nil {
+				test.want = []string{}
+			}
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("expected changes %v, but got %v", test.want, got)
+			}
+			if test.objects == nil {
+				return
+			}
+			objects := []string{}
+			for _, object := range TouchedObjects(changes) {
+				objects = append(objects, object.String())
+			}
+			if !reflect.DeepEqual(test.objects, objects) {
+				t.Errorf("expected objects %v, but got %v", test.objects, objects)
+			}
+		})
+	}
+
+	changes, err := History(s.path, HistoryQuery{Resource: "kubeapiservers", FieldPath: ".spec.logLevel"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := changes[1].Modifiers; !reflect.DeepEqual(got, []string{"cluster-kube-apiserver-operator"}) {
+		t.Errorf("expected the field manager to be the modifier, but got %v", got)
+	}
+	changes, err = History(s.path, HistoryQuery{Resource: "pods"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := changes[len(changes)-1].Modifiers; !reflect.DeepEqual(got, []string{unobservedDeletionAuthor}) {
+		t.Errorf("expected an unobserved deletion, but got %v", got)
+	}
+}
+
+func TestReconcileDeletionsWithConcurrentAdd(t *testing.T) {
+	t.Setenv("GIT_COMMITTER_NAME", "resourcewatch")
+	t.Setenv("GIT_COMMITTER_EMAIL", "ci-monitor@openshift.io")
+
+	s, err := NewGitStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pods := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+	// the pod is added right after the live objects are listed, it must not be mistaken for a deletion.
+	added := make(chan struct{})
+	err = s.ReconcileDeletions(pods, func() []*unstructured.Unstructured {
+		go func() {
+			defer close(added)
+			s.handle(pods, nil, newObject("pods", "openshift-etcd", "etcd-0", "1", ""), false)
+		}()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-added
+
+	changes, err := History(s.path, HistoryQuery{Resource: "pods"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Operation != OperationAdded {
+		t.Errorf("expected only the addition of the pod, but got %v", changes)
+	}
+}
+
+func TestLabelSelectedRemovals(t *testing.T) {
+	t.Setenv("GIT_COMMITTER_NAME", "resourcewatch")
+	t.Setenv("GIT_COMMITTER_EMAIL", "ci-monitor@openshift.io")
+
+	s, err := NewGitStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pods := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	s.SelectLabels(pods.GroupResource(), labels.SelectorFromSet(labels.Set{"app": "etcd"}))
+
+	newPod := func(name, resourceVersion, app string) *unstructured.Unstructured {
+		pod := newObject("pods", "openshift-etcd", name, resourceVersion, "")
+		pod.SetLabels(map[string]string{"app": app})
+		return pod
+	}
+	s.handle(pods, nil, newPod("relabeled", "1", "etcd"), false)
+	s.handle(pods, nil, newPod("deleted", "1", "etcd"), false)
+	s.handle(pods, nil, newPod("unobserved", "1", "etcd"), false)
+
+	// the watch reports a pod whose labels stop matching as deleted, with its new labels.
+	s.handle(pods, nil, newPod("relabeled", "2", "guard"), true)
+	s.handle(pods, nil, newPod("deleted", "1", "etcd"), true)
+	if err := s.ReconcileDeletions(pods, liveObjects()); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := History(s.path, HistoryQuery{Resource: "pods"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string][]string{}
+	for _, change := range changes {
+		if change.Operation == OperationRemoved {
+			got[change.Object.Name] = change.Modifiers
+		}
+	}
+	want := map[string][]string{
+		"relabeled":  {noLongerSelectedAuthor},
+		"deleted":    {"unknown"},
+		"unobserved": {unobservedRemovalAuthor},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("expected removals by %v, but got %v", want, got)
+	}
+}
+
+func TestParseResourceFilename(t *testing.T) {
+	for _, resourceFile := range []ResourceFile{
+		{Group: "config.openshift.io", Resource: "clusteroperators", Name: "kube-apiserver"},
+		{Resource: "pods", Namespace: "openshift-etcd", Name: "etcd-0"},
+	} {
+		gvr := schema.GroupVersionResource{Group: resourceFile.Group, Version: "v1", Resource: resourceFile.Resource}
+		got, ok := parseResourceFilename(resourceFilename(gvr, resourceFile.Namespace, resourceFile.Name))
+		if !ok || got != resourceFile {
+			t.Errorf("expected %v, but got %v", resourceFile, got)
+		}
+	}
+	for _, path := range []string{"README.md", "namespaces/openshift-etcd/core/pods/etcd-0.json", "cluster-scoped-resources/core/nodes"} {
+		if _, ok := parseResourceFilename(path); ok {
+			t.Errorf("expected %s not to be a resource file", path)
+		}
+	}
+}
+
+func newObject(resource, namespace, name, resourceVersion, logLevel string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.openshift.io/v1",
+		"kind":       strings.TrimSuffix(resource, "s"),
+		"metadata": map[string]interface{}{
+			"name":            name,
+			"resourceVersion": resourceVersion,
+			"managedFields": []interface{}{
+				map[string]interface{}{
+					"manager":    "cluster-kube-apiserver-operator",
+					"operation":  "Update",
+					"apiVersion": "example.openshift.io/v1",
+					"fieldsType": "FieldsV1",
+					"fieldsV1":   map[string]interface{}{"f:spec": map[string]interface{}{"f:logLevel": map[string]interface{}{}}},
+				},
+			},
+		},
+	}}
+	if len(namespace) > 0 {
+		obj.SetNamespace(namespace)
+	}
+	if len(logLevel) > 0 {
+		obj.Object["spec"] = map[string]interface{}{"logLevel": logLevel}
+	}
+	return obj
+}
+
+func liveObjects(objs ...*unstructured.Unstructured) func() []*unstructured.Unstructured {
+	return func() []*unstructured.Unstructured {
+		return objs
+	}
+}
+
+func commitCount(t *testing.T, path string) int {
+	cmd := exec.Command("git", "rev-list", "--count", "HEAD")
+	cmd.Dir = path
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v: %s", err, output)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// lastAppliedConfigAnnotation holds a full copy of the object as it was last applied, redacted fields included.
+const lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// redactedValue replaces the redacted fields.  The keys of a redacted map are kept, so that the history
+// still shows which keys were added or removed, for example in the data of a Secret.
+const redactedValue = "<redacted>"
+
+// RedactFields makes the storage replace the given fields, like .data, before the objects of the resource
+// are committed.  The last-applied-configuration annotation is removed as well, since it would repeat them.  It
+// must be called before the informer of the resource is started.
+func (s *GitStorage) RedactFields(gr schema.GroupResource, fieldPaths []string) {
+	if len(fieldPaths) == 0 {
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+
+	if s.redactedFields == nil {
+		s.redactedFields = map[schema.GroupResource][][]string{}
+	}
+	for _, fieldPath := range fieldPaths {
+		s.redactedFields[gr] = append(s.redactedFields[gr], strings.Split(strings.TrimPrefix(fieldPath, "."), "."))
+	}
+}
+
+// redact returns a copy of the object without the given fields and the last-applied-configuration annotation, or
+// the object itself if there is nothing to redact.
+func redact(obj *unstructured.Unstructured, fieldPaths [][]string) *unstructured.Unstructured {
+	if obj == nil || len(fieldPaths) == 0 {
+		return obj
+	}
+	ret := obj.DeepCopy()
+	unstructured.RemoveNestedField(ret.Object, "metadata", "annotations", lastAppliedConfigAnnotation)
+	for _, fieldPath := range fieldPaths {
+		value, found, err := unstructured.NestedFieldNoCopy(ret.Object, fieldPath...)
+		if err != nil || !found || value == nil {
+			continue
+		}
+		if values, ok := value.(map[string]interface{}); ok {
+			for key := range values {
+				values[key] = redactedValue
+			}
+			continue
+		}
+		// the error is impossible, we just found the field.
+		_ = unstructured.SetNestedField(ret.Object, redactedValue, fieldPath...)
+	}
+	return ret
+}
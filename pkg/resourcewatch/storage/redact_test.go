@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRedact(t *testing.T) {
+	secret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"name":      "token",
+			"namespace": "openshift-etcd",
+			// kubectl apply keeps a copy of the unredacted data.
+			"annotations": map[string]interface{}{lastAppliedConfigAnnotation: `{"data":{"password":"c2VjcmV0"}}`, "owner": "etcd"},
+		},
+		"type": "Opaque",
+		"data": map[string]interface{}{"username": "YWRtaW4=", "password": "c2VjcmV0"},
+		"spec": map[string]interface{}{"token": "abc"},
+	}}
+	redacted := redact(secret, [][]string{{"data"}, {"spec", "token"}, {"stringData"}})
+	want := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "token", "namespace": "openshift-etcd", "annotations": map[string]interface{}{"owner": "etcd"}},
+		"type":       "Opaque",
+		"data":       map[string]interface{}{"username": redactedValue, "password": redactedValue},
+		"spec":       map[string]interface{}{"token": redactedValue},
+	}
+	if !reflect.DeepEqual(want, redacted.Object) {
+		t.Errorf("expected %v, but got %v", want, redacted.Object)
+	}
+	if secret.Object["data"].(map[string]interface{})["password"] != "c2VjcmV0" {
+		t.Errorf("expected the informer's object not to be modified")
+	}
+	if redact(secret, nil) != secret {
+		t.Errorf("expected the object itself when there is nothing to redact")
+	}
+}
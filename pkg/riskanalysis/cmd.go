@@ -29,13 +29,16 @@ const (
 type Options struct {
 	JUnitDir string
 	SippyURL string
+	// PassRateFile, when set, is a json list of TestPassRate used to compute the risk analysis locally
+	// instead of requesting it from sippy.
+	PassRateFile string
 }
 
 const testFailureSummaryFilePrefix = "test-failures-summary"
 const sippyURL = "https://sippy.dptools.openshift.org/sippy-ng/"
 
-// Run performs the test risk analysis by reading the output files from the test run, submitting them to sippy,
-// and writing out the analysis result as a new artifact.
+// Run performs the test risk analysis by reading the output files from the test run, submitting them to sippy
+// or analyzing them against PassRateFile, and writing out the analysis result as a new artifact.
 func (opt *Options) Run() error {
 	logrus.Infof("Scanning for %s files in: %s", testFailureSummaryFilePrefix, opt.JUnitDir)
 
@@ -71,32 +74,79 @@ func (opt *Options) Run() error {
 
 	// We will often have more than one output file for this job run because openshift-tests is often
 	// invoked multiple times (pre/post upgrade). We need to merge the data together in this case.
-	var finalProwJobRun *ProwJobRun
-	for _, pjr := range prowJobRuns {
-		if finalProwJobRun == nil {
-			finalProwJobRun = pjr
-			continue
+	finalProwJobRun, err := MergeProwJobRuns(prowJobRuns)
+	if err != nil {
+		logrus.WithError(err).Error("Error merging test failure summaries")
+		return nil
+	}
+
+	var riskAnalysisBytes []byte
+	sippyLink := sippyURL
+	if len(opt.PassRateFile) > 0 {
+		riskAnalysisBytes, err = analyzeLocally(opt.PassRateFile, finalProwJobRun)
+		if err != nil {
+			logrus.WithError(err).Error("Error running local risk analysis")
+			return nil
 		}
-		if pjr.ProwJob.Name != finalProwJobRun.ProwJob.Name {
-			logrus.Errorf("Mismatched job names found in %s files, %s != %s",
-				testFailureSummaryFilePrefix, finalProwJobRun.ProwJob.Name, pjr.ProwJob.Name)
+		// there is no sippy page for data we computed ourselves.
+		sippyLink = ""
+	} else {
+		riskAnalysisBytes, err = requestRiskAnalysis(opt.SippyURL, finalProwJobRun)
+		if err != nil {
+			logrus.WithError(err).Error("Unable to obtain risk analysis from sippy")
 			return nil
 		}
-		finalProwJobRun.Tests = append(finalProwJobRun.Tests, pjr.Tests...)
-		finalProwJobRun.TestCount += pjr.TestCount
 	}
 
-	inputBytes, err := json.Marshal(finalProwJobRun)
+	outputFile := filepath.Join(opt.JUnitDir, "risk-analysis.json")
+	err = ioutil.WriteFile(outputFile, riskAnalysisBytes, 0644)
 	if err != nil {
-		logrus.WithError(err).Error("Error marshalling results")
+		logrus.WithError(err).Error("Error writing risk analysis json artifact")
 		return nil
 	}
+	logrus.Infof("Successfully wrote: %s", outputFile)
 
-	req, err := http.NewRequest("GET", opt.SippyURL, bytes.NewBuffer(inputBytes))
+	disruptionBytes := []byte(`{Backends: []}`)
+	da, err := runDisruptionAnalysis(opt, finalProwJobRun.ClusterData.JobType)
 	if err != nil {
-		logrus.WithError(err).Error("Error creating GET request during risk analysis")
+		logrus.WithError(err).Error("error running disruption analysis locally")
 		return nil
 	}
+	disruptionBytes, err = json.Marshal(da)
+	if err != nil {
+		logrus.WithError(err).Error("Error marshalling disruption results")
+		return nil
+	}
+
+	// Write html file for spyglass
+	riskAnalysisHTMLTemplate := testdata.MustAsset("e2echart/test-risk-analysis.html")
+	sippyLinkHTML := ""
+	if len(sippyLink) > 0 {
+		sippyLinkHTML = fmt.Sprintf(`<a target="_blank" href="%s">Link to Sippy</a>`, sippyLink)
+	}
+	html := bytes.ReplaceAll(riskAnalysisHTMLTemplate, []byte("TEST_RISK_ANALYSIS_SIPPY_LINK_GOES_HERE"), []byte(sippyLinkHTML))
+	html = bytes.ReplaceAll(html, []byte("TEST_RISK_ANALYSIS_JSON_GOES_HERE"), riskAnalysisBytes)
+	html = bytes.ReplaceAll(html, []byte("TEST_DISRUPTION_ANALYSIS_JSON_GOES_HERE"), disruptionBytes)
+	path := filepath.Join(opt.JUnitDir, fmt.Sprintf("%s.html", "test-risk-analysis"))
+	if err := ioutil.WriteFile(path, html, 0644); err != nil {
+		logrus.WithError(err).Error("Error writing output file")
+		return nil
+	}
+
+	return nil
+}
+
+// requestRiskAnalysis submits the merged job run to sippy and returns its risk analysis.
+func requestRiskAnalysis(sippyAPIURL string, finalProwJobRun *ProwJobRun) ([]byte, error) {
+	inputBytes, err := json.Marshal(finalProwJobRun)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling results: %w", err)
+	}
+
+	req, err := http.NewRequest("GET", sippyAPIURL, bytes.NewBuffer(inputBytes))
+	if err != nil {
+		return nil, fmt.Errorf("error creating GET request during risk analysis: %w", err)
+	}
 	req.Header.Set("Content-Type", "application/json")
 	client := &http.Client{}
 
@@ -122,50 +172,27 @@ func (opt *Options) Run() error {
 		time.Sleep(time.Duration(i*30) * time.Second)
 	}
 	if !clientDoSuccess {
-		logrus.WithError(err).Error("Unable to obtain risk analysis from sippy after retries")
-		return nil
+		return nil, fmt.Errorf("unable to obtain risk analysis from sippy after retries: %w", err)
 	}
 	defer resp.Body.Close()
 
 	riskAnalysisBytes, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		logrus.WithError(err).Error("Error reading risk analysis request body from sippy")
-		return nil
+		return nil, fmt.Errorf("error reading risk analysis request body from sippy: %w", err)
 	}
 	logrus.Info("response Body:", string(riskAnalysisBytes))
 
-	outputFile := filepath.Join(opt.JUnitDir, "risk-analysis.json")
-	err = ioutil.WriteFile(outputFile, riskAnalysisBytes, 0644)
-	if err != nil {
-		logrus.WithError(err).Error("Error writing risk analysis json artifact")
-		return nil
-	}
-	logrus.Infof("Successfully wrote: %s", outputFile)
+	return riskAnalysisBytes, nil
+}
 
-	disruptionBytes := []byte(`{Backends: []}`)
-	da, err := runDisruptionAnalysis(opt, finalProwJobRun.ClusterData.JobType)
+// analyzeLocally computes the risk analysis from a file of historical pass rates, for clusters that cannot reach sippy.
+func analyzeLocally(passRateFile string, finalProwJobRun *ProwJobRun) ([]byte, error) {
+	logrus.Infof("Computing risk analysis from pass rates in: %s", passRateFile)
+	passRates, err := NewPassRateDataFromFile(passRateFile)
 	if err != nil {
-		logrus.WithError(err).Error("error running disruption analysis locally")
-		return nil
-	}
-	disruptionBytes, err = json.Marshal(da)
-	if err != nil {
-		logrus.WithError(err).Error("Error marshalling disruption results")
-		return nil
-	}
-
-	// Write html file for spyglass
-	riskAnalysisHTMLTemplate := testdata.MustAsset("e2echart/test-risk-analysis.html")
-	html := bytes.ReplaceAll(riskAnalysisHTMLTemplate, []byte("TEST_RISK_ANALYSIS_SIPPY_URL_GOES_HERE"), []byte(sippyURL))
-	html = bytes.ReplaceAll(html, []byte("TEST_RISK_ANALYSIS_JSON_GOES_HERE"), riskAnalysisBytes)
-	html = bytes.ReplaceAll(html, []byte("TEST_DISRUPTION_ANALYSIS_JSON_GOES_HERE"), disruptionBytes)
-	path := filepath.Join(opt.JUnitDir, fmt.Sprintf("%s.html", "test-risk-analysis"))
-	if err := ioutil.WriteFile(path, html, 0644); err != nil {
-		logrus.WithError(err).Error("Error writing output file")
-		return nil
+		return nil, err
 	}
-
-	return nil
+	return json.Marshal(AnalyzeJobRun(finalProwJobRun, passRates))
 }
 
 type disruptionBackendAnalysis struct {
@@ -0,0 +1,236 @@
+package riskanalysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+const (
+	// sippyFailureStatus is the status getSippyStatusCode uses for a test that failed and never passed.
+	sippyFailureStatus = 12
+
+	// maxFailuresToAnalyze matches sippy, a job run with more failures than this is high risk without looking
+	// at the individual tests.
+	maxFailuresToAnalyze = 20
+
+	// minRunsForRisk is the number of historical runs of a test required before we trust its pass rate.
+	minRunsForRisk = 7
+)
+
+// Risk levels mirror the ones sippy returns so the risk analysis html renders the same either way.
+var (
+	RiskLevelNone    = RiskLevel{Name: "None", Level: 0}
+	RiskLevelLow     = RiskLevel{Name: "Low", Level: 1}
+	RiskLevelUnknown = RiskLevel{Name: "Unknown", Level: 25}
+	RiskLevelMedium  = RiskLevel{Name: "Medium", Level: 50}
+	RiskLevelHigh    = RiskLevel{Name: "High", Level: 100}
+)
+
+// TestPassRate is the historical result counts for one test on one job type.  A file of these, as a json list,
+// is the input for an offline risk analysis.
+type TestPassRate struct {
+	TestName string
+
+	platformidentification.JobType `json:",inline"`
+
+	Passes   int
+	Failures int
+	Flakes   int
+}
+
+func (r TestPassRate) runs() int {
+	return r.Passes + r.Failures + r.Flakes
+}
+
+// passPercentage counts flakes as passes, like sippy does, because the test eventually succeeded.
+func (r TestPassRate) passPercentage() float64 {
+	if r.runs() == 0 {
+		return 0
+	}
+	return float64(r.Passes+r.Flakes) * 100 / float64(r.runs())
+}
+
+type testPassRateKey struct {
+	testName string
+	jobType  platformidentification.JobType
+}
+
+// PassRateData is historical pass rates indexed by test and job type.
+type PassRateData struct {
+	passRates map[testPassRateKey]TestPassRate
+}
+
+// NewPassRateDataFromFile reads a json list of TestPassRate.
+func NewPassRateDataFromFile(filename string) (*PassRateData, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	passRates := []TestPassRate{}
+	if err := json.Unmarshal(data, &passRates); err != nil {
+		return nil, fmt.Errorf("failed to decode %q: %w", filename, err)
+	}
+	return NewPassRateData(passRates), nil
+}
+
+func NewPassRateData(passRates []TestPassRate) *PassRateData {
+	ret := &PassRateData{passRates: map[testPassRateKey]TestPassRate{}}
+	for _, passRate := range passRates {
+		key := testPassRateKey{testName: passRate.TestName, jobType: passRate.JobType}
+		// the same test and job type may be listed more than once if the file was built from several sources.
+		existing := ret.passRates[key]
+		existing.TestName = passRate.TestName
+		existing.JobType = passRate.JobType
+		existing.Passes += passRate.Passes
+		existing.Failures += passRate.Failures
+		existing.Flakes += passRate.Flakes
+		ret.passRates[key] = existing
+	}
+	return ret
+}
+
+// bestMatch returns the pass rate for the job type, or for the same job type on the previous release.
+func (d *PassRateData) bestMatch(testName string, jobType platformidentification.JobType) (TestPassRate, bool) {
+	if passRate, ok := d.passRates[testPassRateKey{testName: testName, jobType: jobType}]; ok && passRate.runs() >= minRunsForRisk {
+		return passRate, true
+	}
+	if len(jobType.Release) == 0 {
+		return TestPassRate{}, false
+	}
+	previousJobType, _ := historicaldata.PreviousReleaseUpgrade(jobType)
+	if passRate, ok := d.passRates[testPassRateKey{testName: testName, jobType: previousJobType}]; ok && passRate.runs() >= minRunsForRisk {
+		return passRate, true
+	}
+	return TestPassRate{}, false
+}
+
+// The types below are the subset of the sippy risk analysis response that test-risk-analysis.html reads.
+
+type RiskLevel struct {
+	Name  string
+	Level int
+}
+
+type RiskAnalysis struct {
+	Level   RiskLevel
+	Reasons []string
+}
+
+type Bug struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary"`
+	URL     string `json:"url"`
+}
+
+type TestRiskAnalysis struct {
+	Name     string
+	Risk     RiskAnalysis
+	OpenBugs []Bug
+}
+
+type JobRunRiskAnalysis struct {
+	ProwJobName    string
+	ProwJobRunID   int
+	Release        string
+	CompareRelease string
+	Tests          []TestRiskAnalysis
+	OverallRisk    RiskAnalysis
+	OpenBugs       []Bug
+}
+
+// AnalyzeJobRun computes the risk of each failed test in the job run from historical pass rates, the same way sippy
+// does: a failure of a test that almost always passes on similar jobs is high risk, a failure of a test that
+// often fails anyway is low risk.
+func AnalyzeJobRun(jobRun *ProwJobRun, passRates *PassRateData) *JobRunRiskAnalysis {
+	jobType := jobRun.ClusterData.JobType
+	ret := &JobRunRiskAnalysis{
+		ProwJobName:    jobRun.ProwJob.Name,
+		ProwJobRunID:   jobRun.ID,
+		Release:        jobType.Release,
+		CompareRelease: jobType.Release,
+		Tests:          []TestRiskAnalysis{},
+		OverallRisk:    RiskAnalysis{Level: RiskLevelNone, Reasons: []string{}},
+		OpenBugs:       []Bug{},
+	}
+
+	failedTests := []string{}
+	for _, test := range jobRun.Tests {
+		if test.Status == sippyFailureStatus {
+			failedTests = append(failedTests, test.Test.Name)
+		}
+	}
+	sort.Strings(failedTests)
+
+	if len(failedTests) == 0 {
+		ret.OverallRisk.Reasons = append(ret.OverallRisk.Reasons, "No test failures")
+		return ret
+	}
+	if len(failedTests) > maxFailuresToAnalyze {
+		ret.OverallRisk = RiskAnalysis{
+			Level:   RiskLevelHigh,
+			Reasons: []string{fmt.Sprintf("Maximum failed tests exceeded: %d > %d", len(failedTests), maxFailuresToAnalyze)},
+		}
+		return ret
+	}
+
+	unknownTests := 0
+	for _, testName := range failedTests {
+		testRisk := TestRiskAnalysis{Name: testName, OpenBugs: []Bug{}}
+		passRate, ok := passRates.bestMatch(testName, jobType)
+		switch {
+		case !ok:
+			unknownTests++
+			testRisk.Risk = RiskAnalysis{
+				Level:   RiskLevelUnknown,
+				Reasons: []string{fmt.Sprintf("Fewer than %d historical runs found for similar jobs", minRunsForRisk)},
+			}
+		default:
+			if passRate.Release != jobType.Release {
+				ret.CompareRelease = passRate.Release
+			}
+			testRisk.Risk = riskForPassRate(passRate)
+		}
+		ret.Tests = append(ret.Tests, testRisk)
+
+		if testRisk.Risk.Level.Level > ret.OverallRisk.Level.Level {
+			ret.OverallRisk.Level = testRisk.Risk.Level
+		}
+		if testRisk.Risk.Level.Level >= RiskLevelMedium.Level {
+			ret.OverallRisk.Reasons = append(ret.OverallRisk.Reasons,
+				fmt.Sprintf("Test %q is at %s risk: %s", testName, testRisk.Risk.Level.Name, testRisk.Risk.Reasons[0]))
+		}
+	}
+	switch {
+	case len(ret.OverallRisk.Reasons) > 0:
+	case unknownTests == len(failedTests):
+		ret.OverallRisk.Reasons = append(ret.OverallRisk.Reasons,
+			fmt.Sprintf("Not enough history to analyze the failed tests, each has fewer than %d historical runs on similar jobs", minRunsForRisk))
+	case unknownTests > 0:
+		ret.OverallRisk.Reasons = append(ret.OverallRisk.Reasons,
+			fmt.Sprintf("%d of %d failed tests have fewer than %d historical runs on similar jobs, the others commonly fail on similar jobs", unknownTests, len(failedTests), minRunsForRisk))
+	default:
+		ret.OverallRisk.Reasons = append(ret.OverallRisk.Reasons, "All failed tests commonly fail on similar jobs")
+	}
+
+	return ret
+}
+
+func riskForPassRate(passRate TestPassRate) RiskAnalysis {
+	passPercentage := passRate.passPercentage()
+	reason := fmt.Sprintf("This test has passed %.2f%% of %d runs on release %s %v in the historical data.",
+		passPercentage, passRate.runs(), passRate.Release, []string{passRate.Platform, passRate.Architecture, passRate.Network, passRate.Topology})
+
+	level := RiskLevelLow
+	switch {
+	case passPercentage >= 98:
+		level = RiskLevelHigh
+	case passPercentage >= 80:
+		level = RiskLevelMedium
+	}
+	return RiskAnalysis{Level: level, Reasons: []string{reason}}
+}
@@ -0,0 +1,134 @@
+package riskanalysis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeJobRun(t *testing.T) {
+	jobType := platformidentification.JobType{
+		Release:      "4.16",
+		FromRelease:  "4.16",
+		Platform:     "aws",
+		Architecture: "amd64",
+		Network:      "ovn",
+		Topology:     "ha",
+	}
+	previousJobType := platformidentification.CloneJobType(jobType)
+	previousJobType.Release = "4.15"
+	previousJobType.FromRelease = "4.15"
+
+	passRates := NewPassRateData([]TestPassRate{
+		{TestName: "reliable", JobType: jobType, Passes: 99, Failures: 1},
+		{TestName: "mostly-reliable", JobType: jobType, Passes: 80, Failures: 10, Flakes: 10},
+		{TestName: "flaky", JobType: jobType, Passes: 30, Failures: 70},
+		{TestName: "too-few-runs", JobType: jobType, Passes: 5},
+		// the same test twice is summed.
+		{TestName: "previous-release", JobType: previousJobType, Passes: 50},
+		{TestName: "previous-release", JobType: previousJobType, Passes: 50},
+	})
+
+	jobRunWithFailures := func(testNames ...string) *ProwJobRun {
+		ret := &ProwJobRun{
+			ID:          1234,
+			ProwJob:     ProwJob{Name: "periodic-ci-e2e-aws"},
+			ClusterData: platformidentification.ClusterData{JobType: jobType},
+		}
+		for _, testName := range testNames {
+			ret.Tests = append(ret.Tests, ProwJobRunTest{Test: Test{Name: testName}, Status: sippyFailureStatus})
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name                   string
+		jobRun                 *ProwJobRun
+		expectedOverall        RiskLevel
+		expectedReason         string
+		expectedTestRisks      map[string]RiskLevel
+		expectedCompareRelease string
+	}{
+		{
+			name:                   "no failures",
+			jobRun:                 jobRunWithFailures(),
+			expectedOverall:        RiskLevelNone,
+			expectedTestRisks:      map[string]RiskLevel{},
+			expectedCompareRelease: "4.16",
+		},
+		{
+			name:                   "flaky test only",
+			jobRun:                 jobRunWithFailures("flaky"),
+			expectedOverall:        RiskLevelLow,
+			expectedReason:         "All failed tests commonly fail on similar jobs",
+			expectedTestRisks:      map[string]RiskLevel{"flaky": RiskLevelLow},
+			expectedCompareRelease: "4.16",
+		},
+		{
+			name:                   "no history",
+			jobRun:                 jobRunWithFailures("too-few-runs", "unknown"),
+			expectedOverall:        RiskLevelUnknown,
+			expectedReason:         "Not enough history to analyze the failed tests, each has fewer than 7 historical runs on similar jobs",
+			expectedTestRisks:      map[string]RiskLevel{"too-few-runs": RiskLevelUnknown, "unknown": RiskLevelUnknown},
+			expectedCompareRelease: "4.16",
+		},
+		{
+			name:                   "flaky test and no history",
+			jobRun:                 jobRunWithFailures("flaky", "unknown"),
+			expectedOverall:        RiskLevelUnknown,
+			expectedReason:         "1 of 2 failed tests have fewer than 7 historical runs on similar jobs, the others commonly fail on similar jobs",
+			expectedTestRisks:      map[string]RiskLevel{"flaky": RiskLevelLow, "unknown": RiskLevelUnknown},
+			expectedCompareRelease: "4.16",
+		},
+		{
+			name:            "reliable test dominates",
+			jobRun:          jobRunWithFailures("flaky", "mostly-reliable", "reliable", "too-few-runs", "unknown"),
+			expectedOverall: RiskLevelHigh,
+			expectedTestRisks: map[string]RiskLevel{
+				"flaky":           RiskLevelLow,
+				"mostly-reliable": RiskLevelMedium,
+				"reliable":        RiskLevelHigh,
+				"too-few-runs":    RiskLevelUnknown,
+				"unknown":         RiskLevelUnknown,
+			},
+			expectedCompareRelease: "4.16",
+		},
+		{
+			name:                   "previous release data",
+			jobRun:                 jobRunWithFailures("previous-release"),
+			expectedOverall:        RiskLevelHigh,
+			expectedTestRisks:      map[string]RiskLevel{"previous-release": RiskLevelHigh},
+			expectedCompareRelease: "4.15",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := AnalyzeJobRun(tt.jobRun, passRates)
+			assert.Equal(t, tt.expectedOverall, actual.OverallRisk.Level)
+			assert.NotEmpty(t, actual.OverallRisk.Reasons)
+			if len(tt.expectedReason) > 0 {
+				assert.Equal(t, []string{tt.expectedReason}, actual.OverallRisk.Reasons)
+			}
+			assert.Equal(t, tt.expectedCompareRelease, actual.CompareRelease)
+			actualTestRisks := map[string]RiskLevel{}
+			for _, test := range actual.Tests {
+				actualTestRisks[test.Name] = test.Risk.Level
+				assert.NotEmpty(t, test.Risk.Reasons)
+			}
+			assert.Equal(t, tt.expectedTestRisks, actualTestRisks)
+		})
+	}
+}
+
+func TestAnalyzeJobRunTooManyFailures(t *testing.T) {
+	jobRun := &ProwJobRun{}
+	for i := 0; i <= maxFailuresToAnalyze; i++ {
+		jobRun.Tests = append(jobRun.Tests, ProwJobRunTest{Test: Test{Name: fmt.Sprintf("test-%d", i)}, Status: sippyFailureStatus})
+	}
+	actual := AnalyzeJobRun(jobRun, NewPassRateData(nil))
+	require.Equal(t, RiskLevelHigh, actual.OverallRisk.Level)
+	assert.Empty(t, actual.Tests)
+}
@@ -94,3 +94,62 @@ func getSippyStatusCode(pf *passFail) int {
 	// we should not hit this given the above filtering
 	return 0
 }
+
+// MergeProwJobRuns combines the test failure summaries written by several invocations of openshift-tests in the
+// same job run, for instance pre and post upgrade, or each shard of a sharded run.
+func MergeProwJobRuns(prowJobRuns []*ProwJobRun) (*ProwJobRun, error) {
+	var finalProwJobRun *ProwJobRun
+	for _, pjr := range prowJobRuns {
+		if finalProwJobRun == nil {
+			finalProwJobRun = pjr
+			continue
+		}
+		if pjr.ProwJob.Name != finalProwJobRun.ProwJob.Name {
+			return nil, fmt.Errorf("mismatched job names found in %s files, %s != %s",
+				testFailureSummaryFilePrefix, finalProwJobRun.ProwJob.Name, pjr.ProwJob.Name)
+		}
+		finalProwJobRun.Tests = append(finalProwJobRun.Tests, pjr.Tests...)
+		finalProwJobRun.TestCount += pjr.TestCount
+	}
+	if finalProwJobRun == nil {
+		return nil, fmt.Errorf("no %s files to merge", testFailureSummaryFilePrefix)
+	}
+	return finalProwJobRun, nil
+}
+
+// MergeTestFailureSummaries merges every test failure summary found in dirs into a single summary in outputDir,
+// so a later risk analysis of outputDir covers all of them.
+func MergeTestFailureSummaries(dirs []string, outputDir string) error {
+	prowJobRuns := []*ProwJobRun{}
+	for _, dir := range dirs {
+		resultFiles, err := filepath.Glob(filepath.Join(dir, testFailureSummaryFilePrefix+"*.json"))
+		if err != nil {
+			return err
+		}
+		for _, resultFile := range resultFiles {
+			data, err := os.ReadFile(resultFile)
+			if err != nil {
+				return err
+			}
+			jobRun := &ProwJobRun{}
+			if err := json.Unmarshal(data, jobRun); err != nil {
+				return fmt.Errorf("error unmarshalling %s: %w", resultFile, err)
+			}
+			prowJobRuns = append(prowJobRuns, jobRun)
+		}
+	}
+	if len(prowJobRuns) == 0 {
+		return nil
+	}
+
+	finalProwJobRun, err := MergeProwJobRuns(prowJobRuns)
+	if err != nil {
+		return err
+	}
+	jsonContent, err := json.MarshalIndent(finalProwJobRun, "", "    ")
+	if err != nil {
+		return err
+	}
+	outputFile := filepath.Join(outputDir, fmt.Sprintf("%s_merged.json", testFailureSummaryFilePrefix))
+	return ioutil.WriteFile(outputFile, jsonContent, 0644)
+}
@@ -10,6 +10,7 @@ import (
 	"os/signal"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -24,8 +25,11 @@ import (
 	"github.com/openshift/origin/pkg/monitor"
 	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
 	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/alertpolicy"
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
 	"github.com/openshift/origin/pkg/riskanalysis"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"github.com/openshift/origin/test/extended/util/annotate/brokentests"
 	"github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -71,11 +75,52 @@ type GinkgoRunSuiteOptions struct {
 
 	ExactMonitorTests   []string
 	DisableMonitorTests []string
+
+	// MonitorIntervalStorageDir, when set, bounds monitor memory by spilling recorded intervals to segments on disk.
+	MonitorIntervalStorageDir string
+
+	// HistoricalDataSource is where allowed disruption and alert thresholds are read from instead of the data
+	// embedded in the binary.  See historicaldata.NewSourceFromString for the accepted values.
+	HistoricalDataSource string
+	// HistoricalDataFallbacks is the fallback chain used when the job type has too little historical data.
+	// See historicaldata.ParseFallbackChain for the accepted values.
+	HistoricalDataFallbacks []string
+	// HistoricalDataBlend combines the historical data of neighbouring job types weighted by their job runs.
+	HistoricalDataBlend bool
+
+	// ShardIndex and ShardCount split the suite across several invocations.  Each invocation runs only the tests
+	// assigned to ShardIndex, see shardTests.
+	ShardIndex int
+	ShardCount int
+	// TestDurationsFrom lists duration files, junit files, or junit directories from previous runs, see
+	// loadTestDurations.  The durations balance the shards and start long tests first.
+	TestDurationsFrom []string
+
+	// ResumeFrom is the junit dir of an interrupted run.  Tests with a result there are not run again, see
+	// loadPreviousTestResults.
+	ResumeFrom string
+
+	// AlertPolicyFile is a yaml or json AlertPolicy with allowed alerts and alert tests applied on top of the
+	// built-in ones.
+	AlertPolicyFile string
+
+	// QuarantineFile is the state written by a previous run, or a hand written list of tests, see
+	// loadTestQuarantine.  QuarantineFrom lists junit dirs of previous runs to build the state from.  Quarantined
+	// tests run and are reported in their own junit, but they do not fail the suite.
+	QuarantineFile               string
+	QuarantineFrom               []string
+	QuarantineFlakeRateThreshold float64
+	QuarantineWindow             int
 }
 
 func NewGinkgoRunSuiteOptions(streams genericclioptions.IOStreams) *GinkgoRunSuiteOptions {
 	return &GinkgoRunSuiteOptions{
-		IOStreams: streams,
+		IOStreams:               streams,
+		HistoricalDataFallbacks: []string{historicaldata.DefaultMatchConfig.Chain.String()},
+		ShardCount:              1,
+
+		QuarantineFlakeRateThreshold: defaultQuarantineFlakeRate,
+		QuarantineWindow:             defaultQuarantineWindow,
 	}
 }
 
@@ -95,6 +140,29 @@ func (o *GinkgoRunSuiteOptions) BindFlags(flags *pflag.FlagSet) {
 	flags.StringSliceVar(&o.ExactMonitorTests, "monitor", o.ExactMonitorTests,
 		fmt.Sprintf("list of exactly which monitors to enable. All others will be disabled.  Current monitors are: [%s]", strings.Join(monitorNames, ", ")))
 	flags.StringSliceVar(&o.DisableMonitorTests, "disable-monitor", o.DisableMonitorTests, "list of monitors to disable.  Defaults for others will be honored.")
+	flags.StringVar(&o.MonitorIntervalStorageDir, "monitor-interval-storage-dir", o.MonitorIntervalStorageDir, "A directory to spill monitor intervals to so memory stays bounded on long runs. Empty keeps all intervals in memory.")
+	flags.StringVar(&o.HistoricalDataSource, "historical-data-source", o.HistoricalDataSource,
+		"Where to read allowed disruption and alert thresholds from: 'embedded' (the default), an http(s) URL or directory serving disruptions.json and alerts.json, or a file holding both as \"disruptions\" and \"alerts\". Types missing from the source use the embedded data.")
+	flags.StringSliceVar(&o.HistoricalDataFallbacks, "historical-data-fallback", o.HistoricalDataFallbacks,
+		"Ordered fallbacks to try when the job type has too little historical data. Each is previous-release, network, topology, or architecture, or several joined by + to relax them together, for instance previous-release,network,network+topology.")
+	flags.BoolVar(&o.HistoricalDataBlend, "historical-data-blend", o.HistoricalDataBlend,
+		"When a fallback finds several neighbouring job types, blend their historical data weighted by job runs instead of using the one with the most runs.")
+	flags.IntVar(&o.ShardIndex, "shard-index", o.ShardIndex, "The zero based shard of the suite to run when --shard-count is greater than 1.")
+	flags.IntVar(&o.ShardCount, "shard-count", o.ShardCount,
+		"Split the parallel and serial tests of the suite into this many shards and run only --shard-index, [Early] and [Late] tests run on every shard. Every shard must be run with the same suite, --shard-count, and --test-durations-from so the shards do not overlap. Use merge-results to combine the shard results.")
+	flags.StringSliceVar(&o.TestDurationsFrom, "test-durations-from", o.TestDurationsFrom,
+		"Historical test durations: .json files mapping test name to seconds, junit files, or directories containing junit*.xml files from a previous run. When set, the longest tests in each group start first and shards are balanced by duration; tests without a duration are estimated.")
+	flags.StringVar(&o.ResumeFrom, "resume-from", o.ResumeFrom,
+		"The --junit-dir of an interrupted run of the same suite. Tests that already passed, failed, or flaked there are not run again and their results are included in the final junit and failure summary.")
+	flags.StringVar(&o.AlertPolicyFile, "alert-policy", o.AlertPolicyFile,
+		"A yaml or json AlertPolicy file listing allowed alerts and alert tests, with job type scoping, bugs, and expiry dates, applied on top of the built-in alert allowances.")
+	flags.StringVar(&o.QuarantineFile, "quarantine-file", o.QuarantineFile,
+		"A yaml or json quarantine state written to --junit-dir by a previous run, or a hand written list of tests under \"tests\" with \"manual: true\". Quarantined tests still run, are reported in a separate junit, and do not fail the suite.")
+	flags.StringSliceVar(&o.QuarantineFrom, "quarantine-from", o.QuarantineFrom,
+		"The --junit-dir of previous runs of the suite, oldest first, to build the quarantine from. Each directory is one run.")
+	flags.Float64Var(&o.QuarantineFlakeRateThreshold, "quarantine-flake-rate", o.QuarantineFlakeRateThreshold,
+		fmt.Sprintf("Quarantine tests that flaked, both passed and failed in the same run, in at least this share of their recent runs, once they ran %d times. Tests whose latest run failed are not quarantined.", quarantineMinRuns))
+	flags.IntVar(&o.QuarantineWindow, "quarantine-window", o.QuarantineWindow, "How many recent runs of each test the quarantine flake rate is computed over.")
 }
 
 func (o *GinkgoRunSuiteOptions) Validate() error {
@@ -103,6 +171,18 @@ func (o *GinkgoRunSuiteOptions) Validate() error {
 	default:
 		return fmt.Errorf("unknown --cluster-stability, %q, expected Stable or Disruptive", o.ClusterStabilityDuringTest)
 	}
+	if o.ShardCount < 1 {
+		return fmt.Errorf("--shard-count must be at least 1, got %d", o.ShardCount)
+	}
+	if o.ShardIndex < 0 || o.ShardIndex >= o.ShardCount {
+		return fmt.Errorf("--shard-index must be between 0 and %d, got %d", o.ShardCount-1, o.ShardIndex)
+	}
+	if o.QuarantineFlakeRateThreshold <= 0 || o.QuarantineFlakeRateThreshold > 1 {
+		return fmt.Errorf("--quarantine-flake-rate must be greater than 0 and at most 1, got %v", o.QuarantineFlakeRateThreshold)
+	}
+	if o.QuarantineWindow < quarantineMinRuns {
+		return fmt.Errorf("--quarantine-window must be at least %d, got %d", quarantineMinRuns, o.QuarantineWindow)
+	}
 	return nil
 }
 
@@ -127,6 +207,43 @@ func max(a, b int) int {
 func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, monitorTestInfo monitortestframework.MonitorTestInitializationInfo, upgrade bool) error {
 	ctx := context.Background()
 
+	if err := o.Validate(); err != nil {
+		return err
+	}
+
+	fallbackChain, err := historicaldata.ParseFallbackChain(o.HistoricalDataFallbacks)
+	if err != nil {
+		return fmt.Errorf("invalid --historical-data-fallback: %w", err)
+	}
+	historicaldata.SetMatchConfig(historicaldata.MatchConfig{Chain: fallbackChain, Blend: o.HistoricalDataBlend})
+
+	if len(o.HistoricalDataSource) > 0 {
+		historicalDataSource, err := historicaldata.LoadSource(o.HistoricalDataSource)
+		if err != nil {
+			return fmt.Errorf("unable to load --historical-data-source: %w", err)
+		}
+		historicaldata.SetSource(historicalDataSource)
+		fmt.Fprintf(o.Out, "Using historical data from %v\n", historicalDataSource)
+	}
+
+	if len(o.AlertPolicyFile) > 0 {
+		alertPolicy, err := alertpolicy.ReadPolicyFile(o.AlertPolicyFile)
+		if err != nil {
+			return fmt.Errorf("unable to load --alert-policy: %w", err)
+		}
+		alertpolicy.SetPolicy(alertPolicy)
+		fmt.Fprintf(o.Out, "Using %d allowed alerts and %d alert tests from %s\n", len(alertPolicy.AllowedAlerts), len(alertPolicy.AlertTests), o.AlertPolicyFile)
+	}
+
+	var quarantine *testQuarantine
+	if len(o.QuarantineFile) > 0 || len(o.QuarantineFrom) > 0 {
+		quarantine, err = loadTestQuarantine(o.QuarantineFile, o.QuarantineFrom, o.QuarantineFlakeRateThreshold, o.QuarantineWindow)
+		if err != nil {
+			return fmt.Errorf("unable to load the test quarantine: %w", err)
+		}
+		fmt.Fprintf(o.Out, "%d tests are quarantined\n", len(quarantine.QuarantinedNames()))
+	}
+
 	tests, err := testsForSuite()
 	if err != nil {
 		return fmt.Errorf("failed reading origin test suites: %w", err)
@@ -134,6 +251,12 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 
 	fmt.Fprintf(o.Out, "found %d tests for suite\n", len(tests))
 
+	// the disabled tests are still in the list, so a broken test exception that matches none of them is stale.
+	brokenTestExceptions, err := brokentests.Registry(testNames(tests))
+	if err != nil {
+		return fmt.Errorf("invalid broken test exceptions: %w", err)
+	}
+
 	var fallbackSyntheticTestResult []*junitapi.JUnitTestCase
 	// OPENSHIFT_SKIP_EXTERNAL_TESTS env variable allows to skip using external binary
 	// in a similar fashion when --from-repository flag is specified when invoking tests
@@ -197,6 +320,34 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 
 	fmt.Fprintf(o.Out, "found %d filtered tests\n", len(tests))
 
+	var testDurations map[string]time.Duration
+	if len(o.TestDurationsFrom) > 0 {
+		testDurations, err = loadTestDurations(o.TestDurationsFrom)
+		if err != nil {
+			return fmt.Errorf("unable to read --test-durations-from: %w", err)
+		}
+		fmt.Fprintf(o.Out, "read historical durations for %d tests\n", len(testDurations))
+	}
+
+	if o.ShardCount > 1 {
+		tests = shardTests(tests, o.ShardIndex, o.ShardCount, testDurations)
+		if len(tests) == 0 {
+			return fmt.Errorf("shard %d of %d of suite %q does not contain any tests", o.ShardIndex, o.ShardCount, suite.Name)
+		}
+		fmt.Fprintf(o.Out, "running %d tests in shard %d of %d\n", len(tests), o.ShardIndex, o.ShardCount)
+	}
+
+	var resumedTests []*testCase
+	var previousResults map[string]testResultRecord
+	if len(o.ResumeFrom) > 0 {
+		previousResults, err = loadPreviousTestResults(o.ResumeFrom)
+		if err != nil {
+			return fmt.Errorf("unable to read --resume-from: %w", err)
+		}
+		resumedTests, tests = resumeTests(tests, previousResults)
+		fmt.Fprintf(o.Out, "resuming from %s: %d tests already have results, %d tests remain\n", o.ResumeFrom, len(resumedTests), len(tests))
+	}
+
 	count := o.Count
 	if count == 0 {
 		count = suite.Count
@@ -218,7 +369,7 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	testRunnerContext := newCommandContext(o.AsEnv(), timeout)
 
 	if o.PrintCommands {
-		newParallelTestQueue(testRunnerContext).OutputCommands(ctx, tests, o.Out)
+		newParallelTestQueue(testRunnerContext, testDurations).OutputCommands(ctx, tests, o.Out)
 		return nil
 	}
 	if o.DryRun {
@@ -283,6 +434,12 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	}
 
 	monitorEventRecorder := monitor.NewRecorder()
+	if len(o.MonitorIntervalStorageDir) > 0 {
+		monitorEventRecorder, err = monitor.NewSegmentedRecorder(o.MonitorIntervalStorageDir, monitor.DefaultSegmentSize)
+		if err != nil {
+			return err
+		}
+	}
 	m := monitor.NewMonitor(
 		monitorEventRecorder,
 		restConfig,
@@ -306,8 +463,24 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	if len(tests) == 1 && count == 1 {
 		includeSuccess = true
 	}
+	var testResults *testResultWriter
+	if len(o.JUnitDir) > 0 {
+		filename := fmt.Sprintf("%s_%s.jsonl", testResultsFilePrefix, o.StartTime.UTC().Format("20060102-150405"))
+		testResults, err = newTestResultWriter(filepath.Join(o.JUnitDir, filename))
+		if err != nil {
+			return fmt.Errorf("unable to record test results: %w", err)
+		}
+		defer testResults.Close()
+		// carry the resumed results forward so this run can be resumed in turn.
+		for _, test := range resumedTests {
+			if err := testResults.Write(previousResults[test.name]); err != nil {
+				return fmt.Errorf("unable to record test results: %w", err)
+			}
+		}
+	}
+
 	testOutputLock := &sync.Mutex{}
-	testOutputConfig := newTestOutputConfig(testOutputLock, o.Out, monitorEventRecorder, includeSuccess)
+	testOutputConfig := newTestOutputConfig(testOutputLock, o.Out, monitorEventRecorder, testResults, includeSuccess)
 
 	early, notEarly := splitTests(tests, func(t *testCase) bool {
 		return strings.Contains(t.name, "[Early]")
@@ -355,7 +528,7 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	tests = nil
 
 	// run our Early tests
-	q := newParallelTestQueue(testRunnerContext)
+	q := newParallelTestQueue(testRunnerContext, testDurations)
 	q.Execute(testCtx, early, parallelism, testOutputConfig, abortFn)
 	tests = append(tests, early...)
 
@@ -391,6 +564,19 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	q.Execute(testCtx, late, parallelism, testOutputConfig, abortFn)
 	tests = append(tests, late...)
 
+	// tests that finished in the run we resumed from are reported as if they ran now.  They are not run again, a
+	// failure that was retried before the run was interrupted already has its retry in the result.
+	tests = append(tests, resumedTests...)
+	isResumed := map[*testCase]bool{}
+	for _, test := range resumedTests {
+		isResumed[test] = true
+	}
+
+	if predicted, actual := q.WallClock(); predicted > 0 {
+		fmt.Fprintf(o.Out, "Test wall clock: predicted %s from historical durations, actual %s\n",
+			predicted.Round(time.Second), actual.Round(time.Second))
+	}
+
 	// TODO: will move to the monitor
 	if len(o.JUnitDir) > 0 {
 		pc.ComputePodTransitions()
@@ -417,6 +603,9 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		duration = duration.Round(time.Second)
 	}
 
+	// quarantined tests are reported on their own and their failures don't count.
+	quarantinedTests, tests := splitTests(tests, func(t *testCase) bool { return quarantine.IsQuarantined(t.name) })
+
 	pass, fail, skip, failing := summarizeTests(tests)
 
 	// attempt to retry failures to do flake detection
@@ -426,6 +615,9 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		// Make a copy of the all failing tests (subject to the max allowed flakes) so we can have
 		// a list of tests to retry.
 		for _, test := range failing {
+			if isResumed[test] {
+				continue
+			}
 			retry := test.Retry()
 			retries = append(retries, retry)
 			if len(retries) > suite.MaximumAllowedFlakes {
@@ -436,7 +628,7 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		fmt.Fprintf(o.Out, "Retry count: %d\n", len(retries))
 
 		// Run the tests in the retries list.
-		q := newParallelTestQueue(testRunnerContext)
+		q := newParallelTestQueue(testRunnerContext, nil)
 		q.Execute(testCtx, retries, parallelism, testOutputConfig, abortFn)
 
 		var flaky, skipped []string
@@ -486,6 +678,27 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		}
 	}
 
+	if quarantine != nil {
+		// retry the failing quarantined tests once, so the quarantine tells a flake apart from a failure.
+		var quarantineRetries []*testCase
+		for _, test := range quarantinedTests {
+			if test.failed && !isResumed[test] {
+				quarantineRetries = append(quarantineRetries, test.Retry())
+			}
+		}
+		if len(quarantineRetries) > 0 {
+			fmt.Fprintf(o.Out, "Quarantined retry count: %d\n", len(quarantineRetries))
+			q := newParallelTestQueue(testRunnerContext, nil)
+			q.Execute(testCtx, quarantineRetries, parallelism, testOutputConfig, abortFn)
+			quarantinedTests = append(quarantinedTests, quarantineRetries...)
+		}
+
+		var ran []*testCase
+		ran = append(ran, tests...)
+		ran = append(ran, quarantinedTests...)
+		quarantine.Record(ran)
+	}
+
 	// Fetch data from in-cluster monitors if available
 	if err = sampler.TearDownInClusterMonitors(restConfig); err != nil {
 		fmt.Printf("Failed to write events from in-cluster monitors, err: %v\n", err)
@@ -497,6 +710,10 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 
 	timeSuffix := fmt.Sprintf("_%s", start.UTC().Format("20060102-150405"))
 
+	// the monitor tests link intervals to the failures of the e2e tests as well as their own.
+	m.AddTestJUnits(generateJUnitTestSuiteResults(junitSuiteName, duration, tests).TestCases...)
+	m.AddTestJUnits(generateJUnitTestSuiteResults(junitSuiteName, duration, quarantinedTests).TestCases...)
+
 	monitorTestResultState, err := m.Stop(ctx)
 	if err != nil {
 		fmt.Fprintf(o.ErrOut, "error: Failed to stop monitor test: %v\n", err)
@@ -515,6 +732,7 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 			// tests, so don't report information there at all
 			syntheticTestResults = append(syntheticTestResults, fallbackSyntheticTestResult...)
 		}
+		syntheticTestResults = append(syntheticTestResults, brokenTestExceptions.JUnits(time.Now())...)
 
 		if len(syntheticTestResults) > 0 {
 			// mark any failures by name
@@ -564,9 +782,26 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		names := sets.NewString(testNames(failing)...).List()
 		fmt.Fprintf(o.Out, "Failing tests:\n\n%s\n\n", strings.Join(names, "\n"))
 	}
+	if _, quarantinedFail, _, quarantinedFailing := summarizeTests(quarantinedTests); quarantinedFail > 0 {
+		names := sets.NewString(testNames(quarantinedFailing)...)
+		for _, test := range quarantinedTests {
+			if test.success {
+				names.Delete(test.name)
+			}
+		}
+		if names.Len() > 0 {
+			fmt.Fprintf(o.Out, "Failing quarantined tests, ignored:\n\n%s\n\n", strings.Join(names.List(), "\n"))
+		}
+	}
 
 	if len(o.JUnitDir) > 0 {
 		finalSuiteResults := generateJUnitTestSuiteResults(junitSuiteName, duration, tests, syntheticTestResults...)
+		if o.ShardCount > 1 {
+			finalSuiteResults.Properties = append(finalSuiteResults.Properties,
+				&junitapi.TestSuiteProperty{Name: "ShardIndex", Value: strconv.Itoa(o.ShardIndex)},
+				&junitapi.TestSuiteProperty{Name: "ShardCount", Value: strconv.Itoa(o.ShardCount)},
+			)
+		}
 		if err := writeJUnitReport(finalSuiteResults, "junit_e2e", timeSuffix, o.JUnitDir, o.ErrOut); err != nil {
 			fmt.Fprintf(o.Out, "error: Unable to write e2e JUnit xml results: %v", err)
 		}
@@ -574,24 +809,67 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		if err := riskanalysis.WriteJobRunTestFailureSummary(o.JUnitDir, timeSuffix, finalSuiteResults, wasMasterNodeUpdated, ""); err != nil {
 			fmt.Fprintf(o.Out, "error: Unable to write e2e job run failures summary: %v", err)
 		}
+
+		if quarantine != nil {
+			quarantinedSuiteResults := generateJUnitTestSuiteResults(junitSuiteName+"-quarantined", duration, quarantinedTests)
+			if err := writeJUnitReport(quarantinedSuiteResults, "junit_quarantine", timeSuffix, o.JUnitDir, o.ErrOut); err != nil {
+				fmt.Fprintf(o.Out, "error: Unable to write quarantined JUnit xml results: %v", err)
+			}
+			path, err := quarantine.Write(o.JUnitDir, timeSuffix)
+			if err != nil {
+				fmt.Fprintf(o.Out, "error: Unable to write the test quarantine: %v", err)
+			} else {
+				fmt.Fprintf(o.Out, "Wrote the test quarantine for the next run to %s\n", path)
+			}
+		}
 	}
 
+	var runErr error
 	if fail > 0 {
 		if len(failing) > 0 || suite.MaximumAllowedFlakes == 0 {
-			return fmt.Errorf("%d fail, %d pass, %d skip (%s)", fail, pass, skip, duration)
+			runErr = fmt.Errorf("%d fail, %d pass, %d skip (%s)", fail, pass, skip, duration)
+		} else {
+			fmt.Fprintf(o.Out, "%d flakes detected, suite allows passing with only flakes\n\n", fail)
 		}
-		fmt.Fprintf(o.Out, "%d flakes detected, suite allows passing with only flakes\n\n", fail)
 	}
-
-	if syntheticFailure {
-		return fmt.Errorf("failed because an invariant was violated, %d pass, %d skip (%s)\n", pass, skip, duration)
+	switch {
+	case runErr != nil:
+	case syntheticFailure:
+		runErr = fmt.Errorf("failed because an invariant was violated, %d pass, %d skip (%s)\n", pass, skip, duration)
+	case monitorTestResultState != monitor.Succeeded:
+		runErr = fmt.Errorf("failed due to a MonitorTest failure")
+	default:
+		fmt.Fprintf(o.Out, "%d pass, %d skip (%s)\n", pass, skip, duration)
+		runErr = ctx.Err()
 	}
-	if monitorTestResultState != monitor.Succeeded {
-		return fmt.Errorf("failed due to a MonitorTest failure")
+
+	// the manifest is written last so it indexes every other artifact of the run.
+	if len(o.JUnitDir) > 0 {
+		manifest := &RunManifest{
+			APIVersion:   RunManifestAPIVersion,
+			Kind:         RunManifestKind,
+			Suite:        junitSuiteName,
+			Start:        start,
+			End:          end,
+			Result:       TestSucceeded,
+			Cluster:      clusterinfo.CollectClusterData(restConfig, wasMasterNodeUpdated),
+			Tests:        runManifestTests(tests, quarantinedTests, resumedTests, syntheticTestResults, junitReportFilename("junit_e2e", timeSuffix), junitReportFilename("junit_quarantine", timeSuffix)),
+			MonitorTests: runManifestMonitorTests(m.JUnits()),
+		}
+		if runErr != nil {
+			manifest.Result, manifest.Error = TestFailed, runErr.Error()
+		}
+		if o.ShardCount > 1 {
+			manifest.Shard = &RunManifestShard{Index: o.ShardIndex, Count: o.ShardCount}
+		}
+		if path, err := writeRunManifest(manifest, o.JUnitDir, timeSuffix); err != nil {
+			fmt.Fprintf(o.ErrOut, "error: Unable to write the run manifest: %v\n", err)
+		} else {
+			fmt.Fprintf(o.Out, "Wrote the run manifest to %s\n", path)
+		}
 	}
 
-	fmt.Fprintf(o.Out, "%d pass, %d skip (%s)\n", pass, skip, duration)
-	return ctx.Err()
+	return runErr
 }
 
 func (o *GinkgoRunSuiteOptions) filterOutRebaseTests(restConfig *rest.Config, tests []*testCase) ([]*testCase, error) {
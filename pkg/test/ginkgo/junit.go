@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -99,11 +100,15 @@ func writeJUnitReport(s *junitapi.JUnitTestSuite, filePrefix, fileSuffix, dir st
 	if err != nil {
 		return err
 	}
-	path := filepath.Join(dir, fmt.Sprintf("%s_%s.xml", filePrefix, fileSuffix))
+	path := filepath.Join(dir, junitReportFilename(filePrefix, fileSuffix))
 	fmt.Fprintf(errOut, "Writing JUnit report to %s\n\n", path)
 	return ioutil.WriteFile(path, test.StripANSI(out), 0640)
 }
 
+func junitReportFilename(filePrefix, fileSuffix string) string {
+	return fmt.Sprintf("%s_%s.xml", filePrefix, fileSuffix)
+}
+
 func lastLinesUntil(output string, max int, until ...string) string {
 	output = strings.TrimSpace(output)
 	index := len(output) - 1
@@ -136,3 +141,31 @@ func stringStartsWithAny(s string, contains []string) bool {
 	}
 	return false
 }
+
+// ReadJUnitSuites reads a junit file containing either a single testsuite or testsuites, and returns every suite
+// including nested ones.
+func ReadJUnitSuites(filename string) ([]*junitapi.JUnitTestSuite, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	suites := &junitapi.JUnitTestSuites{}
+	if err := xml.Unmarshal(data, suites); err == nil {
+		return flattenJUnitSuites(suites.Suites), nil
+	}
+	suite := &junitapi.JUnitTestSuite{}
+	if err := xml.Unmarshal(data, suite); err != nil {
+		return nil, fmt.Errorf("failed to decode junit %q: %w", filename, err)
+	}
+	return flattenJUnitSuites([]*junitapi.JUnitTestSuite{suite}), nil
+}
+
+func flattenJUnitSuites(suites []*junitapi.JUnitTestSuite) []*junitapi.JUnitTestSuite {
+	ret := []*junitapi.JUnitTestSuite{}
+	for _, suite := range suites {
+		ret = append(ret, suite)
+		ret = append(ret, flattenJUnitSuites(suite.Children)...)
+	}
+	return ret
+}
@@ -0,0 +1,211 @@
+package ginkgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// defaultQuarantineFlakeRate is the share of recent runs a test may flake in before it is quarantined.
+	defaultQuarantineFlakeRate = 0.1
+	// defaultQuarantineWindow is how many recent runs of each test the flake rate is computed over.
+	defaultQuarantineWindow = 10
+	// quarantineMinRuns is how many runs a test needs before its flake rate is trusted, so a single flake of a new
+	// test does not quarantine it.
+	quarantineMinRuns = 3
+
+	quarantineFilePrefix = "quarantine"
+)
+
+// quarantineResult is the outcome of a test in one run.
+type quarantineResult string
+
+const (
+	quarantinePassed quarantineResult = "passed"
+	quarantineFlaked quarantineResult = "flaked"
+	quarantineFailed quarantineResult = "failed"
+)
+
+// testQuarantine decides which tests are quarantined.  Quarantined tests still run, but they are reported in their
+// own junit and their failures do not fail the suite.  It is written at the end of every run with that run's
+// results added, so passing it to --quarantine-file of the next run carries the history forward.
+type testQuarantine struct {
+	// FlakeRateThreshold and Window are the settings the state was last computed with.  The flags of the run
+	// reading the state take precedence.
+	FlakeRateThreshold float64 `json:"flakeRateThreshold"`
+	Window             int     `json:"window"`
+
+	Tests map[string]*quarantineHistory `json:"tests"`
+}
+
+// quarantineHistory is the recent results of one test.
+type quarantineHistory struct {
+	// Manual tests are always quarantined, whatever their results.  Use it for tests added to the file by hand.
+	Manual bool `json:"manual,omitempty"`
+	// Reason explains a manual quarantine, usually with a link to the bug.
+	Reason string `json:"reason,omitempty"`
+	// Results are the outcomes of the most recent runs, oldest first.
+	Results []quarantineResult `json:"results,omitempty"`
+
+	FlakeRate   float64 `json:"flakeRate"`
+	Quarantined bool    `json:"quarantined"`
+}
+
+func newTestQuarantine(flakeRateThreshold float64, window int) *testQuarantine {
+	return &testQuarantine{
+		FlakeRateThreshold: flakeRateThreshold,
+		Window:             window,
+		Tests:              map[string]*quarantineHistory{},
+	}
+}
+
+// loadTestQuarantine builds the quarantine from the yaml or json state written by an earlier run, if filename is
+// set, followed by the junit dirs of previous runs, oldest first.  Each dir is one run of the suite.
+func loadTestQuarantine(filename string, junitDirs []string, flakeRateThreshold float64, window int) (*testQuarantine, error) {
+	q := newTestQuarantine(flakeRateThreshold, window)
+	if len(filename) > 0 {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.UnmarshalStrict(data, q); err != nil {
+			return nil, fmt.Errorf("failed to decode %q: %w", filename, err)
+		}
+		if q.Tests == nil {
+			q.Tests = map[string]*quarantineHistory{}
+		}
+		for name, history := range q.Tests {
+			if history == nil {
+				q.Tests[name] = &quarantineHistory{}
+			}
+		}
+		q.FlakeRateThreshold, q.Window = flakeRateThreshold, window
+	}
+
+	for _, dir := range junitDirs {
+		records, err := loadPreviousTestResults(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %q: %w", dir, err)
+		}
+		results := map[string]quarantineResult{}
+		for name, record := range records {
+			switch {
+			case record.State == TestFlaked:
+				results[name] = quarantineFlaked
+			case isTestFailed(record.State):
+				results[name] = quarantineFailed
+			case record.State == TestSucceeded:
+				results[name] = quarantinePassed
+			}
+		}
+		q.add(results)
+	}
+
+	q.recompute()
+	return q, nil
+}
+
+// IsQuarantined returns true if the failures of the test are not to fail the suite.
+func (q *testQuarantine) IsQuarantined(name string) bool {
+	if q == nil {
+		return false
+	}
+	history, ok := q.Tests[name]
+	return ok && history.Quarantined
+}
+
+// Record adds the outcome of each test in this run and recomputes which tests are quarantined.  A test that ran
+// more than once, because it was retried or --count is set, flaked if it both passed and failed.  Skipped tests
+// and tests that did not finish are left out.
+func (q *testQuarantine) Record(tests []*testCase) {
+	passed, failed := map[string]bool{}, map[string]bool{}
+	for _, test := range tests {
+		switch {
+		case test.flake:
+			passed[test.name] = true
+			failed[test.name] = true
+		case test.success:
+			passed[test.name] = true
+		case test.failed:
+			failed[test.name] = true
+		}
+	}
+
+	results := map[string]quarantineResult{}
+	for name := range passed {
+		results[name] = quarantinePassed
+	}
+	for name := range failed {
+		if passed[name] {
+			results[name] = quarantineFlaked
+		} else {
+			results[name] = quarantineFailed
+		}
+	}
+	q.add(results)
+	q.recompute()
+}
+
+// add appends one run's results to the history of each test, keeping only the most recent Window results.
+func (q *testQuarantine) add(results map[string]quarantineResult) {
+	for name, result := range results {
+		history, ok := q.Tests[name]
+		if !ok {
+			history = &quarantineHistory{}
+			q.Tests[name] = history
+		}
+		history.Results = append(history.Results, result)
+		if q.Window > 0 && len(history.Results) > q.Window {
+			history.Results = history.Results[len(history.Results)-q.Window:]
+		}
+	}
+}
+
+// recompute sets the flake rate of each test over its recent results.  Only flakes, runs where the test both
+// passed and failed, count toward the rate: a hard failure is a possible regression, not a flake.  A test is
+// quarantined once it has run at least quarantineMinRuns times and its flake rate reaches the threshold, unless its
+// latest result is a failure, which has to fail the suite so a regression is not hidden behind earlier flakes.
+func (q *testQuarantine) recompute() {
+	for _, history := range q.Tests {
+		var flaked int
+		for _, result := range history.Results {
+			if result == quarantineFlaked {
+				flaked++
+			}
+		}
+		history.FlakeRate = 0
+		if len(history.Results) > 0 {
+			history.FlakeRate = float64(flaked) / float64(len(history.Results))
+		}
+		latestFailed := len(history.Results) > 0 && history.Results[len(history.Results)-1] == quarantineFailed
+		history.Quarantined = history.Manual ||
+			(len(history.Results) >= quarantineMinRuns && !latestFailed && history.FlakeRate >= q.FlakeRateThreshold)
+	}
+}
+
+// QuarantinedNames returns the names of the quarantined tests, sorted.
+func (q *testQuarantine) QuarantinedNames() []string {
+	var names []string
+	for name, history := range q.Tests {
+		if history.Quarantined {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Write stores the quarantine state in dir, to be read with --quarantine-file by the next run.
+func (q *testQuarantine) Write(dir, fileSuffix string) (string, error) {
+	data, err := json.MarshalIndent(q, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s%s.json", quarantineFilePrefix, fileSuffix))
+	return path, os.WriteFile(path, data, 0644)
+}
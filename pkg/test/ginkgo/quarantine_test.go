@@ -0,0 +1,135 @@
+package ginkgo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_testQuarantine(t *testing.T) {
+	// three previous runs, oldest first.
+	junits := []string{
+		`<testsuites><testsuite name="openshift-tests">
+  <testcase name="flaky"><failure>fail</failure></testcase>
+  <testcase name="flaky"></testcase>
+  <testcase name="broken"><failure>fail</failure></testcase>
+  <testcase name="stable"></testcase>
+  <testcase name="new"><skipped message="skip"></skipped></testcase>
+</testsuite></testsuites>`,
+		`<testsuites><testsuite name="openshift-tests">
+  <testcase name="flaky"><failure>fail</failure></testcase>
+  <testcase name="flaky"></testcase>
+  <testcase name="broken"><failure>fail</failure></testcase>
+  <testcase name="stable"></testcase>
+</testsuite></testsuites>`,
+		`<testsuites><testsuite name="openshift-tests">
+  <testcase name="flaky"></testcase>
+  <testcase name="broken"><failure>fail</failure></testcase>
+  <testcase name="stable"></testcase>
+  <testcase name="new"><failure>fail</failure></testcase>
+</testsuite></testsuites>`,
+	}
+	var dirs []string
+	for _, junit := range junits {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "junit_e2e.xml"), []byte(junit), 0644); err != nil {
+			t.Fatal(err)
+		}
+		dirs = append(dirs, dir)
+	}
+
+	stateFile := filepath.Join(t.TempDir(), "quarantine.yaml")
+	state := `
+tests:
+  manual:
+    manual: true
+    reason: https://issues.redhat.com/browse/OCPBUGS-1
+`
+	if err := os.WriteFile(stateFile, []byte(state), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := loadTestQuarantine(stateFile, dirs, 0.5, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := q.QuarantinedNames(), []string{"flaky", "manual"}; !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v to be quarantined, got %v", want, got)
+	}
+	if got, want := q.Tests["flaky"].Results, []quarantineResult{quarantineFlaked, quarantineFlaked, quarantinePassed}; !reflect.DeepEqual(want, got) {
+		t.Errorf("expected flaky to have results %v, got %v", want, got)
+	}
+	if got := q.Tests["new"].Results; len(got) != 1 {
+		t.Errorf("expected the skip of new to be ignored, got %v", got)
+	}
+	if q.IsQuarantined("broken") {
+		t.Errorf("a test that fails without flaking is broken, it should not be quarantined")
+	}
+
+	// flaky passes twice in a row and drops out of the window, new reaches the minimum runs with a flake.
+	for i := 0; i < 2; i++ {
+		q.Record([]*testCase{
+			{name: "flaky", success: true},
+			{name: "new", failed: true},
+			{name: "new", success: true},
+			{name: "stable", skipped: true},
+			{name: "unfinished"},
+		})
+	}
+	if got, want := q.QuarantinedNames(), []string{"manual", "new"}; !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v to be quarantined, got %v", want, got)
+	}
+	if got, want := q.Tests["flaky"].Results, []quarantineResult{quarantineFlaked, quarantinePassed, quarantinePassed, quarantinePassed}; !reflect.DeepEqual(want, got) {
+		t.Errorf("expected flaky to keep the last 4 results %v, got %v", want, got)
+	}
+	if got := q.Tests["stable"].Results; len(got) != 3 {
+		t.Errorf("expected skips of stable to be ignored, got %v", got)
+	}
+	if _, ok := q.Tests["unfinished"]; ok {
+		t.Errorf("expected tests that did not finish to be ignored")
+	}
+
+	// the state written out is read back by the next run.
+	written, err := q.Write(t.TempDir(), "_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := loadTestQuarantine(written, nil, 0.5, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(q, next) {
+		t.Errorf("expected the written state to read back the same, got %#v", next)
+	}
+
+	// a flaky test that now fails outright may be a regression, it has to fail the suite.
+	q.Record([]*testCase{{name: "new", failed: true}})
+	if q.IsQuarantined("new") {
+		t.Errorf("expected a test whose latest run failed not to be quarantined")
+	}
+
+	if _, err := loadTestQuarantine(stateFile+".missing", nil, 0.5, 4); err == nil {
+		t.Errorf("expected an error for a missing state file")
+	}
+}
+
+func Test_testQuarantineFailureIsNotAFlake(t *testing.T) {
+	q := newTestQuarantine(defaultQuarantineFlakeRate, defaultQuarantineWindow)
+	for i := 0; i < 9; i++ {
+		q.Record([]*testCase{{name: "regressed", success: true}})
+	}
+	q.Record([]*testCase{{name: "regressed", failed: true}})
+	if q.IsQuarantined("regressed") {
+		t.Errorf("expected a failure after nine passes to fail the suite, not to be quarantined")
+	}
+	if rate := q.Tests["regressed"].FlakeRate; rate != 0 {
+		t.Errorf("expected a hard failure not to count as a flake, got a flake rate of %v", rate)
+	}
+
+	// one flake in ten runs reaches the default threshold.
+	q.Record([]*testCase{{name: "regressed", failed: true}, {name: "regressed", success: true}})
+	if !q.IsQuarantined("regressed") {
+		t.Errorf("expected a flake rate of %v to be quarantined", q.Tests["regressed"].FlakeRate)
+	}
+}
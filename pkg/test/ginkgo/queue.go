@@ -6,24 +6,38 @@ import (
 	"io"
 	"strings"
 	"sync"
+	"time"
 )
 
 // parallelByFileTestQueue runs tests in parallel unless they have
 // the `[Serial]` tag on their name or if another test with the
 // testExclusion field is currently running. Serial tests are
-// defered until all other tests are completed.
+// defered until all other tests are completed. When testDurations
+// is set, parallel tests are started longest first.
 type parallelByFileTestQueue struct {
 	commandContext *commandContext
+	testDurations  map[string]time.Duration
+
+	// predicted and actual accumulate the wall clock of every Execute when testDurations is set.
+	predicted time.Duration
+	actual    time.Duration
 }
 
 type TestFunc func(ctx context.Context, test *testCase)
 
-func newParallelTestQueue(commandContext *commandContext) *parallelByFileTestQueue {
+func newParallelTestQueue(commandContext *commandContext, testDurations map[string]time.Duration) *parallelByFileTestQueue {
 	return &parallelByFileTestQueue{
 		commandContext: commandContext,
+		testDurations:  testDurations,
 	}
 }
 
+// WallClock returns the wall clock predicted from testDurations and the actual wall clock of every Execute so far.
+// Both are zero when the queue has no testDurations.
+func (q *parallelByFileTestQueue) WallClock() (predicted, actual time.Duration) {
+	return q.predicted, q.actual
+}
+
 // OutputCommand prints to stdout what would have been executed.
 func (q *parallelByFileTestQueue) OutputCommands(ctx context.Context, tests []*testCase, out io.Writer) {
 	// for some reason we split the serial and parallel when printing the command
@@ -96,7 +110,17 @@ func (q *parallelByFileTestQueue) Execute(ctx context.Context, tests []*testCase
 		maybeAbortOnFailureFn: maybeAbortOnFailureFn,
 	}
 
+	if len(q.testDurations) == 0 {
+		execute(ctx, testSuiteRunner, tests, parallelism)
+		return
+	}
+
+	tests = longestFirst(tests, q.testDurations)
+	predicted := predictWallClock(tests, parallelism, q.testDurations)
+	start := time.Now()
 	execute(ctx, testSuiteRunner, tests, parallelism)
+	q.predicted += predicted
+	q.actual += time.Since(start)
 }
 
 // execute is a convenience for unit testing
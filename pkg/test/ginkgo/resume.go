@@ -0,0 +1,192 @@
+package ginkgo
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const testResultsFilePrefix = "test-results"
+
+// testResultRecord is one finished test.  Records are appended to a file as soon as each test finishes, so a run
+// that is interrupted before it writes its junit can still be resumed.
+type testResultRecord struct {
+	Name  string    `json:"name"`
+	State TestState `json:"state"`
+	// Start and End are zero for results read from a junit, which only has the duration.
+	Start           time.Time `json:"start"`
+	End             time.Time `json:"end"`
+	DurationSeconds float64   `json:"durationSeconds,omitempty"`
+	Output          string    `json:"output,omitempty"`
+}
+
+// testResultWriter appends testResultRecords as json lines.  A nil writer discards records.
+type testResultWriter struct {
+	lock sync.Mutex
+	file *os.File
+}
+
+func newTestResultWriter(filename string) (*testResultWriter, error) {
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &testResultWriter{file: file}, nil
+}
+
+// RecordTestResult writes the result of a test that ran to completion.  Tests cut short because ctx was cancelled
+// are reported as skipped, so they are left out to have them run again on resume.
+func (w *testResultWriter) RecordTestResult(ctx context.Context, result *testRunResult) error {
+	if w == nil || result == nil || ctx.Err() != nil {
+		return nil
+	}
+	return w.Write(testResultRecord{
+		Name:   result.name,
+		State:  result.testState,
+		Start:  result.start,
+		End:    result.end,
+		Output: string(result.testOutputBytes),
+	})
+}
+
+func (w *testResultWriter) Write(record testResultRecord) error {
+	if w == nil {
+		return nil
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	_, err = w.file.Write(append(data, '\n'))
+	return err
+}
+
+func (w *testResultWriter) Close() error {
+	if w == nil {
+		return nil
+	}
+	return w.file.Close()
+}
+
+// loadPreviousTestResults reads the results of an earlier run from its junit dir.  The junit*.xml files are read
+// first, then the test-results*.jsonl files, which win when a test is in both.  Skipped tests in the junit are
+// ignored because an interrupted run reports the tests it did not finish as skipped.
+func loadPreviousTestResults(dir string) (map[string]testResultRecord, error) {
+	ret := map[string]testResultRecord{}
+
+	junitFiles, err := filepath.Glob(filepath.Join(dir, "junit*.xml"))
+	if err != nil {
+		return nil, err
+	}
+	// a test that was retried is in the junit more than once, a pass and a failure is a flake.
+	passed, failed := map[string]bool{}, map[string]bool{}
+	for _, filename := range junitFiles {
+		suites, err := ReadJUnitSuites(filename)
+		if err != nil {
+			return nil, err
+		}
+		for _, suite := range suites {
+			for _, testCase := range suite.TestCases {
+				if testCase.SkipMessage != nil {
+					continue
+				}
+				record := ret[testCase.Name]
+				record.Name = testCase.Name
+				record.DurationSeconds = testCase.Duration
+				record.Output = testCase.SystemOut
+				if testCase.FailureOutput != nil {
+					failed[testCase.Name] = true
+					record.Output = testCase.FailureOutput.Output
+				} else {
+					passed[testCase.Name] = true
+				}
+				switch {
+				case passed[testCase.Name] && failed[testCase.Name]:
+					record.State = TestFlaked
+				case failed[testCase.Name]:
+					record.State = TestFailed
+				default:
+					record.State = TestSucceeded
+				}
+				ret[testCase.Name] = record
+			}
+		}
+	}
+
+	resultFiles, err := filepath.Glob(filepath.Join(dir, testResultsFilePrefix+"*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	for _, filename := range resultFiles {
+		if err := readTestResultRecords(filename, ret); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func readTestResultRecords(filename string, records map[string]testResultRecord) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	// test output can be large.
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		record := testResultRecord{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// the last line is cut short if the run was killed while writing it.
+			continue
+		}
+		switch record.State {
+		case TestSucceeded, TestFailed, TestFailedTimeout, TestFlaked, TestSkipped:
+		default:
+			continue
+		}
+		// a retry that passed after a failure is a flake, the same as in the junit.
+		if previous, ok := records[record.Name]; ok && record.State == TestSucceeded && isTestFailed(previous.State) {
+			record.State = TestFlaked
+		}
+		records[record.Name] = record
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %q: %w", filename, err)
+	}
+	return nil
+}
+
+// resumeTests splits tests into those with a previous result, which are updated with it, and those left to run.
+func resumeTests(tests []*testCase, previousResults map[string]testResultRecord) (resumed, remaining []*testCase) {
+	for _, test := range tests {
+		record, ok := previousResults[test.name]
+		if !ok {
+			remaining = append(remaining, test)
+			continue
+		}
+		mutateTestCaseWithResults(test, &testRunResultHandle{
+			testRunResult: &testRunResult{
+				name:            record.Name,
+				start:           record.Start,
+				end:             record.End,
+				testState:       record.State,
+				testOutputBytes: []byte(record.Output),
+			},
+		})
+		if record.Start.IsZero() {
+			test.duration = time.Duration(record.DurationSeconds * float64(time.Second)).Round(time.Second / 10)
+		}
+		resumed = append(resumed, test)
+	}
+	return resumed, remaining
+}
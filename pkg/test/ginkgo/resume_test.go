@@ -0,0 +1,104 @@
+package ginkgo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_resume(t *testing.T) {
+	dir := t.TempDir()
+
+	junit := `<testsuites>
+  <testsuite name="openshift-tests" tests="4" skipped="1" failures="1" time="10">
+    <testcase name="passed in junit" time="2"></testcase>
+    <testcase name="interrupted" time="0"><skipped message="skip"></skipped></testcase>
+    <testcase name="retried" time="3"><failure>fail</failure></testcase>
+    <testcase name="retried" time="3"></testcase>
+  </testsuite>
+</testsuites>`
+	if err := os.WriteFile(filepath.Join(dir, "junit_e2e.xml"), []byte(junit), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	writer, err := newTestResultWriter(filepath.Join(dir, testResultsFilePrefix+"_1.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	for _, result := range []*testRunResult{
+		{name: "failed", testState: TestFailed, start: start, end: start.Add(time.Minute), testOutputBytes: []byte("fail [output]")},
+		{name: "skipped by the test", testState: TestSkipped, start: start, end: start},
+		{name: "failed then passed", testState: TestFailed, start: start, end: start.Add(time.Second)},
+		{name: "failed then passed", testState: TestSucceeded, start: start, end: start.Add(time.Second)},
+	} {
+		if err := writer.RecordTestResult(context.Background(), result); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := writer.RecordTestResult(cancelled, &testRunResult{name: "cancelled", testState: TestSkipped}); err != nil {
+		t.Fatal(err)
+	}
+	// a run killed while writing leaves a partial line behind.
+	if _, err := writer.file.WriteString(`{"name": "partial`); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	previousResults, err := loadPreviousTestResults(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []*testCase{
+		{name: "passed in junit"},
+		{name: "interrupted"},
+		{name: "retried"},
+		{name: "failed"},
+		{name: "skipped by the test"},
+		{name: "failed then passed"},
+		{name: "cancelled"},
+		{name: "new"},
+	}
+	resumed, remaining := resumeTests(tests, previousResults)
+
+	remainingNames := []string{}
+	for _, test := range remaining {
+		remainingNames = append(remainingNames, test.name)
+	}
+	expectedRemaining := []string{"interrupted", "cancelled", "new"}
+	if len(remainingNames) != len(expectedRemaining) {
+		t.Fatalf("expected %v to remain, got %v", expectedRemaining, remainingNames)
+	}
+	for i := range expectedRemaining {
+		if remainingNames[i] != expectedRemaining[i] {
+			t.Fatalf("expected %v to remain, got %v", expectedRemaining, remainingNames)
+		}
+	}
+
+	resumedByName := map[string]*testCase{}
+	for _, test := range resumed {
+		resumedByName[test.name] = test
+	}
+	if test := resumedByName["passed in junit"]; !test.success || test.duration != 2*time.Second || !test.start.IsZero() || !test.end.IsZero() {
+		t.Errorf("expected a 2s success without times, got %#v", test)
+	}
+	if test := resumedByName["retried"]; !test.flake {
+		t.Errorf("expected a flake, got %#v", test)
+	}
+	if test := resumedByName["failed"]; !test.failed || test.duration != time.Minute || string(test.testOutputBytes) != "fail [output]" {
+		t.Errorf("expected a 1m failure with output, got %#v", test)
+	}
+	if test := resumedByName["skipped by the test"]; !test.skipped {
+		t.Errorf("expected a skip, got %#v", test)
+	}
+	if test := resumedByName["failed then passed"]; !test.flake {
+		t.Errorf("expected a flake, got %#v", test)
+	}
+}
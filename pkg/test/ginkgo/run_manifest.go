@@ -0,0 +1,397 @@
+package ginkgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	RunManifestAPIVersion = "tests.openshift.io/v1"
+	RunManifestKind       = "RunManifest"
+
+	runManifestFilePrefix = "run-manifest"
+)
+
+// RunManifest is one machine readable report of a run of a suite, written next to the junit.  It holds the
+// results that are otherwise spread across the junit, failure summaries, and monitor artifacts, and indexes every
+// artifact of the run.
+type RunManifest struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	Suite string    `json:"suite"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Result is Success or Failed, Error is why the run failed.
+	Result TestState         `json:"result"`
+	Error  string            `json:"error,omitempty"`
+	Shard  *RunManifestShard `json:"shard,omitempty"`
+
+	Cluster platformidentification.ClusterData `json:"cluster"`
+
+	Summary      RunManifestSummary       `json:"summary"`
+	Tests        []RunManifestTest        `json:"tests"`
+	MonitorTests []RunManifestMonitorTest `json:"monitorTests"`
+	// Artifacts are the files written to the junit dir, not counting the manifest itself.
+	Artifacts []RunManifestArtifact `json:"artifacts"`
+}
+
+type RunManifestShard struct {
+	Index int `json:"index"`
+	Count int `json:"count"`
+}
+
+type RunManifestSummary struct {
+	Passed             int `json:"passed"`
+	Failed             int `json:"failed"`
+	Flaked             int `json:"flaked"`
+	Skipped            int `json:"skipped"`
+	Quarantined        int `json:"quarantined"`
+	MonitorTestsFailed int `json:"monitorTestsFailed"`
+}
+
+// RunManifestTest is the result of a test over all its attempts.  A test is attempted more than once when it is
+// retried or --count is set.
+type RunManifestTest struct {
+	Name string `json:"name"`
+	// Result is Success, Failed, Flaked, or Skipped.  A test that both passed and failed flaked.
+	Result TestState `json:"result"`
+	// Retries is how many of the attempts were retries of a failure.
+	Retries int `json:"retries"`
+	// Quarantined tests don't fail the run, see testQuarantine.
+	Quarantined bool `json:"quarantined,omitempty"`
+	// Resumed tests ran in the run this one resumed from.
+	Resumed bool `json:"resumed,omitempty"`
+	// Synthetic tests are not run by ginkgo, they report what the suite found, like the invariants of the monitor.
+	Synthetic       bool                 `json:"synthetic,omitempty"`
+	DurationSeconds float64              `json:"durationSeconds"`
+	Attempts        []RunManifestAttempt `json:"attempts"`
+	// OutputFile is the junit holding the output of the test, relative to the junit dir.
+	OutputFile string `json:"outputFile,omitempty"`
+}
+
+// RunManifestAttempt is one run of a test.  Start and End are missing when they are unknown, for synthetic tests and
+// for tests resumed from a junit, which only has their duration.
+type RunManifestAttempt struct {
+	Start           *time.Time `json:"start,omitempty"`
+	End             *time.Time `json:"end,omitempty"`
+	DurationSeconds float64    `json:"durationSeconds"`
+	Result          TestState  `json:"result"`
+}
+
+// RunManifestMonitorTest is the result of each phase of a monitor test.
+type RunManifestMonitorTest struct {
+	Name          string `json:"name"`
+	JiraComponent string `json:"jiraComponent"`
+	// Result is Failed if any phase failed, Flaked if any phase flaked, and Success otherwise.
+	Result TestState                     `json:"result"`
+	Phases []RunManifestMonitorTestPhase `json:"phases"`
+}
+
+type RunManifestMonitorTestPhase struct {
+	Phase           monitortestframework.Phase `json:"phase"`
+	Result          TestState                  `json:"result"`
+	DurationSeconds float64                    `json:"durationSeconds"`
+	// Message is the failure or the reason the phase was skipped.
+	Message string `json:"message,omitempty"`
+}
+
+type RunManifestArtifact struct {
+	// Path is relative to the junit dir.
+	Path      string `json:"path"`
+	Kind      string `json:"kind"`
+	SizeBytes int64  `json:"sizeBytes"`
+}
+
+// artifactKinds classifies artifacts by the pattern of their file name, the first match wins.
+var artifactKinds = []struct {
+	pattern string
+	kind    string
+}{
+	{pattern: "junit*.xml", kind: "junit"},
+	{pattern: "e2e-monitor-tests*.xml", kind: "junit"},
+	{pattern: testResultsFilePrefix + "*.jsonl", kind: "test-results"},
+	{pattern: "test-failures-summary*.json", kind: "test-failures-summary"},
+	{pattern: quarantineFilePrefix + "*.json", kind: "quarantine"},
+	{pattern: "pod-placement-data.json", kind: "pod-placement"},
+	{pattern: "pod-transitions.txt", kind: "pod-placement"},
+	{pattern: "e2e-events*.json", kind: "intervals"},
+	{pattern: "events_used_for_junits*.json", kind: "intervals"},
+	{pattern: "e2e-timelines*", kind: "timeline"},
+	{pattern: "resource-*", kind: "resources"},
+	{pattern: "cluster-data*.json", kind: "cluster-data"},
+	{pattern: "openshift-tests-monitor*.txt", kind: "monitor-summary"},
+}
+
+func artifactKind(filename string) string {
+	for _, curr := range artifactKinds {
+		if matched, _ := filepath.Match(curr.pattern, filename); matched {
+			return curr.kind
+		}
+	}
+	return "other"
+}
+
+// runManifestTests groups the tests of the run and the synthetic tests, which are written to the same junit, by
+// name.  Tests with no result, because the run was interrupted, are left out.
+func runManifestTests(tests, quarantinedTests, resumedTests []*testCase, syntheticTestResults []*junitapi.JUnitTestCase, outputFile, quarantinedOutputFile string) []RunManifestTest {
+	resumed := map[*testCase]bool{}
+	for _, test := range resumedTests {
+		resumed[test] = true
+	}
+
+	byName := map[string]*RunManifestTest{}
+	var names []string
+	add := func(test *testCase, quarantined bool, outputFile string) {
+		result, ok := testCaseState(test)
+		if !ok {
+			return
+		}
+		curr, ok := byName[test.name]
+		if !ok {
+			curr = &RunManifestTest{Name: test.name, Quarantined: quarantined, OutputFile: outputFile}
+			byName[test.name] = curr
+			names = append(names, test.name)
+		}
+		if test.previous != nil {
+			curr.Retries++
+		}
+		if resumed[test] {
+			curr.Resumed = true
+		}
+		curr.DurationSeconds += test.duration.Seconds()
+		attempt := RunManifestAttempt{
+			DurationSeconds: test.duration.Seconds(),
+			Result:          result,
+		}
+		if !test.start.IsZero() {
+			start := test.start
+			attempt.Start = &start
+		}
+		if !test.end.IsZero() {
+			end := test.end
+			attempt.End = &end
+		}
+		curr.Attempts = append(curr.Attempts, attempt)
+	}
+	for _, test := range tests {
+		add(test, false, outputFile)
+	}
+	for _, test := range quarantinedTests {
+		add(test, true, quarantinedOutputFile)
+	}
+	for _, junit := range syntheticTestResults {
+		curr, ok := byName[junit.Name]
+		if !ok {
+			curr = &RunManifestTest{Name: junit.Name, Synthetic: true, OutputFile: outputFile}
+			byName[junit.Name] = curr
+			names = append(names, junit.Name)
+		}
+		result := TestSucceeded
+		switch {
+		case junit.FailureOutput != nil:
+			result = TestFailed
+		case junit.SkipMessage != nil:
+			result = TestSkipped
+		}
+		curr.DurationSeconds += junit.Duration
+		curr.Attempts = append(curr.Attempts, RunManifestAttempt{DurationSeconds: junit.Duration, Result: result})
+	}
+
+	sort.Strings(names)
+	ret := []RunManifestTest{}
+	for _, name := range names {
+		curr := byName[name]
+		curr.Result = combinedTestState(curr.Attempts)
+		ret = append(ret, *curr)
+	}
+	return ret
+}
+
+func testCaseState(test *testCase) (TestState, bool) {
+	switch {
+	case test.flake:
+		return TestFlaked, true
+	case test.success:
+		return TestSucceeded, true
+	case test.skipped:
+		return TestSkipped, true
+	case test.timedOut:
+		return TestFailedTimeout, true
+	case test.failed:
+		return TestFailed, true
+	}
+	return "", false
+}
+
+// combinedTestState is Flaked if any attempt flaked or the attempts both passed and failed, Failed if no attempt
+// passed, Skipped if every attempt was skipped, and Success otherwise.
+func combinedTestState(attempts []RunManifestAttempt) TestState {
+	var passed, failed, flaked bool
+	for _, attempt := range attempts {
+		switch {
+		case attempt.Result == TestFlaked:
+			flaked = true
+		case attempt.Result == TestSucceeded:
+			passed = true
+		case isTestFailed(attempt.Result):
+			failed = true
+		}
+	}
+	switch {
+	case flaked || (passed && failed):
+		return TestFlaked
+	case failed:
+		return TestFailed
+	case passed:
+		return TestSucceeded
+	}
+	return TestSkipped
+}
+
+// runManifestMonitorTests reads the result of each phase of each monitor test from the junits of the monitor.  A
+// phase that returned a flake error has both a failing and a passing junit.
+func runManifestMonitorTests(junits []*junitapi.JUnitTestCase) []RunManifestMonitorTest {
+	byName := map[string]*RunManifestMonitorTest{}
+	var names []string
+	for _, junit := range junits {
+		jiraComponent, name, phase, ok := monitortestframework.ParsePhaseTestName(junit.Name)
+		if !ok {
+			continue
+		}
+		curr, ok := byName[name]
+		if !ok {
+			curr = &RunManifestMonitorTest{Name: name, JiraComponent: jiraComponent}
+			byName[name] = curr
+			names = append(names, name)
+		}
+
+		result, message := TestSucceeded, ""
+		switch {
+		case junit.FailureOutput != nil:
+			result, message = TestFailed, junit.FailureOutput.Output
+		case junit.SkipMessage != nil:
+			result, message = TestSkipped, junit.SkipMessage.Message
+		}
+
+		var existing *RunManifestMonitorTestPhase
+		for i := range curr.Phases {
+			if curr.Phases[i].Phase == phase {
+				existing = &curr.Phases[i]
+			}
+		}
+		if existing == nil {
+			curr.Phases = append(curr.Phases, RunManifestMonitorTestPhase{
+				Phase:           phase,
+				Result:          result,
+				DurationSeconds: junit.Duration,
+				Message:         message,
+			})
+			continue
+		}
+		if (existing.Result == TestFailed && result == TestSucceeded) || (existing.Result == TestSucceeded && result == TestFailed) {
+			existing.Result = TestFlaked
+			if len(message) > 0 {
+				existing.Message = message
+			}
+		}
+	}
+
+	sort.Strings(names)
+	ret := []RunManifestMonitorTest{}
+	for _, name := range names {
+		curr := byName[name]
+		curr.Result = TestSucceeded
+		for _, phase := range curr.Phases {
+			switch {
+			case phase.Result == TestFailed:
+				curr.Result = TestFailed
+			case phase.Result == TestFlaked && curr.Result != TestFailed:
+				curr.Result = TestFlaked
+			}
+		}
+		ret = append(ret, *curr)
+	}
+	return ret
+}
+
+// runManifestArtifacts lists every file under dir, except the run manifests.
+func runManifestArtifacts(dir string) ([]RunManifestArtifact, error) {
+	ret := []RunManifestArtifact{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if matched, _ := filepath.Match(runManifestFilePrefix+"*.json", d.Name()); matched {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		ret = append(ret, RunManifestArtifact{
+			Path:      filepath.ToSlash(relativePath),
+			Kind:      artifactKind(d.Name()),
+			SizeBytes: info.Size(),
+		})
+		return nil
+	})
+	return ret, err
+}
+
+func (m *RunManifest) summarize() {
+	m.Summary = RunManifestSummary{}
+	for _, test := range m.Tests {
+		switch test.Result {
+		case TestSucceeded:
+			m.Summary.Passed++
+		case TestFlaked:
+			m.Summary.Flaked++
+		case TestSkipped:
+			m.Summary.Skipped++
+		default:
+			m.Summary.Failed++
+		}
+		if test.Quarantined {
+			m.Summary.Quarantined++
+		}
+	}
+	for _, monitorTest := range m.MonitorTests {
+		if monitorTest.Result == TestFailed {
+			m.Summary.MonitorTestsFailed++
+		}
+	}
+}
+
+// writeRunManifest indexes the artifacts in dir and writes the manifest there.
+func writeRunManifest(manifest *RunManifest, dir, fileSuffix string) (string, error) {
+	artifacts, err := runManifestArtifacts(dir)
+	if err != nil {
+		return "", fmt.Errorf("unable to index the artifacts: %w", err)
+	}
+	manifest.Artifacts = artifacts
+	manifest.summarize()
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s%s.json", runManifestFilePrefix, fileSuffix))
+	return path, os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,193 @@
+package ginkgo
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+func Test_runManifestTests(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	failed := &testCase{name: "retried", failed: true, start: start, end: start.Add(time.Minute), duration: time.Minute}
+	retry := failed.Retry()
+	retry.success, retry.start, retry.end, retry.duration = true, start.Add(2*time.Minute), start.Add(3*time.Minute), time.Minute
+	resumed := &testCase{name: "resumed", success: true, duration: 2 * time.Second}
+
+	tests := []*testCase{
+		{name: "passed", success: true, duration: time.Second},
+		{name: "skipped", skipped: true},
+		{name: "timed out", failed: true, timedOut: true},
+		{name: "incomplete"},
+		failed,
+		retry,
+		resumed,
+	}
+	quarantined := []*testCase{
+		{name: "quarantined", failed: true},
+	}
+
+	synthetic := []*junitapi.JUnitTestCase{
+		{Name: "[sig-arch] invariant", Duration: 1, FailureOutput: &junitapi.FailureOutput{Output: "fail"}},
+		{Name: "[sig-arch] invariant", Duration: 1},
+		{Name: "[sig-arch] passing invariant"},
+	}
+
+	got := runManifestTests(tests, quarantined, []*testCase{resumed}, synthetic, "junit_e2e.xml", "junit_quarantine.xml")
+	results := map[string]TestState{}
+	for _, test := range got {
+		results[test.Name] = test.Result
+	}
+	expectedResults := map[string]TestState{
+		"passed":      TestSucceeded,
+		"skipped":     TestSkipped,
+		"timed out":   TestFailed,
+		"retried":     TestFlaked,
+		"resumed":     TestSucceeded,
+		"quarantined": TestFailed,
+
+		"[sig-arch] invariant":         TestFlaked,
+		"[sig-arch] passing invariant": TestSucceeded,
+	}
+	if !reflect.DeepEqual(expectedResults, results) {
+		t.Fatalf("expected results %v, got %v", expectedResults, results)
+	}
+
+	for _, test := range got {
+		switch test.Name {
+		case "retried":
+			if test.Retries != 1 || len(test.Attempts) != 2 || test.DurationSeconds != 120 {
+				t.Errorf("expected one retry and two attempts of 2m total, got %#v", test)
+			}
+			if test.Attempts[0].Result != TestFailed || test.Attempts[1].Result != TestSucceeded || !test.Attempts[1].Start.Equal(start.Add(2*time.Minute)) {
+				t.Errorf("expected a failed attempt followed by a passing one, got %#v", test.Attempts)
+			}
+		case "timed out":
+			if test.Attempts[0].Result != TestFailedTimeout {
+				t.Errorf("expected the attempt to have timed out, got %v", test.Attempts[0].Result)
+			}
+		case "resumed":
+			if !test.Resumed {
+				t.Errorf("expected the test to be resumed")
+			}
+			if attempt := test.Attempts[0]; attempt.Start != nil || attempt.End != nil || attempt.DurationSeconds != 2 {
+				t.Errorf("expected a 2s attempt without times, got %#v", attempt)
+			}
+		case "[sig-arch] invariant", "[sig-arch] passing invariant":
+			if !test.Synthetic || test.OutputFile != "junit_e2e.xml" {
+				t.Errorf("expected %q to be synthetic with its output in the e2e junit, got %#v", test.Name, test)
+			}
+		case "quarantined":
+			if !test.Quarantined || test.OutputFile != "junit_quarantine.xml" {
+				t.Errorf("expected the test to be quarantined with its output in the quarantine junit, got %#v", test)
+			}
+		default:
+			if test.Quarantined || test.Resumed || test.Synthetic || test.OutputFile != "junit_e2e.xml" {
+				t.Errorf("expected %q to have its output in the e2e junit, got %#v", test.Name, test)
+			}
+		}
+	}
+}
+
+func Test_runManifestMonitorTests(t *testing.T) {
+	junits := []*junitapi.JUnitTestCase{
+		{Name: `[Jira:"kube-apiserver"] monitor test apiserver-availability setup`, Duration: 1},
+		{Name: `[Jira:"kube-apiserver"] monitor test apiserver-availability collection`, FailureOutput: &junitapi.FailureOutput{Output: "failed during collection\nflaky"}},
+		{Name: `[Jira:"kube-apiserver"] monitor test apiserver-availability collection`},
+		{Name: `[Jira:"Networking"] monitor test network-pods writing to storage`, FailureOutput: &junitapi.FailureOutput{Output: "failed during test evaluation\nfull disk"}},
+		{Name: `[Jira:"Networking"] monitor test network-pods cleanup`, SkipMessage: &junitapi.SkipMessage{Message: "not supported"}},
+		{Name: `[sig-network] pods should not lose connectivity`},
+	}
+
+	expected := []RunManifestMonitorTest{
+		{
+			Name:          "apiserver-availability",
+			JiraComponent: "kube-apiserver",
+			Result:        TestFlaked,
+			Phases: []RunManifestMonitorTestPhase{
+				{Phase: monitortestframework.PhaseSetup, Result: TestSucceeded, DurationSeconds: 1},
+				{Phase: monitortestframework.PhaseCollection, Result: TestFlaked, Message: "failed during collection\nflaky"},
+			},
+		},
+		{
+			Name:          "network-pods",
+			JiraComponent: "Networking",
+			Result:        TestFailed,
+			Phases: []RunManifestMonitorTestPhase{
+				{Phase: monitortestframework.PhaseWritingToStorage, Result: TestFailed, Message: "failed during test evaluation\nfull disk"},
+				{Phase: monitortestframework.PhaseCleanup, Result: TestSkipped, Message: "not supported"},
+			},
+		},
+	}
+	if got := runManifestMonitorTests(junits); !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected %#v, got %#v", expected, got)
+	}
+}
+
+func Test_writeRunManifest(t *testing.T) {
+	dir := t.TempDir()
+	for _, filename := range []string{
+		"junit_e2e__20240101-000000.xml",
+		"e2e-monitor-tests__20240101-000000.xml",
+		"pod-transitions.txt",
+		"monitor-events/e2e-events_20240101-000000.json",
+		"run-manifest_20230101-000000.json",
+		"unknown.log",
+	} {
+		path := filepath.Join(dir, filename)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manifest := &RunManifest{
+		APIVersion: RunManifestAPIVersion,
+		Kind:       RunManifestKind,
+		Result:     TestFailed,
+		Tests: []RunManifestTest{
+			{Name: "passed", Result: TestSucceeded},
+			{Name: "flaked", Result: TestFlaked},
+			{Name: "quarantined", Result: TestFailed, Quarantined: true},
+		},
+		MonitorTests: []RunManifestMonitorTest{{Name: "failed", Result: TestFailed}},
+	}
+	path, err := writeRunManifest(manifest, dir, "_20240101-000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(path) != "run-manifest_20240101-000000.json" {
+		t.Errorf("unexpected manifest path %s", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	written := &RunManifest{}
+	if err := json.Unmarshal(data, written); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSummary := RunManifestSummary{Passed: 1, Failed: 1, Flaked: 1, Quarantined: 1, MonitorTestsFailed: 1}
+	if written.Summary != expectedSummary {
+		t.Errorf("expected summary %#v, got %#v", expectedSummary, written.Summary)
+	}
+	expectedArtifacts := []RunManifestArtifact{
+		{Path: "e2e-monitor-tests__20240101-000000.xml", Kind: "junit", SizeBytes: 4},
+		{Path: "junit_e2e__20240101-000000.xml", Kind: "junit", SizeBytes: 4},
+		{Path: "monitor-events/e2e-events_20240101-000000.json", Kind: "intervals", SizeBytes: 4},
+		{Path: "pod-transitions.txt", Kind: "pod-placement", SizeBytes: 4},
+		{Path: "unknown.log", Kind: "other", SizeBytes: 4},
+	}
+	if !reflect.DeepEqual(expectedArtifacts, written.Artifacts) {
+		t.Errorf("expected artifacts %#v, got %#v", expectedArtifacts, written.Artifacts)
+	}
+}
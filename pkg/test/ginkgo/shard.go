@@ -0,0 +1,78 @@
+package ginkgo
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// shardBucket groups tests that run at the same point in the suite, so each shard gets a fair share of every phase.
+func shardBucket(test *testCase) string {
+	switch {
+	case strings.Contains(test.name, "[Early]"):
+		return "early"
+	case strings.Contains(test.name, "[Late]"):
+		return "late"
+	case isSerialTest(test):
+		return "serial"
+	default:
+		return "parallel"
+	}
+}
+
+// shardTests returns the tests that belong to shardIndex out of shardCount.  Every shard runs against its own
+// cluster, so the [Early] and [Late] tests, which check the cluster before and after the suite, run on every shard.
+// The parallel and serial tests are split: every process computes the same assignment from the same list of tests,
+// regardless of the order they are passed in, so shards never overlap and no test is dropped.  Within each bucket
+// tests are assigned longest first to the shard with the least estimated work.  The returned tests keep their order
+// from the input.
+func shardTests(tests []*testCase, shardIndex, shardCount int, durations map[string]time.Duration) []*testCase {
+	if shardCount <= 1 {
+		return tests
+	}
+
+	inShard := map[*testCase]bool{}
+	buckets := map[string][]*testCase{}
+	for _, test := range tests {
+		bucket := shardBucket(test)
+		if bucket == "early" || bucket == "late" {
+			inShard[test] = true
+			continue
+		}
+		buckets[bucket] = append(buckets[bucket], test)
+	}
+
+	for _, bucketTests := range buckets {
+		sorted := make([]*testCase, len(bucketTests))
+		copy(sorted, bucketTests)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			iDuration, jDuration := estimatedDuration(sorted[i], durations), estimatedDuration(sorted[j], durations)
+			if iDuration != jDuration {
+				return iDuration > jDuration
+			}
+			return sorted[i].name < sorted[j].name
+		})
+
+		load := make([]time.Duration, shardCount)
+		for _, test := range sorted {
+			target := 0
+			for shard := 1; shard < shardCount; shard++ {
+				if load[shard] < load[target] {
+					target = shard
+				}
+			}
+			load[target] += estimatedDuration(test, durations)
+			if target == shardIndex {
+				inShard[test] = true
+			}
+		}
+	}
+
+	ret := []*testCase{}
+	for _, test := range tests {
+		if inShard[test] {
+			ret = append(ret, test)
+		}
+	}
+	return ret
+}
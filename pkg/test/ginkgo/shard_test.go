@@ -0,0 +1,109 @@
+package ginkgo
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func Test_shardTests(t *testing.T) {
+	tests := makeTestCases()
+	durations := map[string]time.Duration{}
+	for i, test := range tests {
+		durations[test.name] = time.Duration(i%17+1) * time.Second
+	}
+	shardCount := 4
+
+	seen := map[string]int{}
+	bucketLoad := map[string][]time.Duration{}
+	for shardIndex := 0; shardIndex < shardCount; shardIndex++ {
+		shard := shardTests(tests, shardIndex, shardCount, durations)
+
+		// the assignment must not depend on the order of the input, every shard shuffles with its own seed.
+		shuffled := make([]*testCase, len(tests))
+		copy(shuffled, tests)
+		rand.New(rand.NewSource(int64(shardIndex))).Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		shuffledShard := shardTests(shuffled, shardIndex, shardCount, durations)
+		if len(shard) != len(shuffledShard) {
+			t.Fatalf("shard %d has %d tests, but %d when shuffled", shardIndex, len(shard), len(shuffledShard))
+		}
+		shardNames := map[string]bool{}
+		for _, test := range shard {
+			shardNames[test.name] = true
+		}
+		for _, test := range shuffledShard {
+			if !shardNames[test.name] {
+				t.Errorf("shard %d only contains %q when shuffled", shardIndex, test.name)
+			}
+		}
+
+		for _, test := range shard {
+			seen[test.name]++
+			bucket := shardBucket(test)
+			if bucketLoad[bucket] == nil {
+				bucketLoad[bucket] = make([]time.Duration, shardCount)
+			}
+			bucketLoad[bucket][shardIndex] += estimatedDuration(test, durations)
+		}
+	}
+
+	for _, test := range tests {
+		expected := 1
+		if bucket := shardBucket(test); bucket == "early" || bucket == "late" {
+			expected = shardCount
+		}
+		if seen[test.name] != expected {
+			t.Errorf("expected %q in %d shards, got %d", test.name, expected, seen[test.name])
+		}
+	}
+
+	// longest first assignment keeps every shard within the longest single test of the others.
+	for bucket, load := range bucketLoad {
+		minLoad, maxLoad := load[0], load[0]
+		for _, curr := range load {
+			if curr < minLoad {
+				minLoad = curr
+			}
+			if curr > maxLoad {
+				maxLoad = curr
+			}
+		}
+		if maxLoad-minLoad > 17*time.Second {
+			t.Errorf("bucket %s is unbalanced across shards: %v", bucket, load)
+		}
+	}
+}
+
+func Test_shardTestsBuckets(t *testing.T) {
+	tests := []*testCase{
+		{name: "[Early] one"},
+		{name: "[Early] two"},
+		{name: "[Serial] one"},
+		{name: "[Serial] two"},
+		{name: "[Late] one"},
+		{name: "[Late] two"},
+		{name: "parallel one"},
+		{name: "parallel two"},
+	}
+	for shardIndex := 0; shardIndex < 2; shardIndex++ {
+		counts := map[string]int{}
+		for _, test := range shardTests(tests, shardIndex, 2, nil) {
+			counts[shardBucket(test)]++
+		}
+		// every shard checks its own cluster before and after the suite.
+		for _, bucket := range []string{"early", "late"} {
+			if counts[bucket] != 2 {
+				t.Errorf("expected shard %d to have every %s test, got %d", shardIndex, bucket, counts[bucket])
+			}
+		}
+		for _, bucket := range []string{"serial", "parallel"} {
+			if counts[bucket] != 1 {
+				t.Errorf("expected shard %d to have one %s test, got %d", shardIndex, bucket, counts[bucket])
+			}
+		}
+	}
+
+	if actual := shardTests(tests, 0, 1, nil); len(actual) != len(tests) {
+		t.Errorf("expected a single shard to run all %d tests, got %d", len(tests), len(actual))
+	}
+}
@@ -0,0 +1,144 @@
+package ginkgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultTestDuration is the estimate for tests we have no history for.
+	defaultTestDuration = 1 * time.Minute
+	// defaultSlowTestDuration is the estimate for [Slow] tests we have no history for.
+	defaultSlowTestDuration = 5 * time.Minute
+)
+
+// loadTestDurations reads historical test durations from each path.  A .json file is a map of test name to
+// duration in seconds.  Any other file is read as junit xml, and directories are searched for junit*.xml files.
+// When a test appears more than once, the longest duration wins so estimates err on the side of a test being slow.
+func loadTestDurations(paths []string) (map[string]time.Duration, error) {
+	durations := map[string]time.Duration{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case info.IsDir():
+			err = filepath.WalkDir(path, func(filename string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if d.IsDir() || !strings.HasPrefix(d.Name(), "junit") || filepath.Ext(d.Name()) != ".xml" {
+					return nil
+				}
+				return addTestDurationsFromJUnitFile(filename, durations)
+			})
+		case filepath.Ext(path) == ".json":
+			err = addTestDurationsFromJSONFile(path, durations)
+		default:
+			err = addTestDurationsFromJUnitFile(path, durations)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return durations, nil
+}
+
+func addTestDurationsFromJSONFile(filename string, durations map[string]time.Duration) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	seconds := map[string]float64{}
+	if err := json.Unmarshal(data, &seconds); err != nil {
+		return fmt.Errorf("failed to decode %q, expected a map of test name to seconds: %w", filename, err)
+	}
+	for name, curr := range seconds {
+		addTestDuration(durations, name, time.Duration(curr*float64(time.Second)))
+	}
+	return nil
+}
+
+func addTestDurationsFromJUnitFile(filename string, durations map[string]time.Duration) error {
+	suites, err := ReadJUnitSuites(filename)
+	if err != nil {
+		return err
+	}
+	for _, suite := range suites {
+		for _, testCase := range suite.TestCases {
+			// skipped tests don't tell us how long the test takes to run.
+			if testCase.SkipMessage != nil {
+				continue
+			}
+			addTestDuration(durations, testCase.Name, time.Duration(testCase.Duration*float64(time.Second)))
+		}
+	}
+	return nil
+}
+
+func addTestDuration(durations map[string]time.Duration, name string, duration time.Duration) {
+	if duration > durations[name] {
+		durations[name] = duration
+	}
+}
+
+// estimatedDuration returns how long the test is expected to take, from history if we have it.
+func estimatedDuration(test *testCase, durations map[string]time.Duration) time.Duration {
+	if duration, ok := durations[test.name]; ok && duration > 0 {
+		return duration
+	}
+	if strings.Contains(test.name, "[Slow]") {
+		return defaultSlowTestDuration
+	}
+	return defaultTestDuration
+}
+
+// longestFirst returns a copy of tests ordered by estimated duration, longest first, so a long test is not picked
+// up at the end of a bucket and left running alone.  Tests with the same estimate keep their relative order, which
+// preserves the random order of tests we have no history for.
+func longestFirst(tests []*testCase, durations map[string]time.Duration) []*testCase {
+	ret := make([]*testCase, len(tests))
+	copy(ret, tests)
+	sort.SliceStable(ret, func(i, j int) bool {
+		return estimatedDuration(ret[i], durations) > estimatedDuration(ret[j], durations)
+	})
+	return ret
+}
+
+// predictWallClock estimates how long execute takes to run tests: parallel tests are handed in order to whichever
+// of the parallelism workers frees up first, then serial tests run one at a time.
+func predictWallClock(tests []*testCase, parallelism int, durations map[string]time.Duration) time.Duration {
+	serial, parallel := splitTests(tests, isSerialTest)
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	workers := make([]time.Duration, parallelism)
+	for _, test := range parallel {
+		next := 0
+		for i := range workers {
+			if workers[i] < workers[next] {
+				next = i
+			}
+		}
+		workers[next] += estimatedDuration(test, durations)
+	}
+	var predicted time.Duration
+	for _, worker := range workers {
+		if worker > predicted {
+			predicted = worker
+		}
+	}
+
+	for _, test := range serial {
+		predicted += estimatedDuration(test, durations)
+	}
+	return predicted
+}
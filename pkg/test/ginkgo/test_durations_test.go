@@ -0,0 +1,101 @@
+package ginkgo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_longestFirst(t *testing.T) {
+	tests := []*testCase{
+		{name: "unknown one"},
+		{name: "short"},
+		{name: "[Slow] unknown"},
+		{name: "long"},
+		{name: "unknown two"},
+	}
+	durations := map[string]time.Duration{
+		"short": 10 * time.Second,
+		"long":  10 * time.Minute,
+	}
+
+	actual := []string{}
+	for _, test := range longestFirst(tests, durations) {
+		actual = append(actual, test.name)
+	}
+	// tests without history keep their relative order.
+	expected := []string{"long", "[Slow] unknown", "unknown one", "unknown two", "short"}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, actual)
+		}
+	}
+	if tests[0].name != "unknown one" {
+		t.Errorf("longestFirst must not reorder its input")
+	}
+}
+
+func Test_predictWallClock(t *testing.T) {
+	durations := map[string]time.Duration{
+		"a":          4 * time.Minute,
+		"b":          3 * time.Minute,
+		"c":          2 * time.Minute,
+		"d":          2 * time.Minute,
+		"[Serial] e": 1 * time.Minute,
+	}
+	tests := []*testCase{{name: "a"}, {name: "b"}, {name: "c"}, {name: "d"}, {name: "[Serial] e"}}
+
+	// a and d share one worker while b and c share the other, then the serial test runs.
+	if actual := predictWallClock(tests, 2, durations); actual != 7*time.Minute {
+		t.Errorf("expected 7m, got %v", actual)
+	}
+	if actual := predictWallClock(tests, 1, durations); actual != 12*time.Minute {
+		t.Errorf("expected 12m, got %v", actual)
+	}
+}
+
+func Test_loadTestDurations(t *testing.T) {
+	dir := t.TempDir()
+	junit := `<testsuites>
+  <testsuite name="openshift-tests" tests="3" skipped="1" failures="0" time="10">
+    <testcase name="fast" time="1.5"></testcase>
+    <testcase name="slow" time="30"></testcase>
+    <testcase name="skipped" time="0"><skipped message="skip"></skipped></testcase>
+  </testsuite>
+</testsuites>`
+	if err := os.WriteFile(filepath.Join(dir, "junit_e2e.xml"), []byte(junit), 0644); err != nil {
+		t.Fatal(err)
+	}
+	retry := `<testsuite name="openshift-tests" tests="1" skipped="0" failures="0" time="10">
+  <testcase name="fast" time="3"></testcase>
+</testsuite>`
+	if err := os.WriteFile(filepath.Join(dir, "junit_retry.xml"), []byte(retry), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "other.xml"), []byte("not junit"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	durationsFile := filepath.Join(t.TempDir(), "durations.json")
+	if err := os.WriteFile(durationsFile, []byte(`{"slow": 20, "from-json": 0.5}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	durations, err := loadTestDurations([]string{dir, durationsFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]time.Duration{
+		"fast":      3 * time.Second,
+		"slow":      30 * time.Second,
+		"from-json": 500 * time.Millisecond,
+	}
+	if len(durations) != len(expected) {
+		t.Errorf("expected %v, got %v", expected, durations)
+	}
+	for name, duration := range expected {
+		if durations[name] != duration {
+			t.Errorf("expected %q to take %v, got %v", name, duration, durations[name])
+		}
+	}
+}
@@ -57,6 +57,10 @@ func (r *testSuiteRunnerImpl) RunOneTest(ctx context.Context, test *testCase) {
 
 	testRunResult.testRunResult = r.commandContext.RunTestInNewProcess(ctx, test)
 	mutateTestCaseWithResults(test, testRunResult)
+
+	if err := r.testOutput.testResults.RecordTestResult(ctx, testRunResult.testRunResult); err != nil {
+		fmt.Fprintf(r.testOutput.out, "error: Unable to record result of %q for resume: %v\n", test.name, err)
+	}
 }
 
 func mutateTestCaseWithResults(test *testCase, testRunResult *testRunResultHandle) {
@@ -125,6 +129,8 @@ type testOutputConfig struct {
 	testOutputLock  *sync.Mutex
 	out             io.Writer
 	monitorRecorder monitorapi.Recorder
+	// testResults records each finished test so an interrupted run can be resumed.  It may be nil.
+	testResults *testResultWriter
 
 	includeSuccessfulOutput bool
 }
@@ -173,11 +179,12 @@ func isTestFailed(testState TestState) bool {
 }
 
 // testOutputLock prevents parallel tests from interleaving their output.
-func newTestOutputConfig(testOutputLock *sync.Mutex, out io.Writer, monitorRecorder monitorapi.Recorder, includeSuccessfulOutput bool) testOutputConfig {
+func newTestOutputConfig(testOutputLock *sync.Mutex, out io.Writer, monitorRecorder monitorapi.Recorder, testResults *testResultWriter, includeSuccessfulOutput bool) testOutputConfig {
 	return testOutputConfig{
 		testOutputLock:          testOutputLock,
 		out:                     out,
 		monitorRecorder:         monitorRecorder,
+		testResults:             testResults,
 		includeSuccessfulOutput: includeSuccessfulOutput,
 	}
 }
@@ -484,6 +484,7 @@
 // e2echart/e2e-chart-template.html
 // e2echart/non-spyglass-e2e-chart-template.html
 // e2echart/test-risk-analysis.html
+// e2echart/timeline-viewer.html
 package testdata
 
 import (
@@ -54564,7 +54565,7 @@ var _e2echartTestRiskAnalysisHtml = []byte(`<html lang="en">
 </head>
 <body onLoad="buildTestCaseTable('#test_case_results'); buildDisruptionTable('#disruption_results')">
 <p>
-    <a target="_blank" href="TEST_RISK_ANALYSIS_SIPPY_URL_GOES_HERE">Link to Sippy</a>
+    TEST_RISK_ANALYSIS_SIPPY_LINK_GOES_HERE
 </p>
 
 <table id="test_case_results" border="1" width="100%">
@@ -54735,6 +54736,744 @@ func e2echartTestRiskAnalysisHtml() (*asset, error) {
 	return a, nil
 }
 
+var _e2echartTimelineViewerHtml = []byte(`<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <meta http-equiv="X-UA-Compatible" content="ie=edge">
+    <title>TIMELINE_VIEWER_TITLE_GOES_HERE</title>
+    <style>
+        * { box-sizing: border-box; }
+        body { margin: 0; font-family: -apple-system, "Segoe UI", Roboto, Helvetica, Arial, sans-serif; font-size: 13px; color: #212529; }
+        #toolbar { display: flex; flex-wrap: wrap; align-items: center; gap: 8px; padding: 6px 8px; border-bottom: 1px solid #dee2e6; background: #f8f9fa; }
+        #toolbar h1 { font-size: 15px; margin: 0 8px 0 0; }
+        #toolbar input[type=text] { padding: 3px 6px; border: 1px solid #ced4da; border-radius: 3px; width: 240px; }
+        #toolbar input.invalid { border-color: #dc3545; background: #fff5f5; }
+        #toolbar button { padding: 3px 8px; border: 1px solid #ced4da; border-radius: 3px; background: #fff; cursor: pointer; }
+        #toolbar button.active { background: #343a40; color: #fff; }
+        #toolbar label { white-space: nowrap; }
+        #sources { position: relative; }
+        #sources summary { cursor: pointer; padding: 3px 8px; border: 1px solid #ced4da; border-radius: 3px; background: #fff; list-style: none; }
+        #sourceList { position: absolute; z-index: 10; top: 26px; left: 0; max-height: 60vh; overflow: auto; padding: 6px; background: #fff; border: 1px solid #ced4da; border-radius: 3px; box-shadow: 0 2px 8px rgba(0, 0, 0, .15); }
+        #sourceList label { display: block; }
+        #status { color: #6c757d; margin-left: auto; }
+        #main { display: flex; height: calc(100vh - 44px); }
+        #timeline { flex: 1; display: flex; flex-direction: column; min-width: 0; }
+        #overview, #axis { display: block; width: 100%; }
+        #overview { height: 40px; cursor: crosshair; border-bottom: 1px solid #dee2e6; }
+        #axis { height: 20px; border-bottom: 1px solid #dee2e6; }
+        #rows { flex: 1; overflow-y: auto; overflow-x: hidden; position: relative; }
+        #rowsCanvas { position: sticky; top: 0; display: block; }
+        #details { width: 440px; overflow: auto; border-left: 1px solid #dee2e6; padding: 8px; }
+        #details h2 { font-size: 14px; margin: 0 0 6px 0; word-break: break-all; }
+        #details h3 { font-size: 13px; margin: 12px 0 4px 0; }
+        #details table { border-collapse: collapse; width: 100%; }
+        #details td { border-top: 1px solid #eee; padding: 2px 4px; vertical-align: top; word-break: break-all; }
+        #details td:first-child { color: #6c757d; white-space: nowrap; width: 1%; }
+        #details pre { white-space: pre-wrap; word-break: break-all; background: #f8f9fa; padding: 6px; font-size: 12px; }
+        #details a { color: #0366d6; cursor: pointer; text-decoration: underline; }
+        #details .hint { color: #6c757d; }
+        #details .failure { color: #dc3545; }
+        #details .flake { color: #e0a800; }
+        #tooltip { position: fixed; z-index: 20; pointer-events: none; display: none; max-width: 600px; padding: 4px 6px; background: rgba(33, 37, 41, .92); color: #fff; border-radius: 3px; font-size: 12px; white-space: pre-wrap; word-break: break-all; }
+    </style>
+</head>
+<body>
+
+<div id="toolbar">
+    <h1 id="title"></h1>
+    <input type="text" id="search" placeholder="Search locators and messages">
+    <input type="text" id="locatorFilter" placeholder="Locator RegExp">
+    <label><input type="checkbox" class="level" value="Info" checked> Info</label>
+    <label><input type="checkbox" class="level" value="Warning" checked> Warning</label>
+    <label><input type="checkbox" class="level" value="Error" checked> Error</label>
+    <details id="sources">
+        <summary id="sourcesSummary">Sources</summary>
+        <div id="sourceList"></div>
+    </details>
+    <button id="failuresButton" title="List the junit failures linked from this timeline"></button>
+    <button id="zoomOut" title="Zoom out">&minus;</button>
+    <button id="zoomReset" title="Show the whole run">Reset zoom</button>
+    <span id="status"></span>
+</div>
+
+<div id="main">
+    <div id="timeline">
+        <canvas id="overview" title="Drag to zoom, double click to reset"></canvas>
+        <canvas id="axis"></canvas>
+        <div id="rows">
+            <canvas id="rowsCanvas"></canvas>
+            <div id="rowsSpacer"></div>
+        </div>
+    </div>
+    <div id="details"></div>
+</div>
+<div id="tooltip"></div>
+
+<script>
+    var timelineData = TIMELINE_VIEWER_DATA_GOES_HERE
+</script>
+
+<script>
+    const LABEL_WIDTH = 320;
+    const ROW_HEIGHT = 16;
+    const LEVEL_COLORS = {Info: "#4e79a7", Warning: "#f28e2b", Error: "#e15759"};
+    const PENDING_COLORS = {Info: "#a0bbd6", Warning: "#f8c596", Error: "#f0abac"};
+    const TICK_STEPS = [1, 2, 5, 10, 15, 30, 60, 120, 300, 600, 900, 1800, 3600, 7200, 10800, 21600, 43200, 86400].map(s => s * 1000);
+
+    const intervals = timelineData.intervals;
+    const junits = timelineData.junits;
+    const resources = timelineData.resources;
+
+    let runStart = Infinity;
+    let runEnd = -Infinity;
+    intervals.forEach((interval, i) => {
+        interval.index = i;
+        interval.fromMs = Date.parse(interval.from);
+        interval.toMs = interval.to ? Date.parse(interval.to) : NaN;
+        interval.locatorString = locatorString(interval.locator);
+        interval.messageString = messageString(interval.message);
+        interval.searchText = (interval.source + " " + interval.locatorString + " " + interval.messageString).toLowerCase();
+        runStart = Math.min(runStart, interval.fromMs);
+        runEnd = Math.max(runEnd, interval.fromMs, isNaN(interval.toMs) ? -Infinity : interval.toMs);
+    });
+    if (!isFinite(runStart)) {
+        runStart = Date.now();
+        runEnd = runStart + 60000;
+    }
+    if (runEnd <= runStart) {
+        runEnd = runStart + 1000;
+    }
+    // intervals that never ended are drawn until the end of the run.
+    intervals.forEach(interval => {
+        interval.endMs = isNaN(interval.toMs) ? runEnd : interval.toMs;
+    });
+    const junitIntervals = junits.map(() => []);
+    intervals.forEach(interval => (interval.junits || []).forEach(j => junitIntervals[j].push(interval)));
+
+    const state = {
+        search: "",
+        locatorRegex: null,
+        levels: new Set(["Info", "Warning", "Error"]),
+        sources: new Set(),
+        junit: null,
+        viewStart: runStart,
+        viewEnd: runEnd,
+        selected: null,
+        rows: [],
+        filtered: [],
+    };
+    const allSources = Array.from(new Set(intervals.map(i => i.source))).sort();
+    allSources.forEach(s => state.sources.add(s));
+
+    function locatorString(locator) {
+        const keys = Object.keys(locator.keys || {}).sort((a, b) => {
+            if (a === "namespace") return -1;
+            if (b === "namespace") return 1;
+            return a < b ? -1 : a > b ? 1 : 0;
+        });
+        return keys.map(k => k + "/" + locator.keys[k]).join(" ");
+    }
+
+    function messageString(message) {
+        const annotations = Object.keys(message.annotations || {}).sort().map(k => k + "/" + message.annotations[k]);
+        return (annotations.join(" ") + " " + (message.humanMessage || "")).trim();
+    }
+
+    function formatTime(ms) {
+        return new Date(ms).toISOString().replace("T", " ").replace("Z", "");
+    }
+
+    function formatDuration(ms) {
+        const seconds = Math.round(ms / 1000);
+        if (seconds < 60) return seconds + "s";
+        const minutes = Math.floor(seconds / 60);
+        if (minutes < 60) return minutes + "m" + (seconds % 60) + "s";
+        return Math.floor(minutes / 60) + "h" + (minutes % 60) + "m";
+    }
+
+    function escapeRegExp(s) {
+        return s.replace(/[.*+?^${}()|[\]\\]/g, "\\$&");
+    }
+
+    function el(tag, attributes, ...children) {
+        const node = document.createElement(tag);
+        Object.entries(attributes || {}).forEach(([k, v]) => {
+            if (k.startsWith("on")) {
+                node.addEventListener(k.substring(2), v);
+            } else {
+                node.setAttribute(k, v);
+            }
+        });
+        children.flat().forEach(child => {
+            if (child === null || child === undefined) return;
+            node.appendChild(typeof child === "string" ? document.createTextNode(child) : child);
+        });
+        return node;
+    }
+
+    function link(text, onclick) {
+        return el("a", {onclick: e => { e.preventDefault(); onclick(); }}, text);
+    }
+
+    function table(rows) {
+        return el("table", {}, rows.filter(r => r[1] !== undefined && r[1] !== "").map(([k, v]) => el("tr", {}, el("td", {}, k), el("td", {}, v))));
+    }
+
+    // filtering
+
+    function matchesFilters(interval) {
+        if (!state.levels.has(interval.level)) return false;
+        if (!state.sources.has(interval.source)) return false;
+        if (state.junit !== null && !(interval.junits || []).includes(state.junit)) return false;
+        if (state.locatorRegex && !state.locatorRegex.test(interval.locatorString)) return false;
+        if (state.search && !interval.searchText.includes(state.search)) return false;
+        return true;
+    }
+
+    function applyFilters() {
+        state.filtered = intervals.filter(matchesFilters);
+        const bySource = new Map();
+        state.filtered.forEach(interval => {
+            if (!bySource.has(interval.source)) bySource.set(interval.source, new Map());
+            const byLocator = bySource.get(interval.source);
+            if (!byLocator.has(interval.locatorString)) byLocator.set(interval.locatorString, []);
+            byLocator.get(interval.locatorString).push(interval);
+        });
+        state.rows = [];
+        Array.from(bySource.keys()).sort().forEach(source => {
+            const byLocator = bySource.get(source);
+            state.rows.push({source: source, label: source + " (" + byLocator.size + ")"});
+            Array.from(byLocator.keys()).sort().forEach(locator => {
+                state.rows.push({label: locator || "<no locator>", intervals: byLocator.get(locator)});
+            });
+        });
+        document.getElementById("rowsSpacer").style.height = Math.max(0, state.rows.length * ROW_HEIGHT - rowsElement.clientHeight) + "px";
+
+        let status = state.filtered.length + " of " + intervals.length + " intervals";
+        if (state.junit !== null) {
+            status += ", linked to " + junits[state.junit].name;
+        }
+        document.getElementById("status").textContent = status;
+        draw();
+    }
+
+    // drawing
+
+    const overview = document.getElementById("overview");
+    const axis = document.getElementById("axis");
+    const rowsCanvas = document.getElementById("rowsCanvas");
+    const rowsElement = document.getElementById("rows");
+    const tooltip = document.getElementById("tooltip");
+
+    function resizeCanvas(canvas, width, height) {
+        const ratio = window.devicePixelRatio || 1;
+        canvas.width = Math.floor(width * ratio);
+        canvas.height = Math.floor(height * ratio);
+        canvas.style.width = width + "px";
+        canvas.style.height = height + "px";
+        const context = canvas.getContext("2d");
+        context.setTransform(ratio, 0, 0, ratio, 0, 0);
+        return context;
+    }
+
+    function chartWidth() {
+        return Math.max(1, rowsElement.clientWidth - LABEL_WIDTH);
+    }
+
+    function timeToX(ms) {
+        return LABEL_WIDTH + (ms - state.viewStart) / (state.viewEnd - state.viewStart) * chartWidth();
+    }
+
+    function xToTime(x) {
+        return state.viewStart + (x - LABEL_WIDTH) / chartWidth() * (state.viewEnd - state.viewStart);
+    }
+
+    function overviewTimeToX(ms) {
+        return LABEL_WIDTH + (ms - runStart) / (runEnd - runStart) * chartWidth();
+    }
+
+    function overviewXToTime(x) {
+        return runStart + (x - LABEL_WIDTH) / chartWidth() * (runEnd - runStart);
+    }
+
+    function colorFor(interval) {
+        if (interval.message.annotations && interval.message.annotations.alertstate === "pending") {
+            return PENDING_COLORS[interval.level] || "#bbb";
+        }
+        return LEVEL_COLORS[interval.level] || "#888";
+    }
+
+    function draw() {
+        drawOverview();
+        drawAxis();
+        drawRows();
+    }
+
+    function drawOverview() {
+        const width = rowsElement.clientWidth;
+        const context = resizeCanvas(overview, width, 40);
+        context.fillStyle = "#6c757d";
+        context.font = "11px sans-serif";
+        context.textBaseline = "middle";
+        context.fillText("whole run, drag to zoom", 6, 20);
+
+        // a histogram of the filtered intervals active in each pixel column, stacked by level.
+        const bins = chartWidth();
+        const counts = {Info: new Float64Array(bins), Warning: new Float64Array(bins), Error: new Float64Array(bins)};
+        state.filtered.forEach(interval => {
+            const levelCounts = counts[interval.level];
+            if (!levelCounts) return;
+            const first = Math.max(0, Math.floor(overviewTimeToX(interval.fromMs) - LABEL_WIDTH));
+            const last = Math.min(bins - 1, Math.floor(overviewTimeToX(interval.endMs) - LABEL_WIDTH));
+            for (let i = first; i <= last; i++) levelCounts[i]++;
+        });
+        let max = 1;
+        for (let i = 0; i < bins; i++) max = Math.max(max, counts.Info[i] + counts.Warning[i] + counts.Error[i]);
+        for (let i = 0; i < bins; i++) {
+            let y = 40;
+            ["Info", "Warning", "Error"].forEach(level => {
+                const height = counts[level][i] / max * 36;
+                if (height <= 0) return;
+                context.fillStyle = LEVEL_COLORS[level];
+                context.fillRect(LABEL_WIDTH + i, y - height, 1, height);
+                y -= height;
+            });
+        }
+
+        // the current view, and the brush while dragging.
+        const viewX1 = overviewTimeToX(state.viewStart);
+        const viewX2 = overviewTimeToX(state.viewEnd);
+        context.fillStyle = "rgba(52, 58, 64, .12)";
+        context.fillRect(viewX1, 0, Math.max(1, viewX2 - viewX1), 40);
+        context.strokeStyle = "#343a40";
+        context.strokeRect(viewX1 + .5, .5, Math.max(1, viewX2 - viewX1 - 1), 39);
+        if (brush && brush.canvas === overview) {
+            context.fillStyle = "rgba(3, 102, 214, .25)";
+            context.fillRect(Math.min(brush.x1, brush.x2), 0, Math.abs(brush.x2 - brush.x1), 40);
+        }
+    }
+
+    function drawAxis() {
+        const width = rowsElement.clientWidth;
+        const context = resizeCanvas(axis, width, 20);
+        context.fillStyle = "#6c757d";
+        context.font = "11px sans-serif";
+        context.textBaseline = "middle";
+        context.fillText(formatDuration(state.viewEnd - state.viewStart) + " shown, times in UTC", 6, 10);
+
+        const span = state.viewEnd - state.viewStart;
+        const maxTicks = Math.max(1, chartWidth() / 90);
+        const step = TICK_STEPS.find(s => span / s <= maxTicks) || TICK_STEPS[TICK_STEPS.length - 1];
+        context.strokeStyle = "#adb5bd";
+        for (let t = Math.ceil(state.viewStart / step) * step; t <= state.viewEnd; t += step) {
+            const x = timeToX(t);
+            context.beginPath();
+            context.moveTo(x + .5, 14);
+            context.lineTo(x + .5, 20);
+            context.stroke();
+            context.fillStyle = "#495057";
+            context.fillText(new Date(t).toISOString().substring(11, 19), x + 3, 8);
+        }
+    }
+
+    function drawRows() {
+        const width = rowsElement.clientWidth;
+        const height = rowsElement.clientHeight;
+        const context = resizeCanvas(rowsCanvas, width, height);
+        context.font = "11px sans-serif";
+        context.textBaseline = "middle";
+
+        const scrollTop = rowsElement.scrollTop;
+        const first = Math.floor(scrollTop / ROW_HEIGHT);
+        const last = Math.min(state.rows.length - 1, Math.ceil((scrollTop + height) / ROW_HEIGHT));
+        for (let r = first; r <= last; r++) {
+            const row = state.rows[r];
+            const y = r * ROW_HEIGHT - scrollTop;
+            if (row.source !== undefined) {
+                context.fillStyle = "#e9ecef";
+                context.fillRect(0, y, width, ROW_HEIGHT);
+                context.fillStyle = "#212529";
+                context.font = "bold 11px sans-serif";
+                context.fillText(row.label, 4, y + ROW_HEIGHT / 2);
+                context.font = "11px sans-serif";
+                continue;
+            }
+            if (r % 2 === 0) {
+                context.fillStyle = "#fafafa";
+                context.fillRect(0, y, width, ROW_HEIGHT);
+            }
+
+            context.save();
+            context.beginPath();
+            context.rect(LABEL_WIDTH, y, width - LABEL_WIDTH, ROW_HEIGHT);
+            context.clip();
+            row.intervals.forEach(interval => {
+                const x1 = timeToX(interval.fromMs);
+                const x2 = timeToX(interval.endMs);
+                if (x2 < LABEL_WIDTH - 2 || x1 > width) return;
+                context.fillStyle = colorFor(interval);
+                context.fillRect(x1, y + 2, Math.max(2, x2 - x1), ROW_HEIGHT - 4);
+                if (interval.junits) {
+                    context.fillStyle = "#a50f15";
+                    context.fillRect(x1, y + ROW_HEIGHT - 4, Math.max(2, x2 - x1), 2);
+                }
+                if (interval === state.selected) {
+                    context.strokeStyle = "#000";
+                    context.lineWidth = 2;
+                    context.strokeRect(x1 - 1, y + 1, Math.max(2, x2 - x1) + 2, ROW_HEIGHT - 2);
+                    context.lineWidth = 1;
+                }
+            });
+            context.restore();
+
+            context.save();
+            context.beginPath();
+            context.rect(0, y, LABEL_WIDTH - 4, ROW_HEIGHT);
+            context.clip();
+            context.fillStyle = "#212529";
+            context.fillText(row.label, 12, y + ROW_HEIGHT / 2);
+            context.restore();
+        }
+
+        if (brush && brush.canvas === rowsCanvas) {
+            context.fillStyle = "rgba(3, 102, 214, .2)";
+            context.fillRect(Math.min(brush.x1, brush.x2), 0, Math.abs(brush.x2 - brush.x1), height);
+        }
+        context.strokeStyle = "#dee2e6";
+        context.beginPath();
+        context.moveTo(LABEL_WIDTH - .5, 0);
+        context.lineTo(LABEL_WIDTH - .5, height);
+        context.stroke();
+    }
+
+    // zooming
+
+    function setView(start, end) {
+        const minSpan = 1000;
+        if (end - start < minSpan) {
+            const middle = (start + end) / 2;
+            start = middle - minSpan / 2;
+            end = middle + minSpan / 2;
+        }
+        state.viewStart = Math.max(runStart, start);
+        state.viewEnd = Math.min(runEnd, end);
+        if (state.viewEnd <= state.viewStart) {
+            state.viewStart = runStart;
+            state.viewEnd = runEnd;
+        }
+        draw();
+    }
+
+    function zoom(factor, aroundMs) {
+        const span = (state.viewEnd - state.viewStart) * factor;
+        const ratio = (aroundMs - state.viewStart) / (state.viewEnd - state.viewStart);
+        let start = aroundMs - span * ratio;
+        let end = start + span;
+        if (start < runStart) {
+            end += runStart - start;
+            start = runStart;
+        }
+        if (end > runEnd) {
+            start -= end - runEnd;
+            end = runEnd;
+        }
+        setView(start, end);
+    }
+
+    let brush = null;
+
+    function startBrush(canvas, e) {
+        const x = e.clientX - canvas.getBoundingClientRect().left;
+        if (x < LABEL_WIDTH) return;
+        brush = {canvas: canvas, x1: x, x2: x};
+    }
+
+    window.addEventListener("mousemove", e => {
+        if (!brush) return;
+        brush.x2 = Math.max(LABEL_WIDTH, Math.min(brush.canvas.getBoundingClientRect().width, e.clientX - brush.canvas.getBoundingClientRect().left));
+        draw();
+    });
+
+    window.addEventListener("mouseup", e => {
+        if (!brush) return;
+        const current = brush;
+        brush = null;
+        if (Math.abs(current.x2 - current.x1) < 4) {
+            draw();
+            if (current.canvas === rowsCanvas) selectAt(e);
+            return;
+        }
+        const toTime = current.canvas === overview ? overviewXToTime : xToTime;
+        setView(toTime(Math.min(current.x1, current.x2)), toTime(Math.max(current.x1, current.x2)));
+    });
+
+    overview.addEventListener("mousedown", e => startBrush(overview, e));
+    overview.addEventListener("dblclick", () => setView(runStart, runEnd));
+    rowsCanvas.addEventListener("mousedown", e => startBrush(rowsCanvas, e));
+    rowsCanvas.addEventListener("mousedown", e => {
+        if (e.clientX - rowsCanvas.getBoundingClientRect().left < LABEL_WIDTH) selectAt(e);
+    });
+    rowsCanvas.addEventListener("wheel", e => {
+        if (!(e.ctrlKey || e.metaKey || e.shiftKey)) return;
+        e.preventDefault();
+        const x = e.clientX - rowsCanvas.getBoundingClientRect().left;
+        if (e.shiftKey && !(e.ctrlKey || e.metaKey)) {
+            const shift = (e.deltaY || e.deltaX) / chartWidth() * (state.viewEnd - state.viewStart);
+            const span = state.viewEnd - state.viewStart;
+            const start = Math.max(runStart, Math.min(runEnd - span, state.viewStart + shift));
+            setView(start, start + span);
+            return;
+        }
+        zoom(e.deltaY > 0 ? 1.25 : 0.8, xToTime(Math.max(LABEL_WIDTH, x)));
+    }, {passive: false});
+    document.getElementById("zoomOut").addEventListener("click", () => zoom(2, (state.viewStart + state.viewEnd) / 2));
+    document.getElementById("zoomReset").addEventListener("click", () => setView(runStart, runEnd));
+    rowsElement.addEventListener("scroll", drawRows);
+    window.addEventListener("resize", applyFilters);
+
+    // selection and details
+
+    function intervalAt(e) {
+        const bounds = rowsCanvas.getBoundingClientRect();
+        const x = e.clientX - bounds.left;
+        const row = state.rows[Math.floor((e.clientY - bounds.top + rowsElement.scrollTop) / ROW_HEIGHT)];
+        if (!row) return {};
+        if (x < LABEL_WIDTH || !row.intervals) return {row: row};
+        const tolerance = 3 / chartWidth() * (state.viewEnd - state.viewStart);
+        const t = xToTime(x);
+        const matches = row.intervals.filter(i => i.fromMs - tolerance <= t && t <= i.endMs + tolerance);
+        return {row: row, interval: matches[matches.length - 1]};
+    }
+
+    function selectAt(e) {
+        const hit = intervalAt(e);
+        if (hit.interval) {
+            showInterval(hit.interval);
+        } else if (hit.row && hit.row.intervals) {
+            showLocator(hit.row);
+        }
+    }
+
+    rowsCanvas.addEventListener("mousemove", e => {
+        const hit = intervalAt(e);
+        if (brush || !hit.row) {
+            tooltip.style.display = "none";
+            return;
+        }
+        let text = hit.row.label;
+        if (hit.interval) {
+            const interval = hit.interval;
+            text = formatTime(interval.fromMs) + " (" + formatDuration(interval.endMs - interval.fromMs) + ") " + interval.level + "\n" +
+                interval.locatorString + "\n" + interval.messageString;
+        }
+        tooltip.textContent = text;
+        tooltip.style.display = "block";
+        tooltip.style.left = Math.min(e.clientX + 12, window.innerWidth - tooltip.offsetWidth - 4) + "px";
+        tooltip.style.top = (e.clientY + 14) + "px";
+    });
+    rowsCanvas.addEventListener("mouseleave", () => tooltip.style.display = "none");
+
+    function showDetails(...children) {
+        const details = document.getElementById("details");
+        details.innerHTML = "";
+        children.flat().forEach(child => child && details.appendChild(child));
+        details.scrollTop = 0;
+    }
+
+    function showInterval(interval) {
+        state.selected = interval;
+        drawRows();
+
+        const message = interval.message;
+        const locatorRows = Object.keys(interval.locator.keys || {}).sort().map(k => [k, interval.locator.keys[k]]);
+        const annotationRows = Object.keys(message.annotations || {}).sort().map(k => [k, message.annotations[k]]);
+        const related = (interval.junits || []).map(j => el("div", {class: junits[j].flake ? "flake" : "failure"},
+            link(junits[j].name, () => showJUnit(j)), junits[j].flake ? " (flake)" : ""));
+
+        showDetails(
+            el("h2", {}, interval.locatorString || "<no locator>"),
+            table([
+                ["source", interval.source],
+                ["level", interval.level],
+                ["from", formatTime(interval.fromMs)],
+                ["to", isNaN(interval.toMs) ? "never ended" : formatTime(interval.toMs)],
+                ["duration", formatDuration(interval.endMs - interval.fromMs)],
+                ["display", interval.display ? "true" : "false"],
+            ]),
+            el("div", {}, link("Filter to this locator", () => {
+                document.getElementById("locatorFilter").value = "^" + escapeRegExp(interval.locatorString) + "$";
+                onLocatorFilter();
+            }), " ", link("Zoom to this interval", () => {
+                const pad = Math.max(5000, (interval.endMs - interval.fromMs) * .1);
+                setView(interval.fromMs - pad, interval.endMs + pad);
+            })),
+            el("h3", {}, "Locator"),
+            table([["type", interval.locator.type]].concat(locatorRows)),
+            el("h3", {}, "Message"),
+            table([["reason", message.reason], ["cause", message.cause]].concat(annotationRows)),
+            message.humanMessage ? el("pre", {}, message.humanMessage) : null,
+            el("h3", {}, "JUnit failures"),
+            related.length > 0 ? related : el("div", {class: "hint"}, "No junit failure mentions this interval."),
+            el("h3", {}, "Tracked resource"),
+            interval.resource !== undefined ?
+                el("div", {}, link(resourceName(interval.resource), () => showResource(interval.resource, interval))) :
+                el("div", {class: "hint"}, "No tracked resource matches this locator."),
+        );
+    }
+
+    function showLocator(row) {
+        showDetails(
+            el("h2", {}, row.label),
+            el("div", {}, row.intervals.length + " intervals."),
+            el("div", {}, link("Filter to this locator", () => {
+                document.getElementById("locatorFilter").value = "^" + escapeRegExp(row.intervals[0].locatorString) + "$";
+                onLocatorFilter();
+            })),
+            el("h3", {}, "Intervals"),
+            row.intervals.slice(0, 500).map(interval => el("div", {},
+                link(formatTime(interval.fromMs), () => showInterval(interval)), " " + interval.level + " " + interval.messageString)),
+        );
+    }
+
+    function showJUnit(j) {
+        const junit = junits[j];
+        const linked = junitIntervals[j];
+        showDetails(
+            el("h2", {class: junit.flake ? "flake" : "failure"}, junit.name),
+            el("div", {}, junit.flake ? "Failed, then passed, so this is a flake." : "Failed."),
+            el("div", {}, linked.length > 0 ?
+                link("Show only the " + linked.length + " intervals linked to this failure", () => {
+                    state.junit = j;
+                    applyFilters();
+                    showJUnit(j);
+                }) :
+                el("span", {class: "hint"}, "No interval is linked to this failure.")),
+            state.junit !== null ? el("div", {}, link("Show all intervals again", () => {
+                state.junit = null;
+                applyFilters();
+                showJUnit(j);
+            })) : null,
+            el("pre", {}, junit.output || ""),
+        );
+    }
+
+    function resourceName(r) {
+        const resource = resources[r];
+        return resource.type + " " + (resource.namespace ? resource.namespace + "/" : "") + resource.name;
+    }
+
+    function showResource(r, fromInterval) {
+        showDetails(
+            el("h2", {}, resourceName(r)),
+            fromInterval ? el("div", {}, link("Back to the interval", () => showInterval(fromInterval))) : null,
+            resources[r].omitted ?
+                el("div", {class: "hint"}, "The resource was left out to keep this page small, it is in the tracked resources of the run.") :
+                el("pre", {}, JSON.stringify(resources[r].object, null, 2)),
+        );
+    }
+
+    function showFailures() {
+        showDetails(
+            el("h2", {}, junits.length + " junit failures"),
+            junits.length === 0 ? el("div", {class: "hint"}, "No junit failures were provided.") : null,
+            junits.map((junit, j) => el("div", {class: junit.flake ? "flake" : "failure"},
+                link(junit.name, () => showJUnit(j)), " (" + junitIntervals[j].length + " intervals)" + (junit.flake ? " flake" : ""))),
+        );
+    }
+
+    // toolbar
+
+    function debounce(fn) {
+        let timeout;
+        return () => {
+            clearTimeout(timeout);
+            timeout = setTimeout(fn, 250);
+        };
+    }
+
+    function onLocatorFilter() {
+        const input = document.getElementById("locatorFilter");
+        try {
+            state.locatorRegex = input.value ? new RegExp(input.value) : null;
+            input.classList.remove("invalid");
+        } catch (err) {
+            input.classList.add("invalid");
+            return;
+        }
+        applyFilters();
+    }
+
+    document.getElementById("search").addEventListener("input", debounce(() => {
+        state.search = document.getElementById("search").value.toLowerCase();
+        applyFilters();
+    }));
+    document.getElementById("locatorFilter").addEventListener("input", debounce(onLocatorFilter));
+    document.querySelectorAll("input.level").forEach(input => input.addEventListener("change", () => {
+        input.checked ? state.levels.add(input.value) : state.levels.delete(input.value);
+        applyFilters();
+    }));
+
+    function updateSourcesSummary() {
+        document.getElementById("sourcesSummary").textContent = "Sources (" + state.sources.size + "/" + allSources.length + ")";
+    }
+
+    const sourceList = document.getElementById("sourceList");
+    sourceList.appendChild(el("div", {},
+        link("all", () => setSources(allSources)), " ", link("none", () => setSources([]))));
+    sourceList.querySelectorAll("a").forEach(a => a.style.cursor = "pointer");
+    allSources.forEach(source => {
+        const count = intervals.filter(i => i.source === source).length;
+        const input = el("input", {type: "checkbox", class: "source", value: source});
+        input.checked = true;
+        input.addEventListener("change", () => {
+            input.checked ? state.sources.add(source) : state.sources.delete(source);
+            updateSourcesSummary();
+            applyFilters();
+        });
+        sourceList.appendChild(el("label", {}, input, " " + (source || "<none>") + " (" + count + ")"));
+    });
+
+    function setSources(sources) {
+        state.sources = new Set(sources);
+        sourceList.querySelectorAll("input.source").forEach(input => input.checked = state.sources.has(input.value));
+        updateSourcesSummary();
+        applyFilters();
+    }
+
+    const failuresButton = document.getElementById("failuresButton");
+    failuresButton.textContent = "Failures (" + junits.length + ")";
+    failuresButton.addEventListener("click", showFailures);
+
+    document.getElementById("title").textContent = timelineData.title;
+    updateSourcesSummary();
+    applyFilters();
+    showDetails(
+        el("h2", {}, timelineData.title),
+        el("div", {class: "hint"}, "Click an interval for its details, related junit failures, and tracked resource.  " +
+            "Drag across the overview or the timeline to zoom, ctrl+scroll to zoom, and shift+scroll to pan.  " +
+            "Intervals underlined in red are mentioned by a junit failure."),
+    );
+</script>
+</body>
+</html>
+`)
+
+func e2echartTimelineViewerHtmlBytes() ([]byte, error) {
+	return _e2echartTimelineViewerHtml, nil
+}
+
+func e2echartTimelineViewerHtml() (*asset, error) {
+	bytes, err := e2echartTimelineViewerHtmlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "e2echart/timeline-viewer.html", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -55271,6 +56010,7 @@ var _bindata = map[string]func() (*asset, error){
 	"e2echart/e2e-chart-template.html":                                                                       e2echartE2eChartTemplateHtml,
 	"e2echart/non-spyglass-e2e-chart-template.html":                                                          e2echartNonSpyglassE2eChartTemplateHtml,
 	"e2echart/test-risk-analysis.html":                                                                       e2echartTestRiskAnalysisHtml,
+	"e2echart/timeline-viewer.html":                                                                          e2echartTimelineViewerHtml,
 }
 
 // AssetDir returns the file names below a certain
@@ -55320,6 +56060,7 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"e2e-chart-template.html":              {e2echartE2eChartTemplateHtml, map[string]*bintree{}},
 		"non-spyglass-e2e-chart-template.html": {e2echartNonSpyglassE2eChartTemplateHtml, map[string]*bintree{}},
 		"test-risk-analysis.html":              {e2echartTestRiskAnalysisHtml, map[string]*bintree{}},
+		"timeline-viewer.html":                 {e2echartTimelineViewerHtml, map[string]*bintree{}},
 	}},
 	"examples": {nil, map[string]*bintree{
 		"db-templates": {nil, map[string]*bintree{
@@ -0,0 +1,89 @@
+package brokentests
+
+import (
+	"regexp"
+
+	"github.com/openshift/origin/pkg/exceptions"
+)
+
+// BrokenTest marks the tests matching Pattern [Disabled:Broken] until the bug is fixed.
+type BrokenTest struct {
+	// Pattern is a regular expression matched against test names.
+	Pattern string
+
+	exceptions.Exception
+}
+
+// BrokenTests are the tests that are known broken and need to be fixed upstream or in openshift.  Always add an
+// owner, a bug, and an expiry date, expired entries are reported as a flake when the suite runs.
+var BrokenTests = []BrokenTest{
+	{
+		// idling with a single service and DeploymentConfig
+		Pattern:   `should idle the service and DeploymentConfig properly`,
+		Exception: exceptions.Exception{Owner: "sig-network-edge", Bug: "https://issues.redhat.com/issues/?jql=project%20%3D%20OCPBUGS%20AND%20text%20~%20%22should%20idle%20the%20service%20and%20DeploymentConfig%20properly%22", Expires: "2027-01-31"},
+	},
+	{
+		// currently not supported by dns operator
+		Pattern:   `should answer endpoint and wildcard queries for the cluster`,
+		Exception: exceptions.Exception{Owner: "sig-network-edge", Bug: "https://github.com/openshift/cluster-dns-operator/issues/43", Expires: "2027-01-31"},
+	},
+	{
+		Pattern:   `\[Feature:GenericEphemeralVolume\]`,
+		Exception: exceptions.Exception{Owner: "sig-storage", Bug: "https://bugzilla.redhat.com/show_bug.cgi?id=1945091", Expires: "2027-01-31"},
+	},
+	{
+		Pattern:   `\[sig-network\] \[Feature:IPv6DualStack\] should have ipv4 and ipv6 node podCIDRs`,
+		Exception: exceptions.Exception{Owner: "sig-network", Bug: "https://bugzilla.redhat.com/show_bug.cgi?id=1996128", Expires: "2027-01-31"},
+	},
+	{
+		Pattern:   `\[sig-network-edge\]\[Feature:Idling\] Unidling \[apigroup:apps.openshift.io\]\[apigroup:route.openshift.io\] should work with TCP \(while idling\)`,
+		Exception: exceptions.Exception{Owner: "sig-network-edge", Bug: "https://bugzilla.redhat.com/show_bug.cgi?id=2004074", Expires: "2027-01-31"},
+	},
+	{
+		Pattern:   `\[sig-network\]\[Feature:EgressIP\]\[apigroup:operator.openshift.io\] \[internal-targets\]`,
+		Exception: exceptions.Exception{Owner: "sig-network", Bug: "https://bugzilla.redhat.com/show_bug.cgi?id=2070929", Expires: "2027-01-31"},
+	},
+	{
+		Pattern:   `\[sig-network\] IngressClass \[Feature:Ingress\] should prevent Ingress creation if more than 1 IngressClass marked as default`,
+		Exception: exceptions.Exception{Owner: "sig-network-edge", Bug: "https://issues.redhat.com/browse/OCPBUGS-967", Expires: "2027-01-31"},
+	},
+	{
+		Pattern:   `\[sig-devex\]\[Feature:ImageEcosystem\]\[mysql\]\[Slow\] openshift mysql image Creating from a template should instantiate the template`,
+		Exception: exceptions.Exception{Owner: "sig-devex", Bug: "https://issues.redhat.com/browse/OCPBUGS-3339", Expires: "2027-01-31"},
+	},
+	{
+		Pattern:   `\[sig-devex\]\[Feature:ImageEcosystem\]\[mariadb\]\[Slow\] openshift mariadb image Creating from a template should instantiate the template`,
+		Exception: exceptions.Exception{Owner: "sig-devex", Bug: "https://issues.redhat.com/browse/OCPBUGS-3339", Expires: "2027-01-31"},
+	},
+}
+
+// Patterns returns the pattern of every broken test for the annotation rules.
+func Patterns() []string {
+	ret := []string{}
+	for _, brokenTest := range BrokenTests {
+		ret = append(ret, brokenTest.Pattern)
+	}
+	return ret
+}
+
+// Registry returns an exceptions.Registry of the broken tests, keyed by pattern, with the patterns that match one of
+// testNames marked as matched.
+func Registry(testNames []string) (*exceptions.Registry, error) {
+	registry := exceptions.NewRegistry("disabled broken test")
+	for _, brokenTest := range BrokenTests {
+		if err := registry.Add(brokenTest.Pattern, brokenTest.Exception); err != nil {
+			return nil, err
+		}
+		re, err := regexp.Compile(brokenTest.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range testNames {
+			if re.MatchString(name) {
+				registry.Matched(brokenTest.Pattern)
+				break
+			}
+		}
+	}
+	return registry, nil
+}
@@ -0,0 +1,29 @@
+package brokentests
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBrokenTestsAreValid(t *testing.T) {
+	if _, err := Registry(nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegistryMatchesTestNames(t *testing.T) {
+	registry, err := Registry([]string{
+		"[sig-storage] Ephemeralstorage [Feature:GenericEphemeralVolume] should work [Disabled:Broken]",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stale := range registry.Stale() {
+		if strings.HasPrefix(stale, `\[Feature:GenericEphemeralVolume\] `) {
+			t.Errorf("expected the matching pattern not to be stale: %s", stale)
+		}
+	}
+	if len(registry.Stale()) != len(BrokenTests)-1 {
+		t.Errorf("expected %d stale patterns, got %d", len(BrokenTests)-1, len(registry.Stale()))
+	}
+}
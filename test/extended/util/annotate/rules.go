@@ -1,5 +1,7 @@
 package main
 
+import "github.com/openshift/origin/test/extended/util/annotate/brokentests"
+
 // Rules defined here are additive to the rules already defined for
 // kube e2e tests in openshift/kubernetes. The kube rules are
 // vendored via the following file:
@@ -30,30 +32,8 @@ var (
 			`\[Feature:ImageQuota\]`, // Quota isn't turned on by default, we should do that and then reenable these tests
 		},
 		// tests that are known broken and need to be fixed upstream or in openshift
-		// always add an issue here
-		"[Disabled:Broken]": {
-			`should idle the service and DeploymentConfig properly`,       // idling with a single service and DeploymentConfig
-			`should answer endpoint and wildcard queries for the cluster`, // currently not supported by dns operator https://github.com/openshift/cluster-dns-operator/issues/43
-
-			// https://bugzilla.redhat.com/show_bug.cgi?id=1945091
-			`\[Feature:GenericEphemeralVolume\]`,
-
-			// https://bugzilla.redhat.com/show_bug.cgi?id=1996128
-			`\[sig-network\] \[Feature:IPv6DualStack\] should have ipv4 and ipv6 node podCIDRs`,
-
-			// https://bugzilla.redhat.com/show_bug.cgi?id=2004074
-			`\[sig-network-edge\]\[Feature:Idling\] Unidling \[apigroup:apps.openshift.io\]\[apigroup:route.openshift.io\] should work with TCP \(while idling\)`,
-
-			// https://bugzilla.redhat.com/show_bug.cgi?id=2070929
-			`\[sig-network\]\[Feature:EgressIP\]\[apigroup:operator.openshift.io\] \[internal-targets\]`,
-
-			// https://issues.redhat.com/browse/OCPBUGS-967
-			`\[sig-network\] IngressClass \[Feature:Ingress\] should prevent Ingress creation if more than 1 IngressClass marked as default`,
-
-			// https://issues.redhat.com/browse/OCPBUGS-3339
-			`\[sig-devex\]\[Feature:ImageEcosystem\]\[mysql\]\[Slow\] openshift mysql image Creating from a template should instantiate the template`,
-			`\[sig-devex\]\[Feature:ImageEcosystem\]\[mariadb\]\[Slow\] openshift mariadb image Creating from a template should instantiate the template`,
-		},
+		// always add an owner, issue, and expiry in brokentests
+		"[Disabled:Broken]": brokentests.Patterns(),
 		// tests that may work, but we don't support them
 		"[Disabled:Unsupported]": {},
 		// tests too slow to be part of conformance
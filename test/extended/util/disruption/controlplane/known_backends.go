@@ -12,78 +12,80 @@ import (
 	"k8s.io/client-go/rest"
 )
 
-func StartAPIMonitoringUsingNewBackend(ctx context.Context, recorder monitorapi.Recorder, clusterConfig *rest.Config, lb backend.LoadBalancerType) error {
+// StartAPIMonitoringUsingNewBackend samples the kube and openshift apiservers,
+// a successful request slower than degradedLatencyThreshold is deemed degraded.
+func StartAPIMonitoringUsingNewBackend(ctx context.Context, recorder monitorapi.Recorder, clusterConfig *rest.Config, lb backend.LoadBalancerType, degradedLatencyThreshold time.Duration) error {
 	factory := disruptionci.NewDisruptionTestFactory(clusterConfig)
-	if err := startKubeAPIMonitoringWithNewConnectionsHTTP2(ctx, recorder, factory, lb); err != nil {
+	if err := startKubeAPIMonitoringWithNewConnectionsHTTP2(ctx, recorder, factory, lb, degradedLatencyThreshold); err != nil {
 		return err
 	}
-	if err := startKubeAPIMonitoringWithConnectionReuseHTTP2(ctx, recorder, factory, lb); err != nil {
+	if err := startKubeAPIMonitoringWithConnectionReuseHTTP2(ctx, recorder, factory, lb, degradedLatencyThreshold); err != nil {
 		return err
 	}
-	if err := startKubeAPIMonitoringWithNewConnectionsHTTP1(ctx, recorder, factory, lb); err != nil {
+	if err := startKubeAPIMonitoringWithNewConnectionsHTTP1(ctx, recorder, factory, lb, degradedLatencyThreshold); err != nil {
 		return err
 	}
-	if err := startKubeAPIMonitoringWithConnectionReuseHTTP1(ctx, recorder, factory, lb); err != nil {
+	if err := startKubeAPIMonitoringWithConnectionReuseHTTP1(ctx, recorder, factory, lb, degradedLatencyThreshold); err != nil {
 		return err
 	}
-	if err := startOpenShiftAPIMonitoringWithNewConnectionsHTTP2(ctx, recorder, factory, lb); err != nil {
+	if err := startOpenShiftAPIMonitoringWithNewConnectionsHTTP2(ctx, recorder, factory, lb, degradedLatencyThreshold); err != nil {
 		return err
 	}
-	if err := startOpenShiftAPIMonitoringWithConnectionReuseHTTP2(ctx, recorder, factory, lb); err != nil {
+	if err := startOpenShiftAPIMonitoringWithConnectionReuseHTTP2(ctx, recorder, factory, lb, degradedLatencyThreshold); err != nil {
 		return err
 	}
 	return nil
 }
 
-func startKubeAPIMonitoringWithNewConnectionsHTTP2(ctx context.Context, recorder monitorapi.Recorder, factory disruptionci.Factory, lb backend.LoadBalancerType) error {
-	backendSampler, err := createKubeAPIMonitoringWithNewConnectionsHTTP2(factory, lb)
+func startKubeAPIMonitoringWithNewConnectionsHTTP2(ctx context.Context, recorder monitorapi.Recorder, factory disruptionci.Factory, lb backend.LoadBalancerType, degradedLatencyThreshold time.Duration) error {
+	backendSampler, err := createKubeAPIMonitoringWithNewConnectionsHTTP2(factory, lb, degradedLatencyThreshold)
 	if err != nil {
 		return err
 	}
 	return backendSampler.StartEndpointMonitoring(ctx, recorder, nil)
 }
 
-func startKubeAPIMonitoringWithConnectionReuseHTTP2(ctx context.Context, recorder monitorapi.Recorder, factory disruptionci.Factory, lb backend.LoadBalancerType) error {
-	backendSampler, err := createKubeAPIMonitoringWithConnectionReuseHTTP2(factory, lb)
+func startKubeAPIMonitoringWithConnectionReuseHTTP2(ctx context.Context, recorder monitorapi.Recorder, factory disruptionci.Factory, lb backend.LoadBalancerType, degradedLatencyThreshold time.Duration) error {
+	backendSampler, err := createKubeAPIMonitoringWithConnectionReuseHTTP2(factory, lb, degradedLatencyThreshold)
 	if err != nil {
 		return err
 	}
 	return backendSampler.StartEndpointMonitoring(ctx, recorder, nil)
 }
 
-func startKubeAPIMonitoringWithNewConnectionsHTTP1(ctx context.Context, recorder monitorapi.Recorder, factory disruptionci.Factory, lb backend.LoadBalancerType) error {
-	backendSampler, err := createKubeAPIMonitoringWithNewConnectionsHTTP1(factory, lb)
+func startKubeAPIMonitoringWithNewConnectionsHTTP1(ctx context.Context, recorder monitorapi.Recorder, factory disruptionci.Factory, lb backend.LoadBalancerType, degradedLatencyThreshold time.Duration) error {
+	backendSampler, err := createKubeAPIMonitoringWithNewConnectionsHTTP1(factory, lb, degradedLatencyThreshold)
 	if err != nil {
 		return err
 	}
 	return backendSampler.StartEndpointMonitoring(ctx, recorder, nil)
 }
 
-func startKubeAPIMonitoringWithConnectionReuseHTTP1(ctx context.Context, recorder monitorapi.Recorder, factory disruptionci.Factory, lb backend.LoadBalancerType) error {
-	backendSampler, err := createKubeAPIMonitoringWithConnectionReuseHTTP1(factory, lb)
+func startKubeAPIMonitoringWithConnectionReuseHTTP1(ctx context.Context, recorder monitorapi.Recorder, factory disruptionci.Factory, lb backend.LoadBalancerType, degradedLatencyThreshold time.Duration) error {
+	backendSampler, err := createKubeAPIMonitoringWithConnectionReuseHTTP1(factory, lb, degradedLatencyThreshold)
 	if err != nil {
 		return err
 	}
 	return backendSampler.StartEndpointMonitoring(ctx, recorder, nil)
 }
 
-func startOpenShiftAPIMonitoringWithNewConnectionsHTTP2(ctx context.Context, recorder monitorapi.Recorder, factory disruptionci.Factory, lb backend.LoadBalancerType) error {
-	backendSampler, err := createOpenShiftAPIMonitoringWithNewConnectionsHTTP2(factory, lb)
+func startOpenShiftAPIMonitoringWithNewConnectionsHTTP2(ctx context.Context, recorder monitorapi.Recorder, factory disruptionci.Factory, lb backend.LoadBalancerType, degradedLatencyThreshold time.Duration) error {
+	backendSampler, err := createOpenShiftAPIMonitoringWithNewConnectionsHTTP2(factory, lb, degradedLatencyThreshold)
 	if err != nil {
 		return err
 	}
 	return backendSampler.StartEndpointMonitoring(ctx, recorder, nil)
 }
 
-func startOpenShiftAPIMonitoringWithConnectionReuseHTTP2(ctx context.Context, recorder monitorapi.Recorder, factory disruptionci.Factory, lb backend.LoadBalancerType) error {
-	backendSampler, err := createOpenShiftAPIMonitoringWithConnectionReuseHTTP2(factory, lb)
+func startOpenShiftAPIMonitoringWithConnectionReuseHTTP2(ctx context.Context, recorder monitorapi.Recorder, factory disruptionci.Factory, lb backend.LoadBalancerType, degradedLatencyThreshold time.Duration) error {
+	backendSampler, err := createOpenShiftAPIMonitoringWithConnectionReuseHTTP2(factory, lb, degradedLatencyThreshold)
 	if err != nil {
 		return err
 	}
 	return backendSampler.StartEndpointMonitoring(ctx, recorder, nil)
 }
 
-func createKubeAPIMonitoringWithNewConnectionsHTTP2(factory disruptionci.Factory, lb backend.LoadBalancerType) (disruptionci.Sampler, error) {
+func createKubeAPIMonitoringWithNewConnectionsHTTP2(factory disruptionci.Factory, lb backend.LoadBalancerType, degradedLatencyThreshold time.Duration) (disruptionci.Sampler, error) {
 	return factory.New(disruptionci.TestConfiguration{
 		TestDescriptor: disruptionci.TestDescriptor{
 			TargetServer:     disruptionci.KubeAPIServer,
@@ -94,11 +96,12 @@ func createKubeAPIMonitoringWithNewConnectionsHTTP2(factory disruptionci.Factory
 		Path:                         "/api/v1/namespaces/default",
 		Timeout:                      15 * time.Second,
 		SampleInterval:               time.Second,
+		DegradedLatencyThreshold:     degradedLatencyThreshold,
 		EnableShutdownResponseHeader: true,
 	})
 }
 
-func createKubeAPIMonitoringWithConnectionReuseHTTP2(factory disruptionci.Factory, lb backend.LoadBalancerType) (disruptionci.Sampler, error) {
+func createKubeAPIMonitoringWithConnectionReuseHTTP2(factory disruptionci.Factory, lb backend.LoadBalancerType, degradedLatencyThreshold time.Duration) (disruptionci.Sampler, error) {
 	return factory.New(disruptionci.TestConfiguration{
 		TestDescriptor: disruptionci.TestDescriptor{
 			TargetServer:     disruptionci.KubeAPIServer,
@@ -109,11 +112,12 @@ func createKubeAPIMonitoringWithConnectionReuseHTTP2(factory disruptionci.Factor
 		Path:                         "/api/v1/namespaces/default",
 		Timeout:                      15 * time.Second,
 		SampleInterval:               time.Second,
+		DegradedLatencyThreshold:     degradedLatencyThreshold,
 		EnableShutdownResponseHeader: true,
 	})
 }
 
-func createKubeAPIMonitoringWithNewConnectionsHTTP1(factory disruptionci.Factory, lb backend.LoadBalancerType) (disruptionci.Sampler, error) {
+func createKubeAPIMonitoringWithNewConnectionsHTTP1(factory disruptionci.Factory, lb backend.LoadBalancerType, degradedLatencyThreshold time.Duration) (disruptionci.Sampler, error) {
 	return factory.New(disruptionci.TestConfiguration{
 		TestDescriptor: disruptionci.TestDescriptor{
 			TargetServer:     disruptionci.KubeAPIServer,
@@ -124,11 +128,12 @@ func createKubeAPIMonitoringWithNewConnectionsHTTP1(factory disruptionci.Factory
 		Path:                         "/api/v1/namespaces/default",
 		Timeout:                      15 * time.Second,
 		SampleInterval:               time.Second,
+		DegradedLatencyThreshold:     degradedLatencyThreshold,
 		EnableShutdownResponseHeader: true,
 	})
 }
 
-func createKubeAPIMonitoringWithConnectionReuseHTTP1(factory disruptionci.Factory, lb backend.LoadBalancerType) (disruptionci.Sampler, error) {
+func createKubeAPIMonitoringWithConnectionReuseHTTP1(factory disruptionci.Factory, lb backend.LoadBalancerType, degradedLatencyThreshold time.Duration) (disruptionci.Sampler, error) {
 	return factory.New(disruptionci.TestConfiguration{
 		TestDescriptor: disruptionci.TestDescriptor{
 			TargetServer:     disruptionci.KubeAPIServer,
@@ -139,11 +144,12 @@ func createKubeAPIMonitoringWithConnectionReuseHTTP1(factory disruptionci.Factor
 		Path:                         "/api/v1/namespaces/default",
 		Timeout:                      15 * time.Second,
 		SampleInterval:               time.Second,
+		DegradedLatencyThreshold:     degradedLatencyThreshold,
 		EnableShutdownResponseHeader: true,
 	})
 }
 
-func createOpenShiftAPIMonitoringWithNewConnectionsHTTP2(factory disruptionci.Factory, lb backend.LoadBalancerType) (disruptionci.Sampler, error) {
+func createOpenShiftAPIMonitoringWithNewConnectionsHTTP2(factory disruptionci.Factory, lb backend.LoadBalancerType, degradedLatencyThreshold time.Duration) (disruptionci.Sampler, error) {
 	return factory.New(disruptionci.TestConfiguration{
 		TestDescriptor: disruptionci.TestDescriptor{
 			TargetServer:     disruptionci.OpenShiftAPIServer,
@@ -154,11 +160,12 @@ func createOpenShiftAPIMonitoringWithNewConnectionsHTTP2(factory disruptionci.Fa
 		Path:                         "/apis/image.openshift.io/v1/namespaces/default/imagestreams",
 		Timeout:                      15 * time.Second,
 		SampleInterval:               time.Second,
+		DegradedLatencyThreshold:     degradedLatencyThreshold,
 		EnableShutdownResponseHeader: true,
 	})
 }
 
-func createOpenShiftAPIMonitoringWithConnectionReuseHTTP2(factory disruptionci.Factory, lb backend.LoadBalancerType) (disruptionci.Sampler, error) {
+func createOpenShiftAPIMonitoringWithConnectionReuseHTTP2(factory disruptionci.Factory, lb backend.LoadBalancerType, degradedLatencyThreshold time.Duration) (disruptionci.Sampler, error) {
 	return factory.New(disruptionci.TestConfiguration{
 		TestDescriptor: disruptionci.TestDescriptor{
 			TargetServer:     disruptionci.OpenShiftAPIServer,
@@ -169,6 +176,7 @@ func createOpenShiftAPIMonitoringWithConnectionReuseHTTP2(factory disruptionci.F
 		Path:                         "/apis/image.openshift.io/v1/namespaces/default/imagestreams",
 		Timeout:                      15 * time.Second,
 		SampleInterval:               time.Second,
+		DegradedLatencyThreshold:     degradedLatencyThreshold,
 		EnableShutdownResponseHeader: true,
 	})
 }
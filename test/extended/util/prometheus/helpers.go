@@ -15,6 +15,7 @@ import (
 
 	g "github.com/onsi/ginkgo/v2"
 	o "github.com/onsi/gomega"
+	"github.com/openshift/origin/pkg/exceptions"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	exutil "github.com/openshift/origin/test/extended/util"
 	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
@@ -161,6 +162,9 @@ type MetricCondition struct {
 
 	// Text is the description of why this alert condition matched.
 	Text string
+	// Exception is the owner, bug, and expiry of a condition allowed with a bug.  Conditions with an exception are
+	// reported when they expire or do not match during a run.
+	Exception *exceptions.Exception
 
 	Matches func(sample *model.Sample) bool
 }